@@ -98,7 +98,10 @@ type DB interface {
 	DoPrepare(link Link, sql string) (*Stmt, error)
 	DoInsert(link Link, table string, data interface{}, option int, batch ...int) (result sql.Result, err error)
 	DoBatchInsert(link Link, table string, list interface{}, option int, batch ...int) (result sql.Result, err error)
+	DoInsertWithOption(link Link, table string, data interface{}, option DoInsertOption) (result sql.Result, err error)
+	DoBatchInsertWithOption(link Link, table string, list interface{}, option DoInsertOption) (result sql.Result, err error)
 	DoUpdate(link Link, table string, data interface{}, condition string, args ...interface{}) (result sql.Result, err error)
+	DoBatchUpdate(link Link, table string, list List, keyField string, condition string, args ...interface{}) (result sql.Result, err error)
 	DoDelete(link Link, table string, condition string, args ...interface{}) (result sql.Result, err error)
 
 	// ===========================================================================
@@ -170,6 +173,8 @@ type DB interface {
 	TableFields(table string, schema ...string) (map[string]*TableField, error)
 	HasTable(name string) (bool, error)
 	FilteredLinkInfo() string
+	Stats() *Stats
+	ResetStats()
 
 	// HandleSqlBeforeCommit is a hook function, which deals with the sql string before
 	// it's committed to underlying driver. The parameter `link` specifies the current
@@ -177,12 +182,36 @@ type DB interface {
 	// arguments `args` as you wish before they're committed to driver.
 	HandleSqlBeforeCommit(link Link, sql string, args []interface{}) (string, []interface{})
 
+	// GetSavePointSql returns the sql statement of current database type for savepoint
+	// `operation` on savepoint named `point`, which is used by nested transaction feature.
+	// It returns an empty string if the operation is not supported by the database.
+	GetSavePointSql(operation int, point string) string
+
+	// FormatUpsert formats and returns the upsert statement of current database type for
+	// SAVE operation, which is appended to the INSERT statement of given `columns`.
+	FormatUpsert(columns []string, option DoInsertOption) (string, error)
+
+	// ===========================================================================
+	// DDL generating from struct.
+	// ===========================================================================
+
+	CreateTableSql(object interface{}, table ...string) ([]string, error)
+	DiffTableSql(object interface{}, table ...string) ([]string, error)
+
+	// FormatCreateTable formats and returns the DDL statements of current database type
+	// for creating table `table` with `columns`.
+	FormatCreateTable(table string, columns []*TableColumn) ([]string, error)
+
+	// FormatAddColumns formats and returns the DDL statements of current database type
+	// for adding `columns` to existing table `table`.
+	FormatAddColumns(table string, columns []*TableColumn) ([]string, error)
+
 	// ===========================================================================
 	// Internal methods, for internal usage purpose, you do not need consider it.
 	// ===========================================================================
 
 	mappingAndFilterData(schema, table string, data map[string]interface{}, filter bool) (map[string]interface{}, error)
-	convertFieldValueToLocalValue(fieldValue interface{}, fieldType string) interface{}
+	convertFieldValueToLocalValue(fieldValue interface{}, fieldType string) (interface{}, error)
 	convertRowsToResult(rows *sql.Rows) (Result, error)
 }
 
@@ -238,6 +267,17 @@ type Link interface {
 	PrepareContext(ctx context.Context, sql string) (*sql.Stmt, error)
 }
 
+// DoInsertOption is the input struct for function DoInsertWithOption/DoBatchInsertWithOption,
+// which carries the upsert options in addition to the insert option and batch count of DoInsert/DoBatchInsert.
+type DoInsertOption struct {
+	InsertOption   int                    // Insert operation: default/replace/save/ignore.
+	BatchCount     int                    // Batch count for batch inserting.
+	OnConflict     []string               // Conflict columns for upsert, which are required by databases like pgsql/sqlite.
+	OnDuplicateStr string                 // Custom updating statement for upsert, like: "count=count+1".
+	OnDuplicateMap map[string]interface{} // Custom updating fields for upsert, the value can be column name/Raw/Counter.
+	OnDuplicateEx  []string               // Excluded fields that are not updated for upsert.
+}
+
 // Counter  is the type for update count.
 type Counter struct {
 	Field string
@@ -276,11 +316,13 @@ var (
 
 	// driverMap manages all custom registered driver.
 	driverMap = map[string]Driver{
-		"mysql":  &DriverMysql{},
-		"mssql":  &DriverMssql{},
-		"pgsql":  &DriverPgsql{},
-		"oracle": &DriverOracle{},
-		"sqlite": &DriverSqlite{},
+		"mysql":      &DriverMysql{},
+		"mssql":      &DriverMssql{},
+		"pgsql":      &DriverPgsql{},
+		"oracle":     &DriverOracle{},
+		"sqlite":     &DriverSqlite{},
+		"clickhouse": &DriverClickhouse{},
+		"mock":       &DriverMock{},
 	}
 
 	// lastOperatorRegPattern is the regular expression pattern for a string
@@ -344,6 +386,8 @@ func New(group ...string) (db DB, err error) {
 				if err != nil {
 					return nil, err
 				}
+				// The caller holds the read lock of configs, see getGroupConfigNode.
+				c.startHealthCheck(configs.config[groupName][0].HealthCheckInterval)
 				return c.db, nil
 			} else {
 				return nil, gerror.New(fmt.Sprintf(`unsupported database type "%s"`, node.Type))
@@ -449,26 +493,116 @@ func getConfigNodeByWeight(cg ConfigGroup) *ConfigNode {
 // The parameter `master` specifies whether retrieves master node connection if
 // master-slave nodes are configured.
 func (c *Core) getSqlDb(master bool, schema ...string) (sqlDb *sql.DB, err error) {
+	// Read-your-writes, the reading operations are pinned to master after writing in the same context.
+	if !master && c.isWritten() {
+		master = true
+	}
+	// Changes the schema.
+	nodeSchema := c.schema.Val()
+	if len(schema) > 0 && schema[0] != "" {
+		nodeSchema = schema[0]
+	}
 	// Load balance.
-	node, err := getConfigNodeByGroup(c.group, master)
+	node, err := c.pickConfigNode(master, nodeSchema)
 	if err != nil {
 		return nil, err
 	}
+	return c.getSqlDbByNode(node, master)
+}
+
+// pickConfigNode picks and returns a healthy configuration node of current group using the configured
+// balancer strategy, with default charset and schema `schema` applied to the returned node.
+//
+// The slave nodes fall back to the master nodes if there's no slave node configured or all slave nodes are unhealthy.
+// All the nodes are treated as candidates if they are all unhealthy, in case that no node is available.
+func (c *Core) pickConfigNode(master bool, schema string) (*ConfigNode, error) {
+	configs.RLock()
+	list, ok := configs.config[c.group]
+	configs.RUnlock()
+	if !ok {
+		return nil, gerror.New(fmt.Sprintf("empty database configuration for item name '%s'", c.group))
+	}
+	// Separates master and slave configuration nodes array.
+	var (
+		masterList = make([]*ConfigNode, 0)
+		slaveList  = make([]*ConfigNode, 0)
+	)
+	for i := 0; i < len(list); i++ {
+		if list[i].Role == "slave" {
+			slaveList = append(slaveList, &list[i])
+		} else {
+			masterList = append(masterList, &list[i])
+		}
+	}
+	if len(masterList) < 1 {
+		return nil, gerror.New("at least one master node configuration's need to make sense")
+	}
+	var (
+		candidates = masterList
+		healthList = make([]*ConfigNode, 0)
+		filterFunc = func(nodes []*ConfigNode) {
+			for _, node := range nodes {
+				if isNodeHealthy(node) {
+					healthList = append(healthList, node)
+				}
+			}
+		}
+	)
+	if !master && len(slaveList) > 0 {
+		candidates = slaveList
+	}
+	filterFunc(candidates)
+	if len(healthList) == 0 && !master && len(slaveList) > 0 {
+		filterFunc(masterList)
+	}
+	if len(healthList) == 0 {
+		healthList = candidates
+	}
+	// The balancer strategy is the group-level configuration of the first node, see getGroupConfigNode.
+	balancer, err := getBalancer(list[0].Balancer)
+	if err != nil {
+		return nil, err
+	}
+	balancerNodes := make([]*BalancerNode, len(healthList))
+	for i, node := range healthList {
+		balancerNodes[i] = &BalancerNode{
+			ConfigNode: c.getConfigNodeWithSchema(node, schema),
+		}
+		// Statistics of the opened connection pool.
+		if v, _ := internalCache.Get(balancerNodes[i].String()); v != nil {
+			balancerNodes[i].Stats = v.(*sql.DB).Stats()
+		}
+	}
+	if len(balancerNodes) == 1 {
+		return balancerNodes[0].ConfigNode, nil
+	}
+	if picked := balancer.Pick(c.group, master, balancerNodes); picked != nil {
+		return picked.ConfigNode, nil
+	}
+	return balancerNodes[0].ConfigNode, nil
+}
+
+// getConfigNodeWithSchema returns a copy of `node` with default charset and schema `schema` applied.
+// The LinkInfo of the node is set to its mock name for mock driver, as the connection pool is cached by node.
+func (c *Core) getConfigNodeWithSchema(node *ConfigNode, schema string) *ConfigNode {
+	// Value copy.
+	n := *node
 	// Default value checks.
-	if node.Charset == "" {
-		node.Charset = "utf8"
+	if n.Charset == "" {
+		n.Charset = "utf8"
 	}
-	// Changes the schema.
-	nodeSchema := c.schema.Val()
-	if len(schema) > 0 && schema[0] != "" {
-		nodeSchema = schema[0]
+	if schema != "" {
+		n.Name = schema
 	}
-	if nodeSchema != "" {
-		// Value copy.
-		n := *node
-		n.Name = nodeSchema
-		node = &n
+	if _, ok := c.db.(mockDriver); ok {
+		n.LinkInfo = getMockName(c.group, &n)
 	}
+	return &n
+}
+
+// getSqlDbByNode retrieves and returns the underlying database connection object of `node`,
+// which is cached by node.
+func (c *Core) getSqlDbByNode(node *ConfigNode, master bool) (sqlDb *sql.DB, err error) {
 	// Cache the underlying connection pool object by node.
 	v, _ := internalCache.GetOrSetFuncLock(node.String(), func() (interface{}, error) {
 		sqlDb, err = c.db.Open(node)
@@ -8,16 +8,20 @@
 package gdb
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
 	"github.com/gogf/gf/errors/gerror"
 	"github.com/gogf/gf/text/gstr"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/gogf/gf/internal/utils"
 
+	"github.com/gogf/gf/container/gset"
 	"github.com/gogf/gf/container/gvar"
 	"github.com/gogf/gf/os/gtime"
 	"github.com/gogf/gf/text/gregex"
@@ -107,6 +111,10 @@ func (c *Core) Query(sql string, args ...interface{}) (rows *sql.Rows, err error
 // DoQuery commits the sql string and its arguments to underlying driver
 // through given link object and returns the execution result.
 func (c *Core) DoQuery(link Link, sql string, args ...interface{}) (rows *sql.Rows, err error) {
+	// The failed sub-query model is not committed without its conditions.
+	if err = getSubQueryError(args...); err != nil {
+		return nil, err
+	}
 	sql, args = formatSql(sql, args)
 	sql, args = c.db.HandleSqlBeforeCommit(link, sql, args)
 	ctx := c.db.GetCtx()
@@ -114,9 +122,15 @@ func (c *Core) DoQuery(link Link, sql string, args ...interface{}) (rows *sql.Ro
 		ctx, _ = context.WithTimeout(ctx, c.GetConfig().QueryTimeout)
 	}
 
-	mTime1 := gtime.TimestampMilli()
+	var (
+		startTime = time.Now()
+		mTime1    = gtime.TimestampMilli()
+	)
 	rows, err = link.QueryContext(ctx, sql, args...)
-	mTime2 := gtime.TimestampMilli()
+	var (
+		latency = time.Since(startTime)
+		mTime2  = gtime.TimestampMilli()
+	)
 	sqlObj := &Sql{
 		Sql:    sql,
 		Type:   "DB.QueryContext",
@@ -128,6 +142,7 @@ func (c *Core) DoQuery(link Link, sql string, args ...interface{}) (rows *sql.Ro
 		Group:  c.db.GetGroup(),
 	}
 	c.addSqlToTracing(ctx, sqlObj)
+	c.addSqlToStats(sqlObj, nil, latency)
 	if c.db.GetDebug() {
 		c.writeSqlToLogger(sqlObj)
 	}
@@ -152,6 +167,10 @@ func (c *Core) Exec(sql string, args ...interface{}) (result sql.Result, err err
 // DoExec commits the sql string and its arguments to underlying driver
 // through given link object and returns the execution result.
 func (c *Core) DoExec(link Link, sql string, args ...interface{}) (result sql.Result, err error) {
+	// The failed sub-query model is not committed without its conditions.
+	if err = getSubQueryError(args...); err != nil {
+		return nil, err
+	}
 	sql, args = formatSql(sql, args)
 	sql, args = c.db.HandleSqlBeforeCommit(link, sql, args)
 	ctx := c.db.GetCtx()
@@ -161,13 +180,22 @@ func (c *Core) DoExec(link Link, sql string, args ...interface{}) (result sql.Re
 		defer cancelFunc()
 	}
 
-	mTime1 := gtime.TimestampMilli()
+	var (
+		startTime = time.Now()
+		mTime1    = gtime.TimestampMilli()
+	)
 	if !c.db.GetDryRun() {
 		result, err = link.ExecContext(ctx, sql, args...)
+		if err == nil {
+			c.markWritten(ctx)
+		}
 	} else {
 		result = new(SqlResult)
 	}
-	mTime2 := gtime.TimestampMilli()
+	var (
+		latency = time.Since(startTime)
+		mTime2  = gtime.TimestampMilli()
+	)
 	sqlObj := &Sql{
 		Sql:    sql,
 		Type:   "DB.ExecContext",
@@ -179,6 +207,7 @@ func (c *Core) DoExec(link Link, sql string, args ...interface{}) (result sql.Re
 		Group:  c.db.GetGroup(),
 	}
 	c.addSqlToTracing(ctx, sqlObj)
+	c.addSqlToStats(sqlObj, result, latency)
 	if c.db.GetDebug() {
 		c.writeSqlToLogger(sqlObj)
 	}
@@ -218,8 +247,10 @@ func (c *Core) DoPrepare(link Link, sql string) (*Stmt, error) {
 		ctx, _ = context.WithTimeout(ctx, c.GetConfig().PrepareTimeout)
 	}
 	var (
+		startTime = time.Now()
 		mTime1    = gtime.TimestampMilli()
 		stmt, err = link.PrepareContext(ctx, sql)
+		latency   = time.Since(startTime)
 		mTime2    = gtime.TimestampMilli()
 		sqlObj    = &Sql{
 			Sql:    sql,
@@ -233,6 +264,7 @@ func (c *Core) DoPrepare(link Link, sql string) (*Stmt, error) {
 		}
 	)
 	c.addSqlToTracing(ctx, sqlObj)
+	c.addSqlToStats(sqlObj, nil, latency)
 	if c.db.GetDebug() {
 		c.writeSqlToLogger(sqlObj)
 	}
@@ -388,12 +420,14 @@ func (c *Core) Begin() (*TX, error) {
 			ctx, cancelFunc = context.WithTimeout(ctx, c.GetConfig().TranTimeout)
 			defer cancelFunc()
 		}
-		if tx, err := master.BeginTx(ctx, nil); err == nil {
-			return &TX{
+		if sqlTx, err := master.BeginTx(ctx, nil); err == nil {
+			tx := &TX{
 				db:     c.db,
-				tx:     tx,
+				tx:     sqlTx,
 				master: master,
-			}, nil
+			}
+			tx.ctx = WithTX(c.db.GetCtx(), tx)
+			return tx, nil
 		} else {
 			return nil, err
 		}
@@ -405,9 +439,16 @@ func (c *Core) Begin() (*TX, error) {
 // it returns non-nil error. It commits the transaction and returns nil if
 // function `f` returns nil.
 //
+// If the context of current DB already carries a transaction of the same configuration
+// group, which is injected by WithTX or comes from TX.GetCtx, it does not start a new
+// transaction but a nested one using savepoint. See TX.Transaction.
+//
 // Note that, you should not Commit or Rollback the transaction in function `f`
 // as it is automatically handled by this function.
 func (c *Core) Transaction(f func(tx *TX) error) (err error) {
+	if tx := TXFromCtx(c.db.GetCtx(), c.db.GetGroup()); tx != nil {
+		return tx.Transaction(f)
+	}
 	var tx *TX
 	tx, err = c.db.Begin()
 	if err != nil {
@@ -515,6 +556,43 @@ func (c *Core) Save(table string, data interface{}, batch ...int) (sql.Result, e
 // 2: save:    if there's unique/primary key in the data, it updates it or else inserts a new one;
 // 3: ignore:  if there's unique/primary key in the data, it ignores the inserting;
 func (c *Core) DoInsert(link Link, table string, data interface{}, option int, batch ...int) (result sql.Result, err error) {
+	return c.db.DoInsertWithOption(link, table, data, newDoInsertOption(option, batch...))
+}
+
+// newDoInsertOption creates and returns the DoInsertOption of insert option `option` and batch count `batch`,
+// which has no upsert options.
+func newDoInsertOption(option int, batch ...int) DoInsertOption {
+	doInsertOption := DoInsertOption{
+		InsertOption: option,
+	}
+	if len(batch) > 0 {
+		doInsertOption.BatchCount = batch[0]
+	}
+	return doInsertOption
+}
+
+// hasUpsert checks and returns whether the option has any upsert option.
+func (option DoInsertOption) hasUpsert() bool {
+	return len(option.OnConflict) > 0 || option.OnDuplicateStr != "" ||
+		len(option.OnDuplicateMap) > 0 || len(option.OnDuplicateEx) > 0
+}
+
+// upsertConflictRequired is implemented by the drivers which require conflict columns for upsert,
+// like pgsql/sqlite. The primary keys are used as the conflict columns if they're not set.
+type upsertConflictRequired interface {
+	requireUpsertConflict()
+}
+
+// DoInsertWithOption acts like DoInsert, but with the upsert options of parameter `option`
+// for save operation, see DoInsertOption.
+// This function is usually used for custom interface definition, you do not need call it manually.
+//
+// The attribute `InsertOption` of parameter `option` values are as follows:
+// 0: insert:  just insert, if there's unique/primary key in the data, it returns error;
+// 1: replace: if there's unique/primary key in the data, it deletes it from table and inserts a new one;
+// 2: save:    if there's unique/primary key in the data, it updates it or else inserts a new one;
+// 3: ignore:  if there's unique/primary key in the data, it ignores the inserting;
+func (c *Core) DoInsertWithOption(link Link, table string, data interface{}, option DoInsertOption) (result sql.Result, err error) {
 	table = c.db.QuotePrefixTableName(table)
 	var (
 		fields       []string
@@ -530,10 +608,10 @@ func (c *Core) DoInsert(link Link, table string, data interface{}, option int, b
 	}
 	switch reflectKind {
 	case reflect.Slice, reflect.Array:
-		return c.db.DoBatchInsert(link, table, data, option, batch...)
+		return c.db.DoBatchInsertWithOption(link, table, data, option)
 	case reflect.Struct:
 		if _, ok := data.(apiInterfaces); ok {
-			return c.db.DoBatchInsert(link, table, data, option, batch...)
+			return c.db.DoBatchInsertWithOption(link, table, data, option)
 		} else {
 			dataMap = ConvertDataForTableRecord(data)
 		}
@@ -547,10 +625,17 @@ func (c *Core) DoInsert(link Link, table string, data interface{}, option int, b
 	}
 	var (
 		charL, charR = c.db.GetChars()
-		operation    = GetInsertOperationByOption(option)
+		operation    = GetInsertOperationByOption(option.InsertOption)
+		keys         = make([]string, 0, len(dataMap))
 		updateStr    = ""
 	)
-	for k, v := range dataMap {
+	for k := range dataMap {
+		keys = append(keys, k)
+	}
+	// The map is unordered, so the fields are sorted for a stable statement.
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := dataMap[k]
 		fields = append(fields, charL+k+charR)
 		if s, ok := v.(Raw); ok {
 			values = append(values, gconv.String(s))
@@ -559,23 +644,10 @@ func (c *Core) DoInsert(link Link, table string, data interface{}, option int, b
 			params = append(params, v)
 		}
 	}
-	if option == insertOptionSave {
-		for k, _ := range dataMap {
-			// If it's SAVE operation,
-			// do not automatically update the creating time.
-			if c.isSoftCreatedFiledName(k) {
-				continue
-			}
-			if len(updateStr) > 0 {
-				updateStr += ","
-			}
-			updateStr += fmt.Sprintf(
-				"%s%s%s=VALUES(%s%s%s)",
-				charL, k, charR,
-				charL, k, charR,
-			)
+	if option.InsertOption == insertOptionSave {
+		if updateStr, err = c.db.FormatUpsert(keys, option); err != nil {
+			return nil, err
 		}
-		updateStr = fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", updateStr)
 	}
 	if link == nil {
 		if link, err = c.db.Master(); err != nil {
@@ -593,6 +665,95 @@ func (c *Core) DoInsert(link Link, table string, data interface{}, option int, b
 	)
 }
 
+// FormatUpsert formats and returns the upsert statement for SAVE operation, which is appended
+// to the INSERT statement of given `columns`, like: "ON DUPLICATE KEY UPDATE ...".
+// It uses the MySQL grammar by default, and drivers of other databases can overwrite it.
+func (c *Core) FormatUpsert(columns []string, option DoInsertOption) (string, error) {
+	updateStr, err := c.formatUpsertUpdates(columns, option, func(column string) string {
+		return fmt.Sprintf(`VALUES(%s)`, c.db.QuoteWord(column))
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", updateStr), nil
+}
+
+// formatUpsertOnConflict formats and returns the "ON CONFLICT (...) DO UPDATE SET ..." upsert statement,
+// which is used by databases like PostgreSQL and SQLite that require explicit conflict columns.
+func (c *Core) formatUpsertOnConflict(columns []string, option DoInsertOption) (string, error) {
+	if len(option.OnConflict) == 0 {
+		return "", gerror.New(`conflict columns cannot be empty for upsert, please specify them using Model.OnConflict`)
+	}
+	updateStr, err := c.formatUpsertUpdates(columns, option, func(column string) string {
+		return fmt.Sprintf(`EXCLUDED.%s`, c.db.QuoteWord(column))
+	})
+	if err != nil {
+		return "", err
+	}
+	conflictColumns := make([]string, len(option.OnConflict))
+	for i, column := range option.OnConflict {
+		conflictColumns[i] = c.db.QuoteWord(column)
+	}
+	return fmt.Sprintf(
+		"ON CONFLICT (%s) DO UPDATE SET %s",
+		strings.Join(conflictColumns, ","), updateStr,
+	), nil
+}
+
+// formatUpsertUpdates formats and returns the updating part of upsert statement, like: "a=VALUES(a),b=b+1".
+// The parameter `valueFunc` returns the expression referring to the inserting value of given column.
+//
+// It uses option.OnDuplicateStr directly if it's given, or else it updates the fields in option.OnDuplicateMap,
+// or else it updates all `columns` except the excluded fields by option.OnDuplicateEx and the soft creating field.
+func (c *Core) formatUpsertUpdates(columns []string, option DoInsertOption, valueFunc func(column string) string) (string, error) {
+	if option.OnDuplicateStr != "" {
+		return option.OnDuplicateStr, nil
+	}
+	var updates []string
+	if len(option.OnDuplicateMap) > 0 {
+		keys := make([]string, 0, len(option.OnDuplicateMap))
+		for k := range option.OnDuplicateMap {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			var (
+				v         = option.OnDuplicateMap[k]
+				quotedKey = c.db.QuoteWord(k)
+			)
+			switch value := v.(type) {
+			case Raw:
+				updates = append(updates, fmt.Sprintf(`%s=%s`, quotedKey, value))
+			case *Counter:
+				updates = append(updates, fmt.Sprintf(
+					`%s=%s+%s`, quotedKey, c.db.QuoteWord(value.Field), gconv.String(value.Value),
+				))
+			case Counter:
+				updates = append(updates, fmt.Sprintf(
+					`%s=%s+%s`, quotedKey, c.db.QuoteWord(value.Field), gconv.String(value.Value),
+				))
+			default:
+				// The value is the column name whose inserting value is used for updating.
+				updates = append(updates, fmt.Sprintf(`%s=%s`, quotedKey, valueFunc(gconv.String(v))))
+			}
+		}
+	} else {
+		excludedSet := gset.NewStrSetFrom(option.OnDuplicateEx)
+		for _, column := range columns {
+			// If it's SAVE operation,
+			// do not automatically update the creating time.
+			if c.isSoftCreatedFiledName(column) || excludedSet.Contains(column) {
+				continue
+			}
+			updates = append(updates, fmt.Sprintf(`%s=%s`, c.db.QuoteWord(column), valueFunc(column)))
+		}
+	}
+	if len(updates) == 0 {
+		return "", gerror.New(`there are no fields to update for upsert`)
+	}
+	return strings.Join(updates, ","), nil
+}
+
 // BatchInsert batch inserts data.
 // The parameter `list` must be type of slice of map or struct.
 func (c *Core) BatchInsert(table string, list interface{}, batch ...int) (sql.Result, error) {
@@ -632,6 +793,13 @@ func (c *Core) BatchSave(table string, list interface{}, batch ...int) (sql.Resu
 // DoBatchInsert batch inserts/replaces/saves data.
 // This function is usually used for custom interface definition, you do not need call it manually.
 func (c *Core) DoBatchInsert(link Link, table string, list interface{}, option int, batch ...int) (result sql.Result, err error) {
+	return c.db.DoBatchInsertWithOption(link, table, list, newDoInsertOption(option, batch...))
+}
+
+// DoBatchInsertWithOption acts like DoBatchInsert, but with the upsert options of parameter `option`
+// for save operation, see DoInsertOption.
+// This function is usually used for custom interface definition, you do not need call it manually.
+func (c *Core) DoBatchInsertWithOption(link Link, table string, list interface{}, option DoInsertOption) (result sql.Result, err error) {
 	table = c.db.QuotePrefixTableName(table)
 	var (
 		keys    []string      // Field names.
@@ -695,35 +863,23 @@ func (c *Core) DoBatchInsert(link Link, table string, list interface{}, option i
 	for k, _ := range listMap[0] {
 		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 	// Prepare the batch result pointer.
 	var (
 		charL, charR = c.db.GetChars()
 		batchResult  = new(SqlResult)
 		keysStr      = charL + strings.Join(keys, charR+","+charL) + charR
-		operation    = GetInsertOperationByOption(option)
+		operation    = GetInsertOperationByOption(option.InsertOption)
 		updateStr    = ""
 	)
-	if option == insertOptionSave {
-		for _, k := range keys {
-			// If it's SAVE operation,
-			// do not automatically update the creating time.
-			if c.isSoftCreatedFiledName(k) {
-				continue
-			}
-			if len(updateStr) > 0 {
-				updateStr += ","
-			}
-			updateStr += fmt.Sprintf(
-				"%s%s%s=VALUES(%s%s%s)",
-				charL, k, charR,
-				charL, k, charR,
-			)
+	if option.InsertOption == insertOptionSave {
+		if updateStr, err = c.db.FormatUpsert(keys, option); err != nil {
+			return nil, err
 		}
-		updateStr = fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", updateStr)
 	}
 	batchNum := defaultBatchNumber
-	if len(batch) > 0 && batch[0] > 0 {
-		batchNum = batch[0]
+	if option.BatchCount > 0 {
+		batchNum = option.BatchCount
 	}
 	var (
 		listMapLen  = len(listMap)
@@ -856,6 +1012,81 @@ func (c *Core) DoUpdate(link Link, table string, data interface{}, condition str
 	)
 }
 
+// DoBatchUpdate updates the records of `list` in single statement, which are identified by `keyField`,
+// using "UPDATE ... SET field=CASE key WHEN ... THEN ... ELSE field END ..." statement.
+// The fields that are missing in some records keep their original values for these records.
+// The parameter `condition` should contain the restriction of keys like "WHERE key IN(...)".
+// This function is usually used for custom interface definition, you do not need call it manually.
+func (c *Core) DoBatchUpdate(link Link, table string, list List, keyField string, condition string, args ...interface{}) (result sql.Result, err error) {
+	table = c.db.QuotePrefixTableName(table)
+	var (
+		fields  = getBatchUpdateFields(list, keyField)
+		updates = make([]string, 0, len(fields))
+		params  = make([]interface{}, 0)
+		column  = c.db.QuoteWord(keyField)
+	)
+	if len(fields) == 0 {
+		return nil, gerror.New("data cannot be empty")
+	}
+	for _, field := range fields {
+		var (
+			quotedField = c.db.QuoteWord(field)
+			buffer      = bytes.NewBuffer(nil)
+		)
+		buffer.WriteString(fmt.Sprintf("%s=CASE %s", quotedField, column))
+		for _, item := range list {
+			value, ok := item[field]
+			if !ok {
+				continue
+			}
+			params = append(params, item[keyField])
+			switch v := value.(type) {
+			case Raw:
+				buffer.WriteString(" WHEN ? THEN " + gconv.String(v))
+			case Counter:
+				buffer.WriteString(fmt.Sprintf(" WHEN ? THEN %s+?", quotedField))
+				params = append(params, v.Value)
+			case *Counter:
+				buffer.WriteString(fmt.Sprintf(" WHEN ? THEN %s+?", quotedField))
+				params = append(params, v.Value)
+			default:
+				buffer.WriteString(" WHEN ? THEN ?")
+				params = append(params, value)
+			}
+		}
+		buffer.WriteString(fmt.Sprintf(" ELSE %s END", quotedField))
+		updates = append(updates, buffer.String())
+	}
+	if link == nil {
+		if link, err = c.db.Master(); err != nil {
+			return nil, err
+		}
+	}
+	return c.db.DoExec(
+		link,
+		fmt.Sprintf("UPDATE %s SET %s%s", table, strings.Join(updates, ","), condition),
+		append(params, args...)...,
+	)
+}
+
+// getBatchUpdateFields returns the sorted updating fields of `list` excluding `keyField`.
+func getBatchUpdateFields(list List, keyField string) []string {
+	fieldSet := make(map[string]struct{})
+	for _, item := range list {
+		for k := range item {
+			if k != keyField {
+				fieldSet[k] = struct{}{}
+			}
+		}
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for k := range fieldSet {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
 // Delete does "DELETE FROM ... " statement for the table.
 //
 // The parameter `condition` can be type of string/map/gmap/slice/struct/*struct, etc.
@@ -916,7 +1147,11 @@ func (c *Core) convertRowsToResult(rows *sql.Rows) (Result, error) {
 			if value == nil {
 				row[columnNames[i]] = gvar.New(nil)
 			} else {
-				row[columnNames[i]] = gvar.New(c.db.convertFieldValueToLocalValue(value, columnTypes[i]))
+				localValue, err := c.db.convertFieldValueToLocalValue(value, columnTypes[i])
+				if err != nil {
+					return records, err
+				}
+				row[columnNames[i]] = gvar.New(localValue)
 			}
 		}
 		records = append(records, row)
@@ -0,0 +1,145 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/gogf/gf/errors/gerror"
+)
+
+// Balancer is the interface for load balance strategy of the configuration nodes,
+// which picks one node from the healthy nodes of the same role in a configuration group.
+// The custom strategy can be registered by RegisterBalancer and used by configuration Balancer.
+type Balancer interface {
+	// Pick picks and returns one node from `nodes`.
+	// The parameter `group` is the configuration group name and `master` specifies the role of `nodes`,
+	// which can be used for maintaining states of different node lists.
+	Pick(group string, master bool, nodes []*BalancerNode) *BalancerNode
+}
+
+// BalancerNode is the candidate node for Balancer.
+type BalancerNode struct {
+	*ConfigNode             // Configuration of the node.
+	Stats       sql.DBStats // Statistics of the connection pool of the node, which is empty if it's not opened yet.
+}
+
+const (
+	BalancerRandom     = "random"     // Weighted random strategy, which is the default strategy.
+	BalancerRoundRobin = "roundrobin" // Smooth weighted round-robin strategy.
+	BalancerLeastConn  = "leastconn"  // Least connections strategy, which picks the node having the least in-use connections per weight.
+)
+
+var (
+	// balancerMap manages all the balancer strategies by name.
+	balancerMap = map[string]Balancer{
+		BalancerRandom:     &balancerRandom{},
+		BalancerRoundRobin: &balancerRoundRobin{weights: make(map[string]int)},
+		BalancerLeastConn:  &balancerLeastConn{},
+	}
+	// balancerMu protects balancerMap for concurrent safety.
+	balancerMu sync.RWMutex
+)
+
+// RegisterBalancer registers custom balancer strategy with `name`,
+// which can be then used by configuration Balancer of ConfigNode.
+// It overwrites the builtin strategy if `name` is the same as the builtin one.
+func RegisterBalancer(name string, balancer Balancer) {
+	balancerMu.Lock()
+	defer balancerMu.Unlock()
+	balancerMap[name] = balancer
+}
+
+// getBalancer returns the balancer strategy of given `name`.
+// It returns the default weighted random strategy if `name` is empty.
+func getBalancer(name string) (Balancer, error) {
+	if name == "" {
+		name = BalancerRandom
+	}
+	balancerMu.RLock()
+	defer balancerMu.RUnlock()
+	if balancer, ok := balancerMap[name]; ok {
+		return balancer, nil
+	}
+	return nil, gerror.Newf(`balancer strategy "%s" is not found`, name)
+}
+
+// getBalancerNodeWeight returns the weight of the node, which is 1 if it's not configured.
+func getBalancerNodeWeight(node *BalancerNode) int {
+	if node.Weight > 0 {
+		return node.Weight
+	}
+	return 1
+}
+
+// balancerRandom is the weighted random strategy.
+type balancerRandom struct{}
+
+// Pick implements interface Balancer using weighted random strategy, see getConfigNodeByWeight.
+func (b *balancerRandom) Pick(group string, master bool, nodes []*BalancerNode) *BalancerNode {
+	cg := make(ConfigGroup, len(nodes))
+	for i, node := range nodes {
+		cg[i] = *node.ConfigNode
+		cg[i].Weight = getBalancerNodeWeight(node)
+	}
+	picked := getConfigNodeByWeight(cg)
+	for i := range cg {
+		if &cg[i] == picked {
+			return nodes[i]
+		}
+	}
+	return nodes[0]
+}
+
+// balancerRoundRobin is the smooth weighted round-robin strategy, which is the same as nginx.
+type balancerRoundRobin struct {
+	mu      sync.Mutex
+	weights map[string]int // Current weights of nodes.
+}
+
+// Pick implements interface Balancer using smooth weighted round-robin strategy.
+// In each picking, every node increases its current weight by its configured weight,
+// and the node having the max current weight is picked and then decreases its current
+// weight by the total weight.
+func (b *balancerRoundRobin) Pick(group string, master bool, nodes []*BalancerNode) *BalancerNode {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var (
+		total  = 0
+		picked = -1
+		keys   = make([]string, len(nodes))
+	)
+	for i, node := range nodes {
+		weight := getBalancerNodeWeight(node)
+		keys[i] = group + "@" + node.String()
+		b.weights[keys[i]] += weight
+		total += weight
+		if picked == -1 || b.weights[keys[i]] > b.weights[keys[picked]] {
+			picked = i
+		}
+	}
+	b.weights[keys[picked]] -= total
+	return nodes[picked]
+}
+
+// balancerLeastConn is the least connections strategy.
+type balancerLeastConn struct{}
+
+// Pick implements interface Balancer using least connections strategy,
+// which picks the node having the least in-use connections per weight.
+// The first node is picked if they have the same value.
+func (b *balancerLeastConn) Pick(group string, master bool, nodes []*BalancerNode) *BalancerNode {
+	picked := nodes[0]
+	for _, node := range nodes[1:] {
+		// Compares node.InUse/node.Weight < picked.InUse/picked.Weight without division.
+		if node.Stats.InUse*getBalancerNodeWeight(picked) < picked.Stats.InUse*getBalancerNodeWeight(node) {
+			picked = node
+		}
+	}
+	return picked
+}
@@ -32,7 +32,7 @@ type ConfigNode struct {
 	User                 string        `json:"user"`                 // Authentication username.
 	Pass                 string        `json:"pass"`                 // Authentication password.
 	Name                 string        `json:"name"`                 // Default used database name.
-	Type                 string        `json:"type"`                 // Database type: mysql, sqlite, mssql, pgsql, oracle.
+	Type                 string        `json:"type"`                 // Database type: mysql, sqlite, mssql, pgsql, oracle, clickhouse.
 	Role                 string        `json:"role"`                 // (Optional, "master" in default) Node role, used for master-slave mode: master, slave.
 	Debug                bool          `json:"debug"`                // (Optional) Debug mode enables debug information logging and output.
 	Prefix               string        `json:"prefix"`               // (Optional) Table prefix.
@@ -49,8 +49,15 @@ type ConfigNode struct {
 	PrepareTimeout       time.Duration `json:"prepareTimeout"`       // (Optional) Max exec time time for prepare operation.
 	CreatedAt            string        `json:"createdAt"`            // (Optional) The filed name of table for automatic-filled created datetime.
 	UpdatedAt            string        `json:"updatedAt"`            // (Optional) The filed name of table for automatic-filled updated datetime.
-	DeletedAt            string        `json:"deletedAt"`            // (Optional) The filed name of table for automatic-filled updated datetime.
+	DeletedAt            string        `json:"deletedAt"`            // (Optional) The filed name of table for automatic-filled deleted datetime or flag.
 	TimeMaintainDisabled bool          `json:"timeMaintainDisabled"` // (Optional) Disable the automatic time maintaining feature.
+	SoftTimeType         string        `json:"softTimeType"`         // (Optional) Value type of created/updated/deleted fields: datetime, timestamp, flag, which is detected from field type in default.
+	VersionAt            string        `json:"versionAt"`            // (Optional) The field name of table for optimistic locking version, which is "version" or "revision" in default.
+	Balancer             string        `json:"balancer"`             // (Optional, "random" in default) Load balance strategy of the group: random, roundrobin, leastconn, or custom registered one. It is read from the first node of the group.
+	HealthCheckInterval  time.Duration `json:"healthCheckInterval"`  // (Optional) Interval of health probes of the group nodes, the failed nodes are taken out of rotation. It is read from the first node of the group.
+	ReadYourWrites       time.Duration `json:"readYourWrites"`       // (Optional) Duration that pins the reading operations to master after writing in the same context, see WithReadYourWrites. It is read from the first node of the group.
+	SlowThreshold        time.Duration `json:"slowThreshold"`        // (Optional) Statements taking longer than the threshold are logged as slow sql with their caller, like: 200ms, 1s, or number in milliseconds in configuration file, like: 200.
+	StatsEnabled         bool          `json:"statsEnabled"`         // (Optional) Enables the per-statement statistics of DB.Stats, which is disabled in default.
 }
 
 // configs is internal used configuration object.
@@ -170,6 +177,20 @@ func (node *ConfigNode) String() string {
 	)
 }
 
+// getGroupConfigNode returns a copy of the first configuration node of current group, from which
+// the group-level configurations are read: Balancer, HealthCheckInterval and ReadYourWrites,
+// as these configurations apply to the whole group rather than the node in use.
+// It returns nil if the group is not configured.
+func (c *Core) getGroupConfigNode() *ConfigNode {
+	configs.RLock()
+	defer configs.RUnlock()
+	if list := configs.config[c.group]; len(list) > 0 {
+		node := list[0]
+		return &node
+	}
+	return nil
+}
+
 // GetConfig returns the current used node configuration.
 func (c *Core) GetConfig() *ConfigNode {
 	return c.config
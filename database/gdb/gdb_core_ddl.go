@@ -0,0 +1,587 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gconv"
+	"github.com/gogf/gf/util/gmeta"
+)
+
+// TableColumn is the column definition for DDL generating, which is parsed from the struct field
+// and its "orm" tag. The first item of the tag is the column name, and the others are its attributes:
+// type:     custom column type, like: type:decimal(10,2). It's converted from the Go type by driver if not given.
+// size:     column size, like: size:45, which is the length for string type, eg: varchar(45).
+// primary:  the column is (part of) the primary key.
+// autoincrement: the column is auto increment.
+// index:    the column has an index, with optional index name, like: index, index:idx_name.
+// unique:   the column has an unique index, with optional index name, like: unique, unique:uk_name.
+// default:  default value for the column, like: default:0.
+// comment:  comment for the column, like: comment:User Name.
+//
+// Eg:
+// Id   uint   `orm:"id,primary,autoincrement,comment:User ID"`
+// Name string `orm:"name,size:45,unique,comment:User Name"`
+// Age  int    `orm:"age,index:idx_age_score,default:18"`
+//
+// The columns using the same index name make up a composite index.
+type TableColumn struct {
+	Name          string       // Column name.
+	GoType        reflect.Type // Go type of the struct field, which is used for column type converting.
+	Type          string       // Custom column type from tag "type".
+	Size          int          // Column size from tag "size".
+	Primary       bool         // The column is (part of) the primary key.
+	AutoIncrement bool         // The column is auto increment.
+	Null          bool         // The column can be null, which is true for non-primary columns.
+	Default       interface{}  // Default value for the column, it's nil if no default value.
+	Comment       string       // Comment for the column.
+	Index         string       // Index name if the column has index.
+	Unique        string       // Unique index name if the column has unique index.
+}
+
+// ddlDialect holds the database specific grammar for DDL generating.
+type ddlDialect struct {
+	columnType           func(column *TableColumn) string // Converts the Go type of column to database column type.
+	autoIncrement        string                           // Keyword for auto increment column, like: AUTO_INCREMENT.
+	autoIncrementPrimary bool                             // The auto increment column should be defined as inline primary key.
+	inlineComment        bool                             // Column comment is defined inline, like: COMMENT '...'.
+	commentOnColumn      bool                             // Column comment is defined by: COMMENT ON COLUMN t.c IS '...'.
+	addColumnFormat      string                           // Format of adding column statement, like: ALTER TABLE %s ADD COLUMN %s.
+}
+
+const (
+	OrmTagForType          = "type"
+	OrmTagForSize          = "size"
+	OrmTagForAutoIncrement = "autoincrement"
+	OrmTagForIndex         = "index"
+	OrmTagForDefault       = "default"
+	OrmTagForComment       = "comment"
+
+	ddlDefaultStringSize = 255
+
+	// Go type categories for column type converting.
+	ddlGoTypeBool    = "bool"
+	ddlGoTypeInt8    = "int8"
+	ddlGoTypeInt16   = "int16"
+	ddlGoTypeInt32   = "int32"
+	ddlGoTypeInt64   = "int64"
+	ddlGoTypeFloat32 = "float32"
+	ddlGoTypeFloat64 = "float64"
+	ddlGoTypeString  = "string"
+	ddlGoTypeBytes   = "bytes"
+	ddlGoTypeTime    = "time"
+	ddlGoTypeJson    = "json"
+)
+
+var (
+	// mysqlDDLDialect is the DDL grammar of mysql, which is also the default DDL grammar.
+	mysqlDDLDialect = &ddlDialect{
+		columnType:      mysqlColumnType,
+		autoIncrement:   "AUTO_INCREMENT",
+		inlineComment:   true,
+		addColumnFormat: "ALTER TABLE %s ADD COLUMN %s",
+	}
+)
+
+// CreateTableSql generates and returns the DDL statements for creating table from the struct `object`
+// and its "orm" tags, which contain the "CREATE TABLE" statement and its "CREATE INDEX" statements.
+// The optional parameter `table` specifies the table name, or else the table name is retrieved
+// from the struct, see Model.With for the table name retrieving of struct.
+// See TableColumn for the "orm" tag definition.
+func (c *Core) CreateTableSql(object interface{}, table ...string) ([]string, error) {
+	tableName, columns, err := c.getTableColumnsFromStruct(object, table...)
+	if err != nil {
+		return nil, err
+	}
+	return c.db.FormatCreateTable(tableName, columns)
+}
+
+// DiffTableSql compares the struct `object` with the fields of its table retrieved by TableFields,
+// and returns the DDL statements for the struct columns that do not exist in the table,
+// which contain the "ALTER TABLE ... ADD COLUMN" statements and the index statements of new columns.
+// It returns the statements of CreateTableSql if the table does not exist.
+//
+// Note that it does not modify or drop any existing columns of the table,
+// and the fields of table are cached by TableFields until the process restarts.
+func (c *Core) DiffTableSql(object interface{}, table ...string) ([]string, error) {
+	tableName, columns, err := c.getTableColumnsFromStruct(object, table...)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := c.db.HasTable(c.db.GetPrefix() + tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return c.db.FormatCreateTable(tableName, columns)
+	}
+	tableFields, err := c.db.TableFields(tableName)
+	if err != nil {
+		return nil, err
+	}
+	newColumns := make([]*TableColumn, 0)
+	for _, column := range columns {
+		found := false
+		for fieldName := range tableFields {
+			if strings.EqualFold(fieldName, column.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			newColumns = append(newColumns, column)
+		}
+	}
+	if len(newColumns) == 0 {
+		return []string{}, nil
+	}
+	return c.db.FormatAddColumns(tableName, newColumns)
+}
+
+// FormatCreateTable formats and returns the DDL statements for creating table `table` with `columns`.
+// It uses the mysql grammar in default, and drivers of other databases can overwrite it.
+func (c *Core) FormatCreateTable(table string, columns []*TableColumn) ([]string, error) {
+	return c.doFormatCreateTable(table, columns, mysqlDDLDialect)
+}
+
+// FormatAddColumns formats and returns the DDL statements for adding `columns` to existing table `table`.
+// It uses the mysql grammar in default, and drivers of other databases can overwrite it.
+func (c *Core) FormatAddColumns(table string, columns []*TableColumn) ([]string, error) {
+	return c.doFormatAddColumns(table, columns, mysqlDDLDialect)
+}
+
+// doFormatCreateTable formats the DDL statements for creating table using grammar `dialect`.
+func (c *Core) doFormatCreateTable(table string, columns []*TableColumn, dialect *ddlDialect) ([]string, error) {
+	if len(columns) == 0 {
+		return nil, gerror.Newf(`no columns found for creating table "%s"`, table)
+	}
+	var (
+		definitions    = make([]string, 0, len(columns)+1)
+		primaryColumns = make([]string, 0)
+		inlinePrimary  = false
+	)
+	for _, column := range columns {
+		definition := c.formatColumnDefinition(column, dialect)
+		if column.AutoIncrement && dialect.autoIncrementPrimary {
+			definition = fmt.Sprintf(`%s PRIMARY KEY %s`, definition, dialect.autoIncrement)
+			inlinePrimary = true
+		}
+		definitions = append(definitions, definition)
+		if column.Primary {
+			primaryColumns = append(primaryColumns, c.db.QuoteWord(column.Name))
+		}
+	}
+	if len(primaryColumns) > 0 && !inlinePrimary {
+		definitions = append(definitions, fmt.Sprintf(`PRIMARY KEY (%s)`, strings.Join(primaryColumns, ",")))
+	}
+	statements := []string{fmt.Sprintf(
+		"CREATE TABLE %s (\n\t%s\n)",
+		c.db.QuotePrefixTableName(table), strings.Join(definitions, ",\n\t"),
+	)}
+	statements = append(statements, c.formatColumnComments(table, columns, dialect)...)
+	statements = append(statements, c.formatColumnIndexes(table, columns, columns)...)
+	return statements, nil
+}
+
+// doFormatAddColumns formats the DDL statements for adding columns using grammar `dialect`.
+// The indexes that contain the new columns are also created.
+func (c *Core) doFormatAddColumns(table string, columns []*TableColumn, dialect *ddlDialect) ([]string, error) {
+	statements := make([]string, 0, len(columns))
+	for _, column := range columns {
+		statements = append(statements, fmt.Sprintf(
+			dialect.addColumnFormat,
+			c.db.QuotePrefixTableName(table), c.formatColumnDefinition(column, dialect),
+		))
+	}
+	statements = append(statements, c.formatColumnComments(table, columns, dialect)...)
+	statements = append(statements, c.formatColumnIndexes(table, columns, columns)...)
+	return statements, nil
+}
+
+// formatColumnDefinition formats and returns the definition of `column`, like: `id` int NOT NULL AUTO_INCREMENT.
+// Note that the primary key constraint is not included in the definition.
+func (c *Core) formatColumnDefinition(column *TableColumn, dialect *ddlDialect) string {
+	definition := c.db.QuoteWord(column.Name) + " " + getColumnType(column, dialect)
+	if column.AutoIncrement && !dialect.autoIncrementPrimary && dialect.autoIncrement != "" {
+		definition += " " + dialect.autoIncrement
+	}
+	if column.Default != nil {
+		definition += " DEFAULT " + formatColumnDefaultValue(column)
+	}
+	if !column.Null {
+		definition += " NOT NULL"
+	}
+	if dialect.inlineComment && column.Comment != "" {
+		definition += " COMMENT " + quoteDDLString(column.Comment)
+	}
+	return definition
+}
+
+// formatColumnComments formats and returns the "COMMENT ON COLUMN" statements of `columns`
+// if the comments are not inline of the column definitions.
+func (c *Core) formatColumnComments(table string, columns []*TableColumn, dialect *ddlDialect) []string {
+	statements := make([]string, 0)
+	if !dialect.commentOnColumn {
+		return statements
+	}
+	for _, column := range columns {
+		if column.Comment == "" {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf(
+			`COMMENT ON COLUMN %s.%s IS %s`,
+			c.db.QuotePrefixTableName(table), c.db.QuoteWord(column.Name), quoteDDLString(column.Comment),
+		))
+	}
+	return statements
+}
+
+// formatColumnIndexes formats and returns the "CREATE INDEX" statements of the indexes in `allColumns`
+// which contain any of `columns`. The columns using the same index name make up a composite index.
+func (c *Core) formatColumnIndexes(table string, allColumns []*TableColumn, columns []*TableColumn) []string {
+	type indexItem struct {
+		unique  bool
+		columns []string
+	}
+	var (
+		indexNames   = make([]string, 0)
+		indexMap     = make(map[string]*indexItem)
+		statements   = make([]string, 0)
+		addIndexFunc = func(name string, unique bool, column string) {
+			item, ok := indexMap[name]
+			if !ok {
+				item = &indexItem{unique: unique}
+				indexMap[name] = item
+				indexNames = append(indexNames, name)
+			}
+			item.columns = append(item.columns, column)
+		}
+	)
+	for _, column := range allColumns {
+		if column.Index != "" {
+			addIndexFunc(column.Index, false, column.Name)
+		}
+		if column.Unique != "" {
+			addIndexFunc(column.Unique, true, column.Name)
+		}
+	}
+	for _, name := range indexNames {
+		var (
+			item         = indexMap[name]
+			matched      = false
+			quotedFields = make([]string, len(item.columns))
+		)
+		for i, field := range item.columns {
+			quotedFields[i] = c.db.QuoteWord(field)
+			for _, column := range columns {
+				if column.Name == field {
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			continue
+		}
+		keyword := "INDEX"
+		if item.unique {
+			keyword = "UNIQUE INDEX"
+		}
+		statements = append(statements, fmt.Sprintf(
+			`CREATE %s %s ON %s (%s)`,
+			keyword, c.db.QuoteWord(name), c.db.QuotePrefixTableName(table), strings.Join(quotedFields, ","),
+		))
+	}
+	return statements
+}
+
+// getTableColumnsFromStruct retrieves and returns the table name and its columns from struct `object`.
+func (c *Core) getTableColumnsFromStruct(object interface{}, table ...string) (tableName string, columns []*TableColumn, err error) {
+	if len(table) > 0 && table[0] != "" {
+		tableName = table[0]
+	} else {
+		tableName = getTableNameFromOrmTag(object)
+	}
+	columns, err = ParseTableColumns(object, tableName)
+	return
+}
+
+// ParseTableColumns parses and returns the table columns from struct `object` and its "orm" tags.
+// The parameter `table` is used for naming the indexes that have no name specified,
+// like: idx_{table}_{column}, uk_{table}_{column}.
+// The embedded structs are parsed recursively, and the attributes having tag `orm:"-"`
+// or relation tag `orm:"with:..."` are ignored. See TableColumn for the tag definition.
+func ParseTableColumns(object interface{}, table string) ([]*TableColumn, error) {
+	reflectValue := reflect.ValueOf(object)
+	for reflectValue.Kind() == reflect.Ptr {
+		if reflectValue.IsNil() {
+			reflectValue = reflect.New(reflectValue.Type().Elem())
+		}
+		reflectValue = reflectValue.Elem()
+	}
+	if reflectValue.Kind() != reflect.Struct {
+		return nil, gerror.Newf(`invalid object type "%s", it should be struct or pointer of struct`, reflectValue.Type())
+	}
+	columns := make([]*TableColumn, 0)
+	if err := doParseTableColumns(reflectValue.Type(), table, &columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// doParseTableColumns parses the table columns from struct type `structType` into `columns` recursively.
+func doParseTableColumns(structType reflect.Type, table string, columns *[]*TableColumn) error {
+	metaType := reflect.TypeOf(gmeta.Meta{})
+	for i := 0; i < structType.NumField(); i++ {
+		var (
+			field  = structType.Field(i)
+			ormTag = strings.TrimSpace(field.Tag.Get(OrmTagForStruct))
+		)
+		if field.Type == metaType || ormTag == "-" {
+			continue
+		}
+		if field.Anonymous && ormTag == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct && !isDDLTimeType(embeddedType) {
+				if err := doParseTableColumns(embeddedType, table, columns); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// Unexported attribute.
+			continue
+		}
+		column, err := parseTableColumn(field, ormTag, table)
+		if err != nil {
+			return err
+		}
+		if column != nil {
+			*columns = append(*columns, column)
+		}
+	}
+	return nil
+}
+
+// parseTableColumn parses and returns the table column from struct field `field` and its orm tag `ormTag`.
+// It returns nil if the field is a relation field having "with" tag.
+func parseTableColumn(field reflect.StructField, ormTag string, table string) (*TableColumn, error) {
+	var (
+		items  = splitOrmTagItems(ormTag)
+		column = &TableColumn{
+			GoType: field.Type,
+			Null:   true,
+		}
+	)
+	// Relation field, of which the tag can be "with:uid=id" without column name.
+	for _, item := range items {
+		if pos := strings.Index(item, ":"); pos != -1 && strings.EqualFold(strings.TrimSpace(item[:pos]), OrmTagForWith) {
+			return nil, nil
+		}
+	}
+	if len(items) > 0 {
+		column.Name = items[0]
+		items = items[1:]
+	}
+	if column.Name == "" {
+		column.Name = gstr.CaseSnake(field.Name)
+	}
+	for _, item := range items {
+		var (
+			key   = item
+			value = ""
+		)
+		if pos := strings.Index(item, ":"); pos != -1 {
+			key, value = strings.TrimSpace(item[:pos]), strings.TrimSpace(item[pos+1:])
+		}
+		switch strings.ToLower(key) {
+		case OrmTagForPrimary:
+			column.Primary = true
+			column.Null = false
+		case OrmTagForAutoIncrement:
+			column.AutoIncrement = true
+		case OrmTagForType:
+			column.Type = value
+		case OrmTagForSize:
+			column.Size = gconv.Int(value)
+			if column.Size <= 0 {
+				return nil, gerror.Newf(`invalid size "%s" in orm tag of field "%s"`, value, field.Name)
+			}
+		case OrmTagForDefault:
+			column.Default = value
+		case OrmTagForComment:
+			column.Comment = value
+		case OrmTagForIndex:
+			column.Index = value
+			if column.Index == "" {
+				column.Index = fmt.Sprintf(`idx_%s_%s`, table, column.Name)
+			}
+		case OrmTagForUnique:
+			column.Unique = value
+			if column.Unique == "" {
+				column.Unique = fmt.Sprintf(`uk_%s_%s`, table, column.Name)
+			}
+		default:
+			return nil, gerror.Newf(`unknown item "%s" in orm tag of field "%s"`, item, field.Name)
+		}
+	}
+	return column, nil
+}
+
+// splitOrmTagItems splits the orm tag into items by char ',', ignoring the ',' in parentheses,
+// like: "amount,type:decimal(10,2)" => ["amount", "type:decimal(10,2)"].
+func splitOrmTagItems(tag string) []string {
+	var (
+		items = make([]string, 0)
+		depth = 0
+		start = 0
+	)
+	if tag == "" {
+		return items
+	}
+	for i, c := range tag {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(tag[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(tag[start:]))
+}
+
+// getColumnType returns the database column type of `column` using grammar `dialect`.
+func getColumnType(column *TableColumn, dialect *ddlDialect) string {
+	if column.Type != "" {
+		if column.Size > 0 && !gstr.Contains(column.Type, "(") {
+			return fmt.Sprintf(`%s(%d)`, column.Type, column.Size)
+		}
+		return column.Type
+	}
+	return dialect.columnType(column)
+}
+
+// getColumnStringSize returns the size of string column, which is 255 in default.
+func getColumnStringSize(column *TableColumn) int {
+	if column.Size > 0 {
+		return column.Size
+	}
+	return ddlDefaultStringSize
+}
+
+// getDDLGoType returns the category of the Go type of column for column type converting.
+func getDDLGoType(column *TableColumn) (goType string, unsigned bool) {
+	t := column.GoType
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if isDDLTimeType(t) {
+		return ddlGoTypeTime, false
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return ddlGoTypeBool, false
+	case reflect.Int8:
+		return ddlGoTypeInt8, false
+	case reflect.Uint8:
+		return ddlGoTypeInt8, true
+	case reflect.Int16:
+		return ddlGoTypeInt16, false
+	case reflect.Uint16:
+		return ddlGoTypeInt16, true
+	case reflect.Int32:
+		return ddlGoTypeInt32, false
+	case reflect.Uint32:
+		return ddlGoTypeInt32, true
+	case reflect.Int, reflect.Int64:
+		return ddlGoTypeInt64, false
+	case reflect.Uint, reflect.Uint64:
+		return ddlGoTypeInt64, true
+	case reflect.Float32:
+		return ddlGoTypeFloat32, false
+	case reflect.Float64:
+		return ddlGoTypeFloat64, false
+	case reflect.String:
+		return ddlGoTypeString, false
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return ddlGoTypeBytes, false
+		}
+	}
+	return ddlGoTypeJson, false
+}
+
+// isDDLTimeType checks and returns whether `t` is time type, which is time.Time or gtime.Time.
+func isDDLTimeType(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(gtime.Time{})
+}
+
+// formatColumnDefaultValue formats and returns the default value of `column` for DDL,
+// which quotes the value for string column if it's not quoted.
+func formatColumnDefaultValue(column *TableColumn) string {
+	value := gconv.String(column.Default)
+	if goType, _ := getDDLGoType(column); goType == ddlGoTypeString {
+		if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+			return quoteDDLString(value)
+		}
+	}
+	return value
+}
+
+// quoteDDLString quotes string `s` for DDL statement with single quotes.
+func quoteDDLString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// mysqlColumnType converts the Go type of column to mysql column type.
+func mysqlColumnType(column *TableColumn) string {
+	goType, unsigned := getDDLGoType(column)
+	columnType := ""
+	switch goType {
+	case ddlGoTypeBool:
+		return "tinyint(1)"
+	case ddlGoTypeInt8:
+		columnType = "tinyint"
+	case ddlGoTypeInt16:
+		columnType = "smallint"
+	case ddlGoTypeInt32:
+		columnType = "int"
+	case ddlGoTypeInt64:
+		columnType = "bigint"
+	case ddlGoTypeFloat32:
+		return "float"
+	case ddlGoTypeFloat64:
+		return "double"
+	case ddlGoTypeString:
+		return fmt.Sprintf(`varchar(%d)`, getColumnStringSize(column))
+	case ddlGoTypeBytes:
+		return "blob"
+	case ddlGoTypeTime:
+		return "datetime"
+	default:
+		return "json"
+	}
+	if unsigned {
+		columnType += " unsigned"
+	}
+	return columnType
+}
@@ -0,0 +1,121 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/util/gconv"
+)
+
+// FieldTypeDecoder converts the field value from database to golang variable for registered field type.
+// The parameter `fieldType` is the original field type from database, like: JSON, DECIMAL(10,2).
+type FieldTypeDecoder func(fieldValue interface{}, fieldType string) (interface{}, error)
+
+// FieldTypeEncoder converts golang variable to the field value for database for registered field type,
+// which is used in data writing of Model, like Insert/Replace/Save/Update.
+// The parameter `fieldType` is the field type from TableFields.
+type FieldTypeEncoder func(value interface{}, fieldType string) (interface{}, error)
+
+// fieldTypeConverter is the registered decoder and encoder for field type.
+type fieldTypeConverter struct {
+	decoder FieldTypeDecoder
+	encoder FieldTypeEncoder
+}
+
+var (
+	// fieldTypeConverterMap manages the converters by lower-cased field type name.
+	fieldTypeConverterMap = map[string]*fieldTypeConverter{
+		"json":  {decoder: decodeJsonFieldValue, encoder: encodeJsonFieldValue},
+		"jsonb": {decoder: decodeJsonFieldValue, encoder: encodeJsonFieldValue},
+	}
+	// fieldTypeConverterMu protects fieldTypeConverterMap for concurrent safety.
+	fieldTypeConverterMu sync.RWMutex
+)
+
+// RegisterFieldType registers custom decoder and encoder for field type `typeName`, which
+// overwrites the builtin converting of the field type.
+//
+// The parameter `typeName` is case-insensitive and without length or precision, like: json, _int4, geometry.
+// The parameter `decoder` is used for converting field values of query result,
+// and `encoder` is used for converting data values of writing operations of Model.
+// Either of them can be nil, which means using the builtin converting.
+//
+// The field types "json" and "jsonb" are registered in default, of which the values are decoded
+// as map/slice, so they can be converted to struct, slice, map or *gjson.Json attributes
+// by Scan/Struct/Structs.
+func RegisterFieldType(typeName string, decoder FieldTypeDecoder, encoder FieldTypeEncoder) {
+	fieldTypeConverterMu.Lock()
+	defer fieldTypeConverterMu.Unlock()
+	typeName = strings.ToLower(typeName)
+	if decoder == nil && encoder == nil {
+		delete(fieldTypeConverterMap, typeName)
+		return
+	}
+	fieldTypeConverterMap[typeName] = &fieldTypeConverter{
+		decoder: decoder,
+		encoder: encoder,
+	}
+}
+
+// getFieldTypeConverter returns the registered converter for `fieldType`.
+// It returns nil if there's no converter registered for the field type.
+func getFieldTypeConverter(fieldType string) *fieldTypeConverter {
+	t, _ := gregex.ReplaceString(`\(.+\)`, "", fieldType)
+	t = strings.ToLower(strings.TrimSpace(t))
+	fieldTypeConverterMu.RLock()
+	defer fieldTypeConverterMu.RUnlock()
+	return fieldTypeConverterMap[t]
+}
+
+// encodeFieldValue converts `value` to the field value for database using the registered encoder
+// of `fieldType`. It returns `value` directly if there's no encoder registered for the field type.
+func encodeFieldValue(value interface{}, fieldType string) (interface{}, error) {
+	switch value.(type) {
+	case nil, Raw, Counter, *Counter:
+		return value, nil
+	}
+	if converter := getFieldTypeConverter(fieldType); converter != nil && converter.encoder != nil {
+		return converter.encoder(value, fieldType)
+	}
+	return value, nil
+}
+
+// decodeJsonFieldValue decodes the JSON field value to golang variable,
+// which is map[string]interface{}, []interface{} or other basic types.
+// Numbers are decoded as json.Number to avoid precision loss of big integers.
+func decodeJsonFieldValue(fieldValue interface{}, fieldType string) (interface{}, error) {
+	var (
+		value   interface{}
+		content = gconv.Bytes(fieldValue)
+		decoder = json.NewDecoder(bytes.NewReader(content))
+	)
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		// It is not a valid JSON, it returns the content as string.
+		return string(content), nil
+	}
+	return value, nil
+}
+
+// encodeJsonFieldValue encodes `value` to JSON string for JSON field.
+// String and []byte values are considered as encoded JSON and returned directly.
+func encodeJsonFieldValue(value interface{}, fieldType string) (interface{}, error) {
+	switch value.(type) {
+	case string, []byte:
+		return value, nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(content), nil
+}
@@ -0,0 +1,220 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gogf/gf/container/gmap"
+	"github.com/gogf/gf/container/gset"
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/internal/intlog"
+	"github.com/gogf/gf/os/gtimer"
+	"github.com/gogf/gf/util/gconv"
+)
+
+// HealthChecker is the function for health probe of configuration node,
+// which returns an error if the node is not healthy.
+// The parameter `db` is the underlying connection pool of the node.
+type HealthChecker func(ctx context.Context, db *sql.DB, node *ConfigNode) error
+
+var (
+	// healthChecker is the health probe function for all configuration nodes.
+	healthChecker HealthChecker = defaultHealthChecker
+
+	// healthCheckGroups is the configuration groups that have health probes running.
+	healthCheckGroups = gset.NewStrSet(true)
+
+	// unhealthyNodes is the nodes that failed in their last health probe, which are taken out of rotation.
+	// The element is the string of the node, see ConfigNode.String.
+	unhealthyNodes = gset.NewStrSet(true)
+)
+
+const (
+	contextReadYourWritesKey = "ReadYourWritesMarker" // Context key for storing the marker of read-your-writes feature.
+)
+
+// readYourWritesMarker is the marker stored in context for read-your-writes feature, which records
+// the expiring time in nanoseconds of the pinning to master by configuration group name.
+type readYourWritesMarker struct {
+	expireTimes *gmap.StrAnyMap
+}
+
+// SetHealthChecker sets the custom health probe function for configuration nodes,
+// which is used when configuration HealthCheckInterval is enabled.
+// The default health probe function pings the node.
+func SetHealthChecker(checker HealthChecker) {
+	if checker == nil {
+		checker = defaultHealthChecker
+	}
+	healthChecker = checker
+}
+
+// MysqlReplicationLagChecker returns a HealthChecker for mysql, which checks slave nodes by
+// "SHOW SLAVE STATUS" and treats the slave node as unhealthy if its replication is stopped
+// or its replication lag is more than `maxLag`. Master nodes are only pinged.
+func MysqlReplicationLagChecker(maxLag time.Duration) HealthChecker {
+	return func(ctx context.Context, db *sql.DB, node *ConfigNode) error {
+		if err := defaultHealthChecker(ctx, db, node); err != nil || node.Role != "slave" {
+			return err
+		}
+		rows, err := db.QueryContext(ctx, "SHOW SLAVE STATUS")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		if !rows.Next() {
+			return rows.Err()
+		}
+		var (
+			values   = make([]sql.RawBytes, len(columns))
+			scanArgs = make([]interface{}, len(columns))
+		)
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		if err = rows.Scan(scanArgs...); err != nil {
+			return err
+		}
+		for i, column := range columns {
+			if column != "Seconds_Behind_Master" {
+				continue
+			}
+			if values[i] == nil {
+				return gerror.New(`replication of slave node is not running`)
+			}
+			if lag := time.Duration(gconv.Int64(string(values[i]))) * time.Second; lag > maxLag {
+				return gerror.Newf(`replication lag %s of slave node exceeds %s`, lag, maxLag)
+			}
+		}
+		return nil
+	}
+}
+
+// defaultHealthChecker is the default health probe function, which pings the node.
+func defaultHealthChecker(ctx context.Context, db *sql.DB, node *ConfigNode) error {
+	return db.PingContext(ctx)
+}
+
+// isNodeHealthy checks and returns whether the node is healthy,
+// which is true if the node did not fail in its last health probe.
+func isNodeHealthy(node *ConfigNode) bool {
+	return !unhealthyNodes.Contains(node.String())
+}
+
+// startHealthCheck starts the periodic health probes in `interval` for the configuration group of `c`
+// if it's not started yet. The probe stops automatically if the group is removed from configuration
+// or its HealthCheckInterval is disabled.
+// The parameter `interval` is the HealthCheckInterval of the first node of the group, see getGroupConfigNode.
+func (c *Core) startHealthCheck(interval time.Duration) {
+	group := c.group
+	if interval <= 0 || !healthCheckGroups.AddIfNotExist(group) {
+		return
+	}
+	gtimer.AddSingleton(interval, func() {
+		configs.RLock()
+		nodes := configs.config[group]
+		configs.RUnlock()
+		if len(nodes) == 0 || nodes[0].HealthCheckInterval <= 0 {
+			healthCheckGroups.Remove(group)
+			gtimer.Exit()
+		}
+		for i := range nodes {
+			c.checkNodeHealth(&nodes[i], interval)
+		}
+	})
+}
+
+// checkNodeHealth probes the node with timeout and marks it as healthy or unhealthy.
+func (c *Core) checkNodeHealth(node *ConfigNode, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	sqlDb, err := c.getSqlDbByNode(c.getConfigNodeWithSchema(node, ""), node.Role != "slave")
+	if err == nil {
+		err = healthChecker(ctx, sqlDb, node)
+	}
+	if err != nil {
+		if unhealthyNodes.AddIfNotExist(node.String()) {
+			c.db.GetLogger().Errorf(
+				`health probe failed, node "%s" is taken out of rotation: %v`, getNodeLogName(node), err,
+			)
+		}
+		return
+	}
+	if unhealthyNodes.Contains(node.String()) {
+		unhealthyNodes.Remove(node.String())
+		intlog.Printf(`health probe succeeded, node "%s" is back to rotation`, getNodeLogName(node))
+	}
+}
+
+// getNodeLogName returns the name of `node` for logging, like: "127.0.0.1:3306/test",
+// which contains no credential like the password or link info of the node.
+func getNodeLogName(node *ConfigNode) string {
+	return fmt.Sprintf(`%s:%s/%s`, node.Host, node.Port, node.Name)
+}
+
+// WithReadYourWrites injects the marker of read-your-writes feature into context and returns a new context.
+// The reading operations using the returned context or any context derived from it are pinned to master
+// node for configured duration ReadYourWrites after writing operations using these contexts, which is
+// commonly called in the middleware for the context of each request.
+// It returns `ctx` if it already has the marker.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if getReadYourWritesMarker(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextReadYourWritesKey, &readYourWritesMarker{
+		expireTimes: gmap.NewStrAnyMap(true),
+	})
+}
+
+// getReadYourWritesMarker retrieves and returns the marker of read-your-writes feature from context.
+// It returns nil if there's no marker in the context.
+func getReadYourWritesMarker(ctx context.Context) *readYourWritesMarker {
+	if ctx == nil {
+		return nil
+	}
+	if v, ok := ctx.Value(contextReadYourWritesKey).(*readYourWritesMarker); ok {
+		return v
+	}
+	return nil
+}
+
+// markWritten marks the context `ctx` has writing operations, so that the following reading
+// operations in the same context are pinned to master node for configured duration ReadYourWrites.
+// It does nothing if the context has no marker injected by WithReadYourWrites.
+func (c *Core) markWritten(ctx context.Context) {
+	marker := getReadYourWritesMarker(ctx)
+	if marker == nil {
+		return
+	}
+	if node := c.getGroupConfigNode(); node != nil && node.ReadYourWrites > 0 {
+		marker.expireTimes.Set(c.group, time.Now().Add(node.ReadYourWrites).UnixNano())
+	}
+}
+
+// isWritten checks and returns whether the context of `c` has writing operations in configured duration
+// ReadYourWrites.
+func (c *Core) isWritten() bool {
+	marker := getReadYourWritesMarker(c.ctx)
+	if marker == nil {
+		return false
+	}
+	if v := marker.expireTimes.Get(c.group); v != nil {
+		return v.(int64) > time.Now().UnixNano()
+	}
+	return false
+}
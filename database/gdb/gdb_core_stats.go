@@ -0,0 +1,260 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/container/gmap"
+	"github.com/gogf/gf/text/gregex"
+)
+
+// Stats is the statistics snapshot of a configuration group, see DB.Stats.
+type Stats struct {
+	Group      string            // Configuration group name.
+	Statements []*StatementStats // Statistics of normalized statements, ordered by total latency descending.
+	Nodes      []*NodeStats      // Connection pool statistics of the opened nodes.
+}
+
+// StatementStats is the statistics of a normalized statement, of which the literal values and
+// placeholder lists are replaced with single placeholder '?'.
+type StatementStats struct {
+	Sql          string        // Normalized sql statement.
+	Count        int64         // Execution count.
+	ErrorCount   int64         // Failed execution count.
+	SlowCount    int64         // Execution count that exceeds the SlowThreshold of configuration.
+	RowsAffected int64         // Total affected rows of executing statements.
+	TotalLatency time.Duration // Total execution latency.
+	AvgLatency   time.Duration // Average execution latency.
+	MaxLatency   time.Duration // Max execution latency.
+	P99Latency   time.Duration // 99th percentile latency of the latest executions.
+}
+
+// NodeStats is the connection pool statistics of a configuration node.
+type NodeStats struct {
+	Host  string      // Host of the node.
+	Port  string      // Port of the node.
+	Name  string      // Database name of the node.
+	Role  string      // Role of the node: master, slave.
+	Stats sql.DBStats // Statistics of the underlying connection pool.
+}
+
+// groupStats is the statement statistics of a configuration group.
+type groupStats struct {
+	mu         sync.Mutex
+	statements map[string]*statementStats
+	normalized map[string]string // Normalized sql cache by raw sql, which avoids normalizing the same sql repeatedly.
+}
+
+// statementStats is the statement statistics with latest latencies for percentile calculating.
+type statementStats struct {
+	StatementStats
+	latencies []time.Duration // Ring buffer of the latest latencies.
+	index     int             // Next writing position of the ring buffer.
+}
+
+const (
+	maxStatsStatementCount = 1000 // Max normalized statement count of a group, the exceeded ones are not counted.
+	maxStatsLatencyCount   = 1000 // Max latest latency count of a statement for percentile calculating.
+	maxStatsNormalizeCount = 5000 // Max cached normalized sql count of a group, the exceeded ones are normalized each time.
+)
+
+var (
+	// groupStatsMap manages the statement statistics by configuration group name.
+	groupStatsMap = gmap.NewStrAnyMap(true)
+
+	// gdbPackagePath is the import path of current package, which is used for caller detection.
+	gdbPackagePath = reflect.TypeOf(Core{}).PkgPath()
+)
+
+// Stats returns the statistics snapshot of current configuration group, containing the statistics of
+// all executed normalized statements and the connection pools of the opened configuration nodes.
+// Note that the statement statistics are collected only if configuration "statsEnabled" is true.
+func (c *Core) Stats() *Stats {
+	stats := &Stats{
+		Group:      c.group,
+		Statements: make([]*StatementStats, 0),
+		Nodes:      make([]*NodeStats, 0),
+	}
+	if v := groupStatsMap.Get(c.group); v != nil {
+		g := v.(*groupStats)
+		g.mu.Lock()
+		for _, s := range g.statements {
+			item := s.StatementStats
+			if item.Count > 0 {
+				item.AvgLatency = item.TotalLatency / time.Duration(item.Count)
+			}
+			item.P99Latency = getPercentileLatency(s.latencies, 0.99)
+			stats.Statements = append(stats.Statements, &item)
+		}
+		g.mu.Unlock()
+	}
+	sort.Slice(stats.Statements, func(i, j int) bool {
+		if stats.Statements[i].TotalLatency == stats.Statements[j].TotalLatency {
+			return stats.Statements[i].Sql < stats.Statements[j].Sql
+		}
+		return stats.Statements[i].TotalLatency > stats.Statements[j].TotalLatency
+	})
+	configs.RLock()
+	list := configs.config[c.group]
+	configs.RUnlock()
+	for i := range list {
+		node := c.getConfigNodeWithSchema(&list[i], c.schema.Val())
+		if v, _ := internalCache.Get(node.String()); v != nil {
+			stats.Nodes = append(stats.Nodes, &NodeStats{
+				Host:  node.Host,
+				Port:  node.Port,
+				Name:  node.Name,
+				Role:  node.Role,
+				Stats: v.(*sql.DB).Stats(),
+			})
+		}
+	}
+	return stats
+}
+
+// ResetStats clears the statement statistics of current configuration group.
+func (c *Core) ResetStats() {
+	groupStatsMap.Remove(c.group)
+}
+
+// addSqlToStats adds the execution of sql to the statement statistics of current group if
+// configuration "statsEnabled" is true, and writes it to logger if it exceeds the SlowThreshold of configuration.
+// The parameter `result` is the result of executing statement, which is nil for query statement.
+// The parameter `latency` is the execution latency in nanosecond resolution.
+func (c *Core) addSqlToStats(v *Sql, result sql.Result, latency time.Duration) {
+	var isSlow bool
+	if threshold := c.getSlowThreshold(); threshold > 0 && latency >= threshold {
+		isSlow = true
+		c.writeSlowSqlToLogger(v, latency)
+	}
+	if !c.db.GetConfig().StatsEnabled {
+		return
+	}
+	var rowsAffected int64
+	if result != nil && v.Error == nil {
+		rowsAffected, _ = result.RowsAffected()
+	}
+	g := groupStatsMap.GetOrSetFuncLock(v.Group, func() interface{} {
+		return &groupStats{
+			statements: make(map[string]*statementStats),
+			normalized: make(map[string]string),
+		}
+	}).(*groupStats)
+	g.mu.Lock()
+	normalizedSql, ok := g.normalized[v.Sql]
+	g.mu.Unlock()
+	if !ok {
+		normalizedSql = normalizeSqlForStats(v.Sql)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !ok && len(g.normalized) < maxStatsNormalizeCount {
+		g.normalized[v.Sql] = normalizedSql
+	}
+	s, ok := g.statements[normalizedSql]
+	if !ok {
+		if len(g.statements) >= maxStatsStatementCount {
+			return
+		}
+		s = &statementStats{
+			StatementStats: StatementStats{Sql: normalizedSql},
+		}
+		g.statements[normalizedSql] = s
+	}
+	s.Count++
+	if v.Error != nil {
+		s.ErrorCount++
+	}
+	if isSlow {
+		s.SlowCount++
+	}
+	s.RowsAffected += rowsAffected
+	s.TotalLatency += latency
+	if latency > s.MaxLatency {
+		s.MaxLatency = latency
+	}
+	if len(s.latencies) < maxStatsLatencyCount {
+		s.latencies = append(s.latencies, latency)
+	} else {
+		s.latencies[s.index] = latency
+		s.index = (s.index + 1) % maxStatsLatencyCount
+	}
+}
+
+// getSlowThreshold returns the SlowThreshold of configuration.
+func (c *Core) getSlowThreshold() time.Duration {
+	return c.db.GetConfig().SlowThreshold
+}
+
+// writeSlowSqlToLogger outputs the slow sql object along with its caller to logger.
+// It is enabled only if configuration "slowThreshold" is set.
+func (c *Core) writeSlowSqlToLogger(v *Sql, latency time.Duration) {
+	s := fmt.Sprintf("[%3d ms] [%s] [slow] %s", latency.Milliseconds(), v.Group, v.Format)
+	if caller := getSqlCaller(); caller != "" {
+		s += "\nCaller: " + caller
+	}
+	if v.Error != nil {
+		s += "\nError: " + v.Error.Error()
+	}
+	c.logger.Ctx(c.db.GetCtx()).Warning(s)
+}
+
+// getSqlCaller returns the first caller outside of current package in format "file:line".
+func getSqlCaller() string {
+	var (
+		pcs    = make([]uintptr, 64)
+		frames = runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, gdbPackagePath+".") {
+			return fmt.Sprintf(`%s:%d`, frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// normalizeSqlForStats normalizes the sql for statistics by replacing the string and number literals
+// and placeholder lists with single placeholder '?', and continuous white spaces with one space.
+func normalizeSqlForStats(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	sql, _ = gregex.ReplaceString(`'(?:[^'\\]|\\.|'')*'`, "?", sql)
+	sql, _ = gregex.ReplaceString(`\b\d+(\.\d+)?\b`, "?", sql)
+	sql, _ = gregex.ReplaceString(`\?(\s*,\s*\?)+`, "?", sql)
+	sql, _ = gregex.ReplaceString(`\(\?\)(\s*,\s*\(\?\))+`, "(?)", sql)
+	return sql
+}
+
+// getPercentileLatency calculates and returns the percentile `p` of `latencies`.
+func getPercentileLatency(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	index := int(float64(len(sorted))*p+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
@@ -22,13 +22,22 @@ import (
 )
 
 // convertFieldValueToLocalValue automatically checks and converts field value from database type
-// to golang variable type.
-func (c *Core) convertFieldValueToLocalValue(fieldValue interface{}, fieldType string) interface{} {
+// to golang variable type. It uses the registered decoder of the field type if any, see RegisterFieldType.
+func (c *Core) convertFieldValueToLocalValue(fieldValue interface{}, fieldType string) (interface{}, error) {
 	// If there's no type retrieved, it returns the `fieldValue` directly
 	// to use its original data type, as `fieldValue` is type of interface{}.
 	if fieldType == "" {
-		return fieldValue
+		return fieldValue, nil
 	}
+	if converter := getFieldTypeConverter(fieldType); converter != nil && converter.decoder != nil {
+		return converter.decoder(fieldValue, fieldType)
+	}
+	return c.convertFieldValueToLocalValueByType(fieldValue, fieldType), nil
+}
+
+// convertFieldValueToLocalValueByType converts field value from database type to golang variable type
+// using builtin field type checks.
+func (c *Core) convertFieldValueToLocalValueByType(fieldValue interface{}, fieldType string) interface{} {
 	t, _ := gregex.ReplaceString(`\(.+\)`, "", fieldType)
 	t = strings.ToLower(t)
 	switch t {
@@ -174,6 +183,14 @@ func (c *Core) mappingAndFilterData(schema, table string, data map[string]interf
 				}
 			}
 		}
+		// Custom field type encoding.
+		for dataKey, dataValue := range data {
+			if field, ok := fieldsMap[dataKey]; ok {
+				if data[dataKey], err = encodeFieldValue(dataValue, field.Type); err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
 	return data, nil
 }
@@ -65,6 +65,20 @@ func (c *Core) HandleSqlBeforeCommit(sql string) string {
 	return sql
 }
 
+// GetSavePointSql returns the sql statement for savepoint `operation` on savepoint named `point`.
+// It uses the standard SQL syntax in default, which is supported by mysql, pgsql and sqlite.
+func (c *Core) GetSavePointSql(operation int, point string) string {
+	switch operation {
+	case savePointOperationCreate:
+		return "SAVEPOINT " + c.db.QuoteWord(point)
+	case savePointOperationRollback:
+		return "ROLLBACK TO SAVEPOINT " + c.db.QuoteWord(point)
+	case savePointOperationRelease:
+		return "RELEASE SAVEPOINT " + c.db.QuoteWord(point)
+	}
+	return ""
+}
+
 // Tables retrieves and returns the tables of current schema.
 // It's mainly used in cli tool chain for automatically generating the models.
 //
@@ -0,0 +1,401 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+//
+// Note:
+// 1. It needs manually import: _ "github.com/ClickHouse/clickhouse-go"
+// 2. It does not support Save/Replace features.
+// 3. It does not support LastInsertId.
+// 4. It does not support transaction.
+// 5. The UPDATE/DELETE statements are converted to ALTER TABLE ... UPDATE/DELETE mutations.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/internal/intlog"
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// DriverClickhouse is the driver for clickhouse database.
+type DriverClickhouse struct {
+	*Core
+}
+
+var (
+	// ErrClickhouseTxUnsupported is the error for transaction operations of clickhouse.
+	ErrClickhouseTxUnsupported = gerror.New("transaction is not supported by clickhouse")
+)
+
+// New creates and returns a database object for clickhouse.
+// It implements the interface of gdb.Driver for extra database driver installation.
+func (d *DriverClickhouse) New(core *Core, node *ConfigNode) (DB, error) {
+	return &DriverClickhouse{
+		Core: core,
+	}, nil
+}
+
+// Open creates and returns a underlying sql.DB object for clickhouse.
+func (d *DriverClickhouse) Open(config *ConfigNode) (*sql.DB, error) {
+	var source string
+	if config.LinkInfo != "" {
+		source = config.LinkInfo
+		// Custom changing the schema in runtime.
+		if config.Name != "" {
+			source, _ = gregex.ReplaceString(`database=([\w\.\-]+)+`, "database="+config.Name, source)
+		}
+	} else {
+		source = fmt.Sprintf(
+			"tcp://%s:%s?username=%s&password=%s&database=%s",
+			config.Host, config.Port, config.User, config.Pass, config.Name,
+		)
+	}
+	intlog.Printf("Open: %s", source)
+	if db, err := sql.Open("clickhouse", source); err == nil {
+		return db, nil
+	} else {
+		return nil, err
+	}
+}
+
+// FilteredLinkInfo retrieves and returns filtered `linkInfo` that can be using for
+// logging or tracing purpose.
+func (d *DriverClickhouse) FilteredLinkInfo() string {
+	linkInfo := d.GetConfig().LinkInfo
+	if linkInfo == "" {
+		return ""
+	}
+	s, _ := gregex.ReplaceString(
+		`password=[^&]*`,
+		`password=xxx`,
+		linkInfo,
+	)
+	return s
+}
+
+// GetChars returns the security char for this type of database.
+func (d *DriverClickhouse) GetChars() (charLeft string, charRight string) {
+	return "`", "`"
+}
+
+// HandleSqlBeforeCommit deals with the sql string before commits it to underlying sql driver.
+// It converts the UPDATE/DELETE statements to the ALTER TABLE ... UPDATE/DELETE mutations of clickhouse.
+func (d *DriverClickhouse) HandleSqlBeforeCommit(link Link, sql string, args []interface{}) (string, []interface{}) {
+	if match, _ := gregex.MatchString(`(?is)^\s*UPDATE\s+(.+?)\s+SET\s+(.+)$`, sql); len(match) == 3 {
+		sql = fmt.Sprintf(`ALTER TABLE %s UPDATE %s`, match[1], match[2])
+	} else if match, _ = gregex.MatchString(`(?is)^\s*DELETE\s+FROM\s+(\S+)\s*(.*)$`, sql); len(match) == 3 {
+		sql = fmt.Sprintf(`ALTER TABLE %s DELETE %s`, match[1], match[2])
+	}
+	return sql, args
+}
+
+// Begin is not supported by clickhouse, which returns ErrClickhouseTxUnsupported.
+func (d *DriverClickhouse) Begin() (*TX, error) {
+	return nil, ErrClickhouseTxUnsupported
+}
+
+// Transaction is not supported by clickhouse, which returns ErrClickhouseTxUnsupported.
+func (d *DriverClickhouse) Transaction(f func(tx *TX) error) error {
+	return ErrClickhouseTxUnsupported
+}
+
+// FormatUpsert is not supported by clickhouse, as it has no Save/Replace feature.
+func (d *DriverClickhouse) FormatUpsert(columns []string, option DoInsertOption) (string, error) {
+	return "", gerror.New("Save/Replace operation is not supported by clickhouse")
+}
+
+// DoInsertWithOption inserts data for given table using DoBatchInsertWithOption, as the inserting of clickhouse
+// should be done in batch mode.
+func (d *DriverClickhouse) DoInsertWithOption(link Link, table string, data interface{}, option DoInsertOption) (result sql.Result, err error) {
+	return d.db.DoBatchInsertWithOption(link, table, data, option)
+}
+
+// DoBatchInsertWithOption batch inserts data for clickhouse, which prepares the INSERT statement and executes it
+// for each record in a batch, and the batch is sent to the server at once as it's committed.
+func (d *DriverClickhouse) DoBatchInsertWithOption(link Link, table string, list interface{}, option DoInsertOption) (result sql.Result, err error) {
+	if option.InsertOption != insertOptionDefault {
+		return nil, gerror.New("Save/Replace operation is not supported by clickhouse")
+	}
+	listMap, err := d.convertBatchInsertList(list)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		keys      = make([]string, 0, len(listMap[0]))
+		holders   = make([]string, 0, len(listMap[0]))
+		ctx       = d.db.GetCtx()
+		affected  = int64(0)
+		batchNum  = defaultBatchNumber
+		insertSql string
+	)
+	for k := range listMap[0] {
+		keys = append(keys, k)
+		holders = append(holders, "?")
+	}
+	insertSql = fmt.Sprintf(
+		"INSERT INTO %s(%s) VALUES(%s)",
+		d.db.QuotePrefixTableName(table), d.db.QuoteString(strings.Join(keys, ",")), strings.Join(holders, ","),
+	)
+	if option.BatchCount > 0 {
+		batchNum = option.BatchCount
+	}
+	sqlDb, ok := link.(*sql.DB)
+	if !ok {
+		if sqlDb, err = d.db.Master(); err != nil {
+			return nil, err
+		}
+	}
+	for i := 0; i < len(listMap); i += batchNum {
+		chunk := listMap[i:]
+		if len(chunk) > batchNum {
+			chunk = chunk[:batchNum]
+		}
+		if err = d.doBatchInsertChunk(ctx, sqlDb, insertSql, keys, chunk); err != nil {
+			return &SqlResult{affected: affected}, err
+		}
+		affected += int64(len(chunk))
+	}
+	return &SqlResult{affected: affected}, nil
+}
+
+// doBatchInsertChunk inserts records of `chunk` in one batch of clickhouse.
+func (d *DriverClickhouse) doBatchInsertChunk(ctx context.Context, sqlDb *sql.DB, insertSql string, keys []string, chunk List) (err error) {
+	sqlTx, err := sqlDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = sqlTx.Rollback()
+		}
+	}()
+	stmt, err := d.db.DoPrepare(sqlTx, insertSql)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, item := range chunk {
+		values := make([]interface{}, len(keys))
+		for i, k := range keys {
+			if _, ok := item[k].(Raw); ok {
+				return gerror.Newf(`Raw value is not supported in inserting of clickhouse for field "%s"`, k)
+			}
+			values[i] = item[k]
+		}
+		if _, err = stmt.ExecContext(ctx, values...); err != nil {
+			return err
+		}
+	}
+	return sqlTx.Commit()
+}
+
+// convertBatchInsertList converts the inserting data `list` to List.
+func (d *DriverClickhouse) convertBatchInsertList(list interface{}) (List, error) {
+	var listMap List
+	switch value := list.(type) {
+	case Result:
+		listMap = value.List()
+	case Record:
+		listMap = List{value.Map()}
+	case List:
+		listMap = value
+	case Map:
+		listMap = List{value}
+	default:
+		rv := reflect.ValueOf(list)
+		if rv.Kind() == reflect.Ptr {
+			rv = rv.Elem()
+		}
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			listMap = make(List, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				listMap[i] = ConvertDataForTableRecord(rv.Index(i).Interface())
+			}
+		case reflect.Map, reflect.Struct:
+			listMap = List{ConvertDataForTableRecord(list)}
+		default:
+			return nil, gerror.Newf(`unsupported list type "%T"`, list)
+		}
+	}
+	if len(listMap) < 1 {
+		return nil, gerror.New("data list cannot be empty")
+	}
+	return listMap, nil
+}
+
+// FormatCreateTable formats and returns the DDL statements for creating table of clickhouse,
+// which uses the MergeTree engine ordered by the primary key columns.
+// The indexes of the columns are ignored as clickhouse has no secondary index like other databases.
+func (d *DriverClickhouse) FormatCreateTable(table string, columns []*TableColumn) ([]string, error) {
+	if len(columns) == 0 {
+		return nil, gerror.Newf(`no columns found for creating table "%s"`, table)
+	}
+	var (
+		definitions    = make([]string, 0, len(columns))
+		primaryColumns = make([]string, 0)
+	)
+	for _, column := range columns {
+		definitions = append(definitions, d.formatClickhouseColumnDefinition(column))
+		if column.Primary {
+			primaryColumns = append(primaryColumns, d.db.QuoteWord(column.Name))
+		}
+	}
+	orderBy := "tuple()"
+	if len(primaryColumns) > 0 {
+		orderBy = "(" + strings.Join(primaryColumns, ",") + ")"
+	}
+	return []string{fmt.Sprintf(
+		"CREATE TABLE %s (\n\t%s\n) ENGINE = MergeTree() ORDER BY %s",
+		d.db.QuotePrefixTableName(table), strings.Join(definitions, ",\n\t"), orderBy,
+	)}, nil
+}
+
+// FormatAddColumns formats and returns the DDL statements for adding columns to table of clickhouse.
+func (d *DriverClickhouse) FormatAddColumns(table string, columns []*TableColumn) ([]string, error) {
+	statements := make([]string, 0, len(columns))
+	for _, column := range columns {
+		statements = append(statements, fmt.Sprintf(
+			`ALTER TABLE %s ADD COLUMN %s`,
+			d.db.QuotePrefixTableName(table), d.formatClickhouseColumnDefinition(column),
+		))
+	}
+	return statements, nil
+}
+
+// formatClickhouseColumnDefinition formats and returns the definition of `column` for clickhouse,
+// like: `name` Nullable(String) DEFAULT 'john' COMMENT 'user name'.
+// The Nullable type is used only for pointer attributes, as Nullable columns have performance cost in clickhouse.
+func (d *DriverClickhouse) formatClickhouseColumnDefinition(column *TableColumn) string {
+	columnType := column.Type
+	if columnType == "" {
+		columnType = clickhouseColumnType(column)
+		if column.Null && !column.Primary && column.GoType != nil && column.GoType.Kind() == reflect.Ptr {
+			columnType = fmt.Sprintf(`Nullable(%s)`, columnType)
+		}
+	}
+	definition := d.db.QuoteWord(column.Name) + " " + columnType
+	if column.Default != nil {
+		definition += " DEFAULT " + formatColumnDefaultValue(column)
+	}
+	if column.Comment != "" {
+		definition += " COMMENT " + quoteDDLString(column.Comment)
+	}
+	return definition
+}
+
+// clickhouseColumnType converts the Go type of column to clickhouse column type.
+func clickhouseColumnType(column *TableColumn) string {
+	goType, unsigned := getDDLGoType(column)
+	prefix := "Int"
+	if unsigned {
+		prefix = "UInt"
+	}
+	switch goType {
+	case ddlGoTypeBool:
+		return "UInt8"
+	case ddlGoTypeInt8:
+		return prefix + "8"
+	case ddlGoTypeInt16:
+		return prefix + "16"
+	case ddlGoTypeInt32:
+		return prefix + "32"
+	case ddlGoTypeInt64:
+		return prefix + "64"
+	case ddlGoTypeFloat32:
+		return "Float32"
+	case ddlGoTypeFloat64:
+		return "Float64"
+	case ddlGoTypeTime:
+		return "DateTime"
+	default:
+		return "String"
+	}
+}
+
+// Tables retrieves and returns the tables of current schema.
+// It's mainly used in cli tool chain for automatically generating the models.
+func (d *DriverClickhouse) Tables(schema ...string) (tables []string, err error) {
+	var result Result
+	link, err := d.db.GetSlave(schema...)
+	if err != nil {
+		return nil, err
+	}
+	result, err = d.db.DoGetAll(
+		link, `SELECT name FROM system.tables WHERE database=currentDatabase() ORDER BY name`,
+	)
+	if err != nil {
+		return
+	}
+	for _, m := range result {
+		tables = append(tables, m["name"].String())
+	}
+	return
+}
+
+// TableFields retrieves and returns the fields information of specified table of current schema
+// from system table "system.columns".
+//
+// It's using cache feature to enhance the performance, which is never expired util the
+// process restarts.
+func (d *DriverClickhouse) TableFields(table string, schema ...string) (fields map[string]*TableField, err error) {
+	charL, charR := d.GetChars()
+	table = gstr.Trim(table, charL+charR)
+	if gstr.Contains(table, " ") {
+		return nil, gerror.New("function TableFields supports only single table operations")
+	}
+	checkSchema := d.schema.Val()
+	if len(schema) > 0 && schema[0] != "" {
+		checkSchema = schema[0]
+	}
+	v, _ := internalCache.GetOrSetFunc(
+		fmt.Sprintf(`clickhouse_table_fields_%s_%s@group:%s`, table, checkSchema, d.GetGroup()),
+		func() (interface{}, error) {
+			var (
+				result Result
+				link   *sql.DB
+			)
+			link, err = d.db.GetSlave(checkSchema)
+			if err != nil {
+				return nil, err
+			}
+			result, err = d.db.DoGetAll(
+				link,
+				`SELECT name,type,default_expression,comment,is_in_primary_key FROM system.columns `+
+					`WHERE database=currentDatabase() AND table=? ORDER BY position`,
+				table,
+			)
+			if err != nil {
+				return nil, err
+			}
+			fields = make(map[string]*TableField)
+			for i, m := range result {
+				field := &TableField{
+					Index:   i,
+					Name:    m["name"].String(),
+					Type:    m["type"].String(),
+					Null:    gstr.HasPrefix(m["type"].String(), "Nullable("),
+					Default: m["default_expression"].Val(),
+					Comment: m["comment"].String(),
+				}
+				if m["is_in_primary_key"].Bool() {
+					field.Key = "PRI"
+				}
+				fields[field.Name] = field
+			}
+			return fields, nil
+		}, 0)
+	if err == nil {
+		fields = v.(map[string]*TableField)
+	}
+	return
+}
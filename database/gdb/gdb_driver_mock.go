@@ -0,0 +1,597 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+//
+// Note:
+// 1. It is an in-memory driver for unit testing, which executes no sql but the scripted expectations.
+// 2. It uses the sql grammar of mysql.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gogf/gf/container/gmap"
+	"github.com/gogf/gf/container/gtype"
+	"github.com/gogf/gf/container/gvar"
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/util/gconv"
+)
+
+// DriverMock is the in-memory mock driver for unit testing of the code using DB,
+// which records all the sql statements with their arguments, and returns the scripted
+// results of expectations instead of executing them.
+//
+// It can be used by configuration type "mock", or replaces any other driver by Register,
+// which makes the code using the configuration of production run on the mock driver:
+// gdb.Register("mysql", &gdb.DriverMock{})
+//
+// The mock of the node created by Mock.ConfigNode is the mock itself, which is identified by the LinkInfo
+// of the node. The mock of any other node is retrieved by GetMock with its configuration group name,
+// which is created automatically if it's not created by NewMock yet.
+// The connection pool of the node is identified by its mock name, so the groups having the identical
+// node configuration do not share the same connection pool.
+// The writing statements are also recorded if the DryRun feature is enabled.
+type DriverMock struct {
+	*DriverMysql
+	mock *Mock
+}
+
+// Mock is the scripted expectations and recorded statements of DriverMock.
+type Mock struct {
+	mu           sync.Mutex
+	name         string
+	expectations []*MockExpectation
+	records      []*MockRecord
+	tableFields  map[string]map[string]*TableField
+}
+
+// MockExpectation is the expected statement of Mock and its scripted result.
+type MockExpectation struct {
+	typ          string
+	sql          string
+	args         []interface{}
+	times        int
+	called       int
+	result       Result
+	rowsAffected int64
+	lastInsertId int64
+	err          error
+}
+
+// MockRecord is the statement recorded by Mock.
+type MockRecord struct {
+	Type   string        // Statement type: query, exec, tx.
+	Sql    string        // Sql statement, or BEGIN/COMMIT/ROLLBACK for transaction.
+	Args   []interface{} // Arguments of the sql statement.
+	DryRun bool          // Whether the statement is not committed as the DryRun feature is enabled.
+	Error  error         // The scripted error returned for the statement.
+}
+
+const (
+	MockTypeQuery = "query" // Statement type of querying, like SELECT.
+	MockTypeExec  = "exec"  // Statement type of executing, like INSERT/UPDATE/DELETE.
+	MockTypeTx    = "tx"    // Statement type of transaction, like BEGIN/COMMIT/ROLLBACK.
+
+	mockSqlDriverName = "gdb-mock" // Name of the underlying sql driver of DriverMock.
+)
+
+// mockDriver is implemented by DriverMock and the drivers embedding it.
+type mockDriver interface {
+	Mock() *Mock
+}
+
+var (
+	// mockMap manages the mocks by name.
+	mockMap = gmap.NewStrAnyMap(true)
+
+	// mockNameCounter is used for generating unique mock names.
+	mockNameCounter = gtype.NewInt()
+)
+
+func init() {
+	sql.Register(mockSqlDriverName, &mockSqlDriver{})
+}
+
+// New creates and returns a database object for mock.
+// It implements the interface of gdb.Driver for extra database driver installation.
+func (d *DriverMock) New(core *Core, node *ConfigNode) (DB, error) {
+	return &DriverMock{
+		DriverMysql: &DriverMysql{
+			Core: core,
+		},
+		mock: getOrNewMock(getMockName(core.group, node)),
+	}, nil
+}
+
+// Open creates and returns a underlying sql.DB object for mock.
+func (d *DriverMock) Open(config *ConfigNode) (*sql.DB, error) {
+	name := getMockName(d.GetGroup(), config)
+	getOrNewMock(name)
+	return sql.Open(mockSqlDriverName, name)
+}
+
+// FilteredLinkInfo retrieves and returns filtered `linkInfo` that can be using for
+// logging or tracing purpose.
+func (d *DriverMock) FilteredLinkInfo() string {
+	return d.GetConfig().LinkInfo
+}
+
+// Mock returns the mock of the driver.
+func (d *DriverMock) Mock() *Mock {
+	return d.mock
+}
+
+// Tables retrieves and returns the tables that have their fields mocked by Mock.SetTableFields.
+func (d *DriverMock) Tables(schema ...string) (tables []string, err error) {
+	d.mock.mu.Lock()
+	defer d.mock.mu.Unlock()
+	for table := range d.mock.tableFields {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return
+}
+
+// TableFields retrieves and returns the fields of `table` mocked by Mock.SetTableFields.
+// It returns an error if the fields of `table` are not mocked.
+func (d *DriverMock) TableFields(table string, schema ...string) (fields map[string]*TableField, err error) {
+	charL, charR := d.GetChars()
+	table = strings.Trim(table, charL+charR)
+	d.mock.mu.Lock()
+	defer d.mock.mu.Unlock()
+	if fields, ok := d.mock.tableFields[table]; ok {
+		return fields, nil
+	}
+	return nil, gerror.Newf(`fields of table "%s" are not mocked`, table)
+}
+
+// DoExec commits the sql string and its arguments to the mock.
+// The statement is recorded as dry-run statement if the DryRun feature is enabled.
+func (d *DriverMock) DoExec(link Link, sql string, args ...interface{}) (result sql.Result, err error) {
+	if d.GetDryRun() {
+		newSql, newArgs := formatSql(sql, args)
+		newSql, newArgs = d.db.HandleSqlBeforeCommit(link, newSql, newArgs)
+		d.mock.addRecord(&MockRecord{
+			Type:   MockTypeExec,
+			Sql:    newSql,
+			Args:   newArgs,
+			DryRun: true,
+		})
+	}
+	return d.Core.DoExec(link, sql, args...)
+}
+
+// NewMock creates and returns a new mock with `name`, which replaces the mock with the same name.
+// A unique name is generated if `name` is not given.
+func NewMock(name ...string) *Mock {
+	mock := &Mock{
+		tableFields: make(map[string]map[string]*TableField),
+	}
+	if len(name) > 0 && name[0] != "" {
+		mock.name = name[0]
+	} else {
+		mock.name = fmt.Sprintf(`mock_%d`, mockNameCounter.Add(1))
+	}
+	mockMap.Set(mock.name, mock)
+	return mock
+}
+
+// GetMock returns the mock with `name`, which is the mock name or the configuration group name of DriverMock.
+// It returns nil if the mock does not exist.
+func GetMock(name string) *Mock {
+	if v := mockMap.Get(name); v != nil {
+		return v.(*Mock)
+	}
+	return nil
+}
+
+// getOrNewMock returns the mock with `name`, which is created if it does not exist.
+func getOrNewMock(name string) *Mock {
+	return mockMap.GetOrSetFuncLock(name, func() interface{} {
+		return &Mock{
+			name:        name,
+			tableFields: make(map[string]map[string]*TableField),
+		}
+	}).(*Mock)
+}
+
+// getMockName returns the mock name of `node` in configuration group `group`,
+// which is the LinkInfo of the node if it's created by Mock.ConfigNode, or else the group name.
+func getMockName(group string, node *ConfigNode) string {
+	if node.LinkInfo != "" && GetMock(node.LinkInfo) != nil {
+		return node.LinkInfo
+	}
+	return group
+}
+
+// Name returns the name of the mock.
+func (m *Mock) Name() string {
+	return m.name
+}
+
+// ConfigNode returns the configuration node using the mock, which is identified by the LinkInfo of the node.
+// Note that the driver should be registered as type "mock", or change the type of the node.
+func (m *Mock) ConfigNode() ConfigNode {
+	return ConfigNode{
+		Type:     "mock",
+		LinkInfo: m.name,
+	}
+}
+
+// ExpectQuery adds and returns the expectation of query statement containing `sql`.
+// The sql is matched case-insensitively with its continuous white spaces treated as one space.
+// It matches any query statement if `sql` is empty.
+func (m *Mock) ExpectQuery(sql string) *MockExpectation {
+	return m.addExpectation(MockTypeQuery, sql)
+}
+
+// ExpectExec adds and returns the expectation of executing statement containing `sql`.
+// The sql is matched case-insensitively with its continuous white spaces treated as one space.
+// It matches any executing statement if `sql` is empty.
+func (m *Mock) ExpectExec(sql string) *MockExpectation {
+	return m.addExpectation(MockTypeExec, sql)
+}
+
+// SetTableFields sets the fields of `table`, which are returned by TableFields of DriverMock.
+// The Index of fields is set as their order.
+func (m *Mock) SetTableFields(table string, fields ...*TableField) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fieldMap := make(map[string]*TableField, len(fields))
+	for i, field := range fields {
+		field.Index = i
+		fieldMap[field.Name] = field
+	}
+	m.tableFields[table] = fieldMap
+}
+
+// Records returns the statements recorded by the mock in order.
+func (m *Mock) Records() []*MockRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records := make([]*MockRecord, len(m.records))
+	copy(records, m.records)
+	return records
+}
+
+// Reset clears the expectations and recorded statements of the mock.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expectations = nil
+	m.records = nil
+}
+
+// ExpectationsWereMet checks whether all the expectations are matched by the statements
+// for their expected times. It returns an error describing the unmet expectations.
+func (m *Mock) ExpectationsWereMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	unmet := make([]string, 0)
+	for _, e := range m.expectations {
+		if e.times > 0 && e.called < e.times {
+			unmet = append(unmet, fmt.Sprintf(
+				`%s "%s" is expected %d times but called %d times`, e.typ, e.sql, e.times, e.called,
+			))
+		}
+	}
+	if len(unmet) > 0 {
+		return gerror.Newf(`unmet expectations: %s`, strings.Join(unmet, "; "))
+	}
+	return nil
+}
+
+// addExpectation adds and returns the expectation of statement type `typ`.
+func (m *Mock) addExpectation(typ string, sql string) *MockExpectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := &MockExpectation{
+		typ:   typ,
+		sql:   normalizeMockSql(sql),
+		times: 1,
+	}
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// addRecord records the statement.
+func (m *Mock) addRecord(record *MockRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, record)
+}
+
+// match records the statement and returns the first matched expectation that is not used up.
+// It returns an error if there's no expectation matched, or the scripted error of the expectation.
+func (m *Mock) match(typ string, sql string, args []interface{}) (*MockExpectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var (
+		record = &MockRecord{Type: typ, Sql: sql, Args: args}
+		newSql = normalizeMockSql(sql)
+	)
+	m.records = append(m.records, record)
+	for _, e := range m.expectations {
+		if e.typ != typ || (e.times > 0 && e.called >= e.times) || !strings.Contains(newSql, e.sql) {
+			continue
+		}
+		if e.args != nil && !isMockArgsMatched(e.args, args) {
+			continue
+		}
+		e.called++
+		record.Error = e.err
+		return e, e.err
+	}
+	record.Error = gerror.Newf(`unexpected %s statement: %s, args: %v`, typ, sql, args)
+	return nil, record.Error
+}
+
+// WithArgs sets the expected arguments of the statement, which are compared as strings.
+func (e *MockExpectation) WithArgs(args ...interface{}) *MockExpectation {
+	if args == nil {
+		args = make([]interface{}, 0)
+	}
+	e.args = args
+	return e
+}
+
+// Times sets the expected times of the statement, which is 1 in default.
+// The expectation matches statements for unlimited times if `times` <= 0.
+func (e *MockExpectation) Times(times int) *MockExpectation {
+	e.times = times
+	return e
+}
+
+// WillReturnResult sets the result returned for the query statement.
+func (e *MockExpectation) WillReturnResult(result Result) *MockExpectation {
+	e.result = result
+	return e
+}
+
+// WillReturnRows sets the result returned for the query statement using maps.
+func (e *MockExpectation) WillReturnRows(rows ...Map) *MockExpectation {
+	result := make(Result, len(rows))
+	for i, row := range rows {
+		result[i] = make(Record, len(row))
+		for k, v := range row {
+			result[i][k] = gvar.New(v)
+		}
+	}
+	e.result = result
+	return e
+}
+
+// WillReturnAffected sets the affected rows count and optional last insert id
+// returned for the executing statement.
+func (e *MockExpectation) WillReturnAffected(rowsAffected int64, lastInsertId ...int64) *MockExpectation {
+	e.rowsAffected = rowsAffected
+	if len(lastInsertId) > 0 {
+		e.lastInsertId = lastInsertId[0]
+	}
+	return e
+}
+
+// WillReturnError sets the error returned for the statement.
+func (e *MockExpectation) WillReturnError(err error) *MockExpectation {
+	e.err = err
+	return e
+}
+
+// normalizeMockSql lower-cases the sql and replaces its continuous white spaces with one space.
+func normalizeMockSql(sql string) string {
+	return strings.ToLower(strings.Join(strings.Fields(sql), " "))
+}
+
+// isMockArgsMatched checks whether the arguments of statement `args` equals to the expected `expected`.
+func isMockArgsMatched(expected []interface{}, args []interface{}) bool {
+	if len(expected) != len(args) {
+		return false
+	}
+	for i := range expected {
+		if gconv.String(expected[i]) != gconv.String(args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// mockSqlDriver is the underlying sql driver of DriverMock.
+type mockSqlDriver struct{}
+
+// mockConn is the connection of mockSqlDriver, which passes the statements to the mock.
+type mockConn struct {
+	mock *Mock
+}
+
+// mockTx is the transaction of mockConn.
+type mockTx struct {
+	mock *Mock
+}
+
+// mockStmt is the prepared statement of mockConn.
+type mockStmt struct {
+	conn *mockConn
+	sql  string
+}
+
+// mockRows is the rows of query statement from the scripted result.
+type mockRows struct {
+	columns []string
+	result  Result
+	index   int
+}
+
+// mockResult is the result of executing statement from the scripted affected rows count.
+type mockResult struct {
+	rowsAffected int64
+	lastInsertId int64
+}
+
+// Open implements interface driver.Driver.
+func (d *mockSqlDriver) Open(name string) (driver.Conn, error) {
+	mock := GetMock(name)
+	if mock == nil {
+		return nil, gerror.Newf(`mock "%s" is not found`, name)
+	}
+	return &mockConn{mock: mock}, nil
+}
+
+// Prepare implements interface driver.Conn.
+func (c *mockConn) Prepare(query string) (driver.Stmt, error) {
+	return &mockStmt{conn: c, sql: query}, nil
+}
+
+// Close implements interface driver.Conn.
+func (c *mockConn) Close() error {
+	return nil
+}
+
+// Begin implements interface driver.Conn.
+func (c *mockConn) Begin() (driver.Tx, error) {
+	c.mock.addRecord(&MockRecord{Type: MockTypeTx, Sql: "BEGIN"})
+	return &mockTx{mock: c.mock}, nil
+}
+
+// BeginTx implements interface driver.ConnBeginTx.
+func (c *mockConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Begin()
+}
+
+// CheckNamedValue implements interface driver.NamedValueChecker, which accepts arguments of any type.
+func (c *mockConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+// QueryContext implements interface driver.QueryerContext.
+func (c *mockConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := c.mock.match(MockTypeQuery, query, namedValuesToInterfaces(args))
+	if err != nil {
+		return nil, err
+	}
+	return newMockRows(e.result), nil
+}
+
+// ExecContext implements interface driver.ExecerContext.
+func (c *mockConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := c.mock.match(MockTypeExec, query, namedValuesToInterfaces(args))
+	if err != nil {
+		return nil, err
+	}
+	return &mockResult{rowsAffected: e.rowsAffected, lastInsertId: e.lastInsertId}, nil
+}
+
+// Commit implements interface driver.Tx.
+func (tx *mockTx) Commit() error {
+	tx.mock.addRecord(&MockRecord{Type: MockTypeTx, Sql: "COMMIT"})
+	return nil
+}
+
+// Rollback implements interface driver.Tx.
+func (tx *mockTx) Rollback() error {
+	tx.mock.addRecord(&MockRecord{Type: MockTypeTx, Sql: "ROLLBACK"})
+	return nil
+}
+
+// Close implements interface driver.Stmt.
+func (s *mockStmt) Close() error {
+	return nil
+}
+
+// NumInput implements interface driver.Stmt, which returns -1 as the mock does not check it.
+func (s *mockStmt) NumInput() int {
+	return -1
+}
+
+// Exec implements interface driver.Stmt.
+func (s *mockStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.sql, valuesToNamedValues(args))
+}
+
+// Query implements interface driver.Stmt.
+func (s *mockStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.sql, valuesToNamedValues(args))
+}
+
+// newMockRows creates and returns the rows of `result`, of which the columns are sorted by name.
+func newMockRows(result Result) *mockRows {
+	columnMap := make(map[string]struct{})
+	for _, record := range result {
+		for k := range record {
+			columnMap[k] = struct{}{}
+		}
+	}
+	columns := make([]string, 0, len(columnMap))
+	for k := range columnMap {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return &mockRows{
+		columns: columns,
+		result:  result,
+	}
+}
+
+// Columns implements interface driver.Rows.
+func (r *mockRows) Columns() []string {
+	return r.columns
+}
+
+// Close implements interface driver.Rows.
+func (r *mockRows) Close() error {
+	return nil
+}
+
+// Next implements interface driver.Rows.
+func (r *mockRows) Next(dest []driver.Value) error {
+	if r.index >= len(r.result) {
+		return io.EOF
+	}
+	record := r.result[r.index]
+	for i, column := range r.columns {
+		if v, ok := record[column]; ok && v != nil {
+			dest[i] = v.Val()
+		} else {
+			dest[i] = nil
+		}
+	}
+	r.index++
+	return nil
+}
+
+// LastInsertId implements interface driver.Result.
+func (r *mockResult) LastInsertId() (int64, error) {
+	return r.lastInsertId, nil
+}
+
+// RowsAffected implements interface driver.Result.
+func (r *mockResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// namedValuesToInterfaces converts driver arguments to interface slice.
+func namedValuesToInterfaces(args []driver.NamedValue) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+// valuesToNamedValues converts driver values to named values.
+func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
+	values := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		values[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return values
+}
@@ -88,6 +88,64 @@ func (d *DriverMssql) HandleSqlBeforeCommit(link Link, sql string, args []interf
 	return d.parseSql(str), args
 }
 
+// GetSavePointSql returns the sql statement for savepoint `operation` on savepoint named `point`.
+// Note that SQL server does not support releasing savepoint, which is done by the final commit.
+func (d *DriverMssql) GetSavePointSql(operation int, point string) string {
+	switch operation {
+	case savePointOperationCreate:
+		return "SAVE TRANSACTION " + d.QuoteWord(point)
+	case savePointOperationRollback:
+		return "ROLLBACK TRANSACTION " + d.QuoteWord(point)
+	}
+	return ""
+}
+
+// mssqlDDLDialect is the DDL grammar of SQL server, which does not support column comment in DDL.
+var mssqlDDLDialect = &ddlDialect{
+	columnType:      mssqlColumnType,
+	autoIncrement:   "IDENTITY(1,1)",
+	addColumnFormat: "ALTER TABLE %s ADD %s",
+}
+
+// FormatCreateTable formats and returns the DDL statements for creating table of SQL server.
+func (d *DriverMssql) FormatCreateTable(table string, columns []*TableColumn) ([]string, error) {
+	return d.doFormatCreateTable(table, columns, mssqlDDLDialect)
+}
+
+// FormatAddColumns formats and returns the DDL statements for adding columns to table of SQL server.
+func (d *DriverMssql) FormatAddColumns(table string, columns []*TableColumn) ([]string, error) {
+	return d.doFormatAddColumns(table, columns, mssqlDDLDialect)
+}
+
+// mssqlColumnType converts the Go type of column to SQL server column type.
+func mssqlColumnType(column *TableColumn) string {
+	goType, _ := getDDLGoType(column)
+	switch goType {
+	case ddlGoTypeBool:
+		return "bit"
+	case ddlGoTypeInt8:
+		return "tinyint"
+	case ddlGoTypeInt16:
+		return "smallint"
+	case ddlGoTypeInt32:
+		return "int"
+	case ddlGoTypeInt64:
+		return "bigint"
+	case ddlGoTypeFloat32:
+		return "real"
+	case ddlGoTypeFloat64:
+		return "float"
+	case ddlGoTypeString:
+		return fmt.Sprintf(`nvarchar(%d)`, getColumnStringSize(column))
+	case ddlGoTypeBytes:
+		return "varbinary(max)"
+	case ddlGoTypeTime:
+		return "datetime"
+	default:
+		return "nvarchar(max)"
+	}
+}
+
 // parseSql does some replacement of the sql before commits it to underlying driver,
 // for support of microsoft sql server.
 func (d *DriverMssql) parseSql(sql string) string {
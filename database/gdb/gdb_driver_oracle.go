@@ -106,6 +106,63 @@ func (d *DriverOracle) HandleSqlBeforeCommit(link Link, sql string, args []inter
 	return
 }
 
+// GetSavePointSql returns the sql statement for savepoint `operation` on savepoint named `point`.
+// Note that oracle does not support releasing savepoint, which is done by the final commit.
+func (d *DriverOracle) GetSavePointSql(operation int, point string) string {
+	switch operation {
+	case savePointOperationCreate:
+		return "SAVEPOINT " + d.QuoteWord(point)
+	case savePointOperationRollback:
+		return "ROLLBACK TO SAVEPOINT " + d.QuoteWord(point)
+	}
+	return ""
+}
+
+// oracleDDLDialect is the DDL grammar of oracle, whose identity column is supported since oracle 12c.
+var oracleDDLDialect = &ddlDialect{
+	columnType:      oracleColumnType,
+	autoIncrement:   "GENERATED BY DEFAULT AS IDENTITY",
+	commentOnColumn: true,
+	addColumnFormat: "ALTER TABLE %s ADD (%s)",
+}
+
+// FormatCreateTable formats and returns the DDL statements for creating table of oracle.
+func (d *DriverOracle) FormatCreateTable(table string, columns []*TableColumn) ([]string, error) {
+	return d.doFormatCreateTable(table, columns, oracleDDLDialect)
+}
+
+// FormatAddColumns formats and returns the DDL statements for adding columns to table of oracle.
+func (d *DriverOracle) FormatAddColumns(table string, columns []*TableColumn) ([]string, error) {
+	return d.doFormatAddColumns(table, columns, oracleDDLDialect)
+}
+
+// oracleColumnType converts the Go type of column to oracle column type.
+func oracleColumnType(column *TableColumn) string {
+	goType, _ := getDDLGoType(column)
+	switch goType {
+	case ddlGoTypeBool:
+		return "NUMBER(1)"
+	case ddlGoTypeInt8, ddlGoTypeInt16:
+		return "NUMBER(5)"
+	case ddlGoTypeInt32:
+		return "NUMBER(10)"
+	case ddlGoTypeInt64:
+		return "NUMBER(19)"
+	case ddlGoTypeFloat32:
+		return "BINARY_FLOAT"
+	case ddlGoTypeFloat64:
+		return "BINARY_DOUBLE"
+	case ddlGoTypeString:
+		return fmt.Sprintf(`VARCHAR2(%d)`, getColumnStringSize(column))
+	case ddlGoTypeBytes:
+		return "BLOB"
+	case ddlGoTypeTime:
+		return "TIMESTAMP"
+	default:
+		return "CLOB"
+	}
+}
+
 // parseSql does some replacement of the sql before commits it to underlying driver,
 // for support of oracle server.
 func (d *DriverOracle) parseSql(sql string) string {
@@ -252,7 +309,7 @@ func (d *DriverOracle) getTableUniqueIndex(table string) (fields map[string]map[
 	return
 }
 
-func (d *DriverOracle) DoInsert(link Link, table string, data interface{}, option int, batch ...int) (result sql.Result, err error) {
+func (d *DriverOracle) DoInsertWithOption(link Link, table string, data interface{}, option DoInsertOption) (result sql.Result, err error) {
 	var (
 		fields  []string
 		values  []string
@@ -267,7 +324,7 @@ func (d *DriverOracle) DoInsert(link Link, table string, data interface{}, optio
 	}
 	switch kind {
 	case reflect.Slice, reflect.Array:
-		return d.db.DoBatchInsert(link, table, data, option, batch...)
+		return d.db.DoBatchInsertWithOption(link, table, data, option)
 	case reflect.Map:
 		fallthrough
 	case reflect.Struct:
@@ -280,7 +337,7 @@ func (d *DriverOracle) DoInsert(link Link, table string, data interface{}, optio
 		indexMap    = make(map[string]string)
 		indexExists = false
 	)
-	if option != insertOptionDefault {
+	if option.InsertOption != insertOptionDefault {
 		index, err := d.getTableUniqueIndex(table)
 		if err != nil {
 			return nil, err
@@ -307,7 +364,7 @@ func (d *DriverOracle) DoInsert(link Link, table string, data interface{}, optio
 		k = strings.ToUpper(k)
 
 		// 操作类型为REPLACE/SAVE时且存在唯一索引才使用merge，否则使用insert
-		if (option == insertOptionReplace || option == insertOptionSave) && indexExists {
+		if (option.InsertOption == insertOptionReplace || option.InsertOption == insertOptionSave) && indexExists {
 			fields = append(fields, tableAlias1+"."+charL+k+charR)
 			values = append(values, tableAlias2+"."+charL+k+charR)
 			params = append(params, v)
@@ -331,8 +388,8 @@ func (d *DriverOracle) DoInsert(link Link, table string, data interface{}, optio
 		}
 	}
 
-	if indexExists && option != insertOptionDefault {
-		switch option {
+	if indexExists && option.InsertOption != insertOptionDefault {
+		switch option.InsertOption {
 		case
 			insertOptionReplace,
 			insertOptionSave:
@@ -362,7 +419,7 @@ func (d *DriverOracle) DoInsert(link Link, table string, data interface{}, optio
 		params...)
 }
 
-func (d *DriverOracle) DoBatchInsert(link Link, table string, list interface{}, option int, batch ...int) (result sql.Result, err error) {
+func (d *DriverOracle) DoBatchInsertWithOption(link Link, table string, list interface{}, option DoInsertOption) (result sql.Result, err error) {
 	var (
 		keys   []string
 		values []string
@@ -421,9 +478,9 @@ func (d *DriverOracle) DoBatchInsert(link Link, table string, list interface{},
 		keyStr         = charL + strings.Join(keys, charL+","+charR) + charR
 		valueHolderStr = strings.Join(holders, ",")
 	)
-	if option != insertOptionDefault {
+	if option.InsertOption != insertOptionDefault {
 		for _, v := range listMap {
-			r, err := d.db.DoInsert(link, table, v, option, 1)
+			r, err := d.db.DoInsertWithOption(link, table, v, option)
 			if err != nil {
 				return r, err
 			}
@@ -439,8 +496,8 @@ func (d *DriverOracle) DoBatchInsert(link Link, table string, list interface{},
 	}
 
 	batchNum := defaultBatchNumber
-	if len(batch) > 0 {
-		batchNum = batch[0]
+	if option.BatchCount > 0 {
+		batchNum = option.BatchCount
 	}
 	// Format "INSERT...INTO..." statement.
 	intoStr := make([]string, 0)
@@ -17,6 +17,7 @@ import (
 	"github.com/gogf/gf/errors/gerror"
 	"github.com/gogf/gf/internal/intlog"
 	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gconv"
 	"strings"
 
 	"github.com/gogf/gf/text/gregex"
@@ -86,6 +87,67 @@ func (d *DriverPgsql) HandleSqlBeforeCommit(link Link, sql string, args []interf
 	return sql, args
 }
 
+// FormatUpsert formats and returns the "ON CONFLICT (...) DO UPDATE SET ..." upsert statement
+// for SAVE operation of pgsql.
+func (d *DriverPgsql) FormatUpsert(columns []string, option DoInsertOption) (string, error) {
+	return d.formatUpsertOnConflict(columns, option)
+}
+
+// requireUpsertConflict implements interface upsertConflictRequired.
+func (d *DriverPgsql) requireUpsertConflict() {}
+
+// pgsqlDDLDialect is the DDL grammar of pgsql.
+var pgsqlDDLDialect = &ddlDialect{
+	columnType:      pgsqlColumnType,
+	commentOnColumn: true,
+	addColumnFormat: "ALTER TABLE %s ADD COLUMN %s",
+}
+
+// FormatCreateTable formats and returns the DDL statements for creating table of pgsql.
+func (d *DriverPgsql) FormatCreateTable(table string, columns []*TableColumn) ([]string, error) {
+	return d.doFormatCreateTable(table, columns, pgsqlDDLDialect)
+}
+
+// FormatAddColumns formats and returns the DDL statements for adding columns to table of pgsql.
+func (d *DriverPgsql) FormatAddColumns(table string, columns []*TableColumn) ([]string, error) {
+	return d.doFormatAddColumns(table, columns, pgsqlDDLDialect)
+}
+
+// pgsqlColumnType converts the Go type of column to pgsql column type.
+// The auto increment column uses type serial or bigserial.
+func pgsqlColumnType(column *TableColumn) string {
+	goType, _ := getDDLGoType(column)
+	switch goType {
+	case ddlGoTypeBool:
+		return "boolean"
+	case ddlGoTypeInt8, ddlGoTypeInt16, ddlGoTypeInt32:
+		if column.AutoIncrement {
+			return "serial"
+		}
+		if goType == ddlGoTypeInt32 {
+			return "integer"
+		}
+		return "smallint"
+	case ddlGoTypeInt64:
+		if column.AutoIncrement {
+			return "bigserial"
+		}
+		return "bigint"
+	case ddlGoTypeFloat32:
+		return "real"
+	case ddlGoTypeFloat64:
+		return "double precision"
+	case ddlGoTypeString:
+		return fmt.Sprintf(`varchar(%d)`, getColumnStringSize(column))
+	case ddlGoTypeBytes:
+		return "bytea"
+	case ddlGoTypeTime:
+		return "timestamp"
+	default:
+		return "jsonb"
+	}
+}
+
 // Tables retrieves and returns the tables of current schema.
 // It's mainly used in cli tool chain for automatically generating the models.
 func (d *DriverPgsql) Tables(schema ...string) (tables []string, err error) {
@@ -134,7 +196,11 @@ func (d *DriverPgsql) TableFields(table string, schema ...string) (fields map[st
 				return nil, err
 			}
 			structureSql := fmt.Sprintf(`
-SELECT a.attname AS field, t.typname AS type FROM pg_class c, pg_attribute a 
+SELECT a.attname AS field, t.typname AS type,
+CASE WHEN EXISTS (
+	SELECT 1 FROM pg_index i WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+) THEN 'pri' ELSE '' END AS key
+FROM pg_class c, pg_attribute a 
 LEFT OUTER JOIN pg_description b ON a.attrelid=b.objoid AND a.attnum = b.objsubid,pg_type t
 WHERE c.relname = '%s' and a.attnum > 0 and a.attrelid = c.oid and a.atttypid = t.oid 
 ORDER BY a.attnum`,
@@ -152,6 +218,7 @@ ORDER BY a.attnum`,
 					Index: i,
 					Name:  m["field"].String(),
 					Type:  m["type"].String(),
+					Key:   m["key"].String(),
 				}
 			}
 			return fields, nil
@@ -161,3 +228,104 @@ ORDER BY a.attnum`,
 	}
 	return
 }
+
+// DoBatchUpdate updates the records of `list` identified by `keyField` using
+// "UPDATE ... SET field=v.field FROM (VALUES ...) AS v(...) WHERE key=v.key" statement for pgsql.
+// The records are grouped by their fields as the VALUES list requires the same fields for all records,
+// and the values are cast to their field types as the placeholders of VALUES list are untyped in pgsql.
+func (d *DriverPgsql) DoBatchUpdate(link Link, table string, list List, keyField string, condition string, args ...interface{}) (result sql.Result, err error) {
+	var (
+		tableFields, _ = d.db.TableFields(table)
+		quotedTable    = d.db.QuotePrefixTableName(table)
+		batchResult    = new(SqlResult)
+		groupKeys      = make([]string, 0)
+		groupMap       = make(map[string]List)
+	)
+	for _, item := range list {
+		groupKey := strings.Join(getBatchUpdateFields(List{item}, keyField), ",")
+		if _, ok := groupMap[groupKey]; !ok {
+			groupKeys = append(groupKeys, groupKey)
+		}
+		groupMap[groupKey] = append(groupMap[groupKey], item)
+	}
+	if link == nil {
+		if link, err = d.db.Master(); err != nil {
+			return nil, err
+		}
+	}
+	for _, groupKey := range groupKeys {
+		var (
+			groupList = groupMap[groupKey]
+			fields    = getBatchUpdateFields(groupList, keyField)
+			columns   = []string{`"gf_0"`}
+			updates   = make([]string, 0, len(fields))
+			values    = make([]string, 0, len(groupList))
+			params    = make([]interface{}, 0)
+		)
+		if len(fields) == 0 {
+			return nil, gerror.New("data cannot be empty")
+		}
+		for i, field := range fields {
+			column := fmt.Sprintf(`"gf_%d"`, i+1)
+			columns = append(columns, column)
+			updates = append(updates, fmt.Sprintf(
+				`%s=%s`, d.db.QuoteWord(field), pgsqlCastValue(`"gf_v".`+column, tableFields[field]),
+			))
+		}
+		for _, item := range groupList {
+			holders := []string{"?"}
+			params = append(params, item[keyField])
+			for _, field := range fields {
+				switch v := item[field].(type) {
+				case Raw:
+					holders = append(holders, gconv.String(v))
+				case Counter, *Counter:
+					return nil, gerror.Newf(`Counter is not supported in batch update of pgsql for field "%s"`, field)
+				default:
+					holders = append(holders, "?")
+					params = append(params, v)
+				}
+			}
+			values = append(values, "("+strings.Join(holders, ",")+")")
+		}
+		where := fmt.Sprintf(
+			`%s.%s=%s`,
+			quotedTable, d.db.QuoteWord(keyField), pgsqlCastValue(`"gf_v"."gf_0"`, tableFields[keyField]),
+		)
+		if condition = gstr.Trim(condition); condition != "" {
+			where += fmt.Sprintf(` AND (%s)`, gstr.TrimLeftStr(condition, "WHERE "))
+		}
+		r, err := d.db.DoExec(
+			link,
+			fmt.Sprintf(
+				`UPDATE %s SET %s FROM (VALUES %s) AS "gf_v"(%s) WHERE %s`,
+				quotedTable, strings.Join(updates, ","), strings.Join(values, ","),
+				strings.Join(columns, ","), where,
+			),
+			append(params, args...)...,
+		)
+		if err != nil {
+			return r, err
+		}
+		n, err := r.RowsAffected()
+		if err != nil {
+			return r, err
+		}
+		batchResult.result = r
+		batchResult.affected += n
+	}
+	return batchResult, nil
+}
+
+// pgsqlCastValue casts the `value` expression to the type of `field`, like: CAST(value AS int4).
+// The array types like "_int4" are cast as "int4[]".
+func pgsqlCastValue(value string, field *TableField) string {
+	if field == nil || field.Type == "" {
+		return value
+	}
+	fieldType := field.Type
+	if gstr.HasPrefix(fieldType, "_") {
+		fieldType = fieldType[1:] + "[]"
+	}
+	return fmt.Sprintf(`CAST(%s AS %s)`, value, fieldType)
+}
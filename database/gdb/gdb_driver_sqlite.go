@@ -65,12 +65,55 @@ func (d *DriverSqlite) GetChars() (charLeft string, charRight string) {
 }
 
 // HandleSqlBeforeCommit deals with the sql string before commits it to underlying sql driver.
-// TODO 需要增加对Save方法的支持，可使用正则来实现替换，
-// TODO 将ON DUPLICATE KEY UPDATE触发器修改为两条SQL语句(INSERT OR IGNORE & UPDATE)
 func (d *DriverSqlite) HandleSqlBeforeCommit(link Link, sql string, args []interface{}) (string, []interface{}) {
 	return sql, args
 }
 
+// FormatUpsert formats and returns the "ON CONFLICT (...) DO UPDATE SET ..." upsert statement
+// for SAVE operation of sqlite, which is supported since sqlite 3.24.0.
+func (d *DriverSqlite) FormatUpsert(columns []string, option DoInsertOption) (string, error) {
+	return d.formatUpsertOnConflict(columns, option)
+}
+
+// requireUpsertConflict implements interface upsertConflictRequired.
+func (d *DriverSqlite) requireUpsertConflict() {}
+
+// sqliteDDLDialect is the DDL grammar of sqlite, which has no column comment,
+// and its auto increment column should be defined as "INTEGER PRIMARY KEY AUTOINCREMENT".
+var sqliteDDLDialect = &ddlDialect{
+	columnType:           sqliteColumnType,
+	autoIncrement:        "AUTOINCREMENT",
+	autoIncrementPrimary: true,
+	addColumnFormat:      "ALTER TABLE %s ADD COLUMN %s",
+}
+
+// FormatCreateTable formats and returns the DDL statements for creating table of sqlite.
+func (d *DriverSqlite) FormatCreateTable(table string, columns []*TableColumn) ([]string, error) {
+	return d.doFormatCreateTable(table, columns, sqliteDDLDialect)
+}
+
+// FormatAddColumns formats and returns the DDL statements for adding columns to table of sqlite.
+func (d *DriverSqlite) FormatAddColumns(table string, columns []*TableColumn) ([]string, error) {
+	return d.doFormatAddColumns(table, columns, sqliteDDLDialect)
+}
+
+// sqliteColumnType converts the Go type of column to sqlite column type.
+func sqliteColumnType(column *TableColumn) string {
+	goType, _ := getDDLGoType(column)
+	switch goType {
+	case ddlGoTypeBool, ddlGoTypeInt8, ddlGoTypeInt16, ddlGoTypeInt32, ddlGoTypeInt64:
+		return "INTEGER"
+	case ddlGoTypeFloat32, ddlGoTypeFloat64:
+		return "REAL"
+	case ddlGoTypeBytes:
+		return "BLOB"
+	case ddlGoTypeTime:
+		return "DATETIME"
+	default:
+		return "TEXT"
+	}
+}
+
 // Tables retrieves and returns the tables of current schema.
 // It's mainly used in cli tool chain for automatically generating the models.
 func (d *DriverSqlite) Tables(schema ...string) (tables []string, err error) {
@@ -120,10 +163,15 @@ func (d *DriverSqlite) TableFields(table string, schema ...string) (fields map[s
 			}
 			fields = make(map[string]*TableField)
 			for i, m := range result {
+				key := ""
+				if m["pk"].Int() > 0 {
+					key = "pri"
+				}
 				fields[strings.ToLower(m["name"].String())] = &TableField{
 					Index: i,
 					Name:  strings.ToLower(m["name"].String()),
 					Type:  strings.ToLower(m["type"].String()),
+					Key:   key,
 				}
 			}
 			return fields, nil
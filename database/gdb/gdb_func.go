@@ -506,7 +506,11 @@ func formatWhere(db DB, where interface{}, args []interface{}, omitEmpty bool) (
 			} else if gregex.IsMatchString(regularFieldNameRegPattern, newWhere) {
 				newWhere = db.QuoteString(newWhere)
 				if len(newArgs) > 0 {
-					if utils.IsArray(newArgs[0]) {
+					if _, ok := newArgs[0].(*Model); ok {
+						// Eg:
+						// Where("id", db.Model("user").Fields("id"))
+						newWhere += " IN (?)"
+					} else if utils.IsArray(newArgs[0]) {
 						// Eg:
 						// Where("id", []int{1,2,3})
 						// Where("user.id", []int{1,2,3})
@@ -569,6 +573,20 @@ func formatWhereKeyValue(db DB, buffer *bytes.Buffer, newArgs []interface{}, key
 		rv   = reflect.ValueOf(value)
 		kind = rv.Kind()
 	)
+	if _, ok := value.(*Model); ok {
+		// Sub-query model, which is handled by function handleArguments.
+		if gstr.Pos(quotedKey, "?") == -1 {
+			if gregex.IsMatchString(regularFieldNameRegPattern, key) {
+				// Eg: Where(g.Map{"id": db.Model("user").Fields("id")})
+				quotedKey += " IN(?)"
+			} else {
+				// Eg: Where(g.Map{"score >": db.Model("score").Fields("AVG(score)")})
+				quotedKey += " (?)"
+			}
+		}
+		buffer.WriteString(quotedKey)
+		return append(newArgs, value)
+	}
 	switch kind {
 	case reflect.Slice, reflect.Array:
 		count := gstr.Count(quotedKey, "?")
@@ -629,6 +647,31 @@ func formatWhereKeyValue(db DB, buffer *bytes.Buffer, newArgs []interface{}, key
 	return newArgs
 }
 
+// getSubQueryError returns the error of the sub-query models in `values`, which are the conditions or
+// arguments possibly containing sub-query models, including the ones in map conditions.
+// It returns nil if there's no failed sub-query model.
+func getSubQueryError(values ...interface{}) error {
+	for _, value := range values {
+		switch v := value.(type) {
+		case *Model:
+			if v != nil && v.err != nil {
+				return v.err
+			}
+		case []interface{}:
+			if err := getSubQueryError(v...); err != nil {
+				return err
+			}
+		case Map:
+			for _, item := range v {
+				if err := getSubQueryError(item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // handleArguments is an important function, which handles the sql and all its arguments
 // before committing them to underlying driver.
 func handleArguments(sql string, args []interface{}) (newSql string, newArgs []interface{}) {
@@ -638,6 +681,30 @@ func handleArguments(sql string, args []interface{}) (newSql string, newArgs []i
 	// Handles the slice arguments.
 	if len(args) > 0 {
 		for index, arg := range args {
+			// Sub-query model, which replaces its '?' holder with its SELECT statement,
+			// and inserts its arguments at the position of the holder.
+			// Eg: Where("id IN(?)", db.Model("user").Fields("id").Where("status", 1))
+			if subModel, ok := arg.(*Model); ok {
+				var (
+					counter  = 0
+					replaced = false
+				)
+				subSql, subArgs := subModel.getFormattedSubQuery()
+				newSql, _ = gregex.ReplaceStringFunc(`\?`, newSql, func(s string) string {
+					if replaced {
+						return s
+					}
+					counter++
+					if counter == index+insertHolderCount+1 {
+						replaced = true
+						insertHolderCount += len(subArgs) - 1
+						return subSql
+					}
+					return s
+				})
+				newArgs = append(newArgs, subArgs...)
+				continue
+			}
 			var (
 				reflectValue = reflect.ValueOf(arg)
 				reflectKind  = reflectValue.Kind()
@@ -0,0 +1,520 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/container/gtype"
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/os/gres"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/util/gconv"
+	"github.com/gogf/gf/util/guid"
+)
+
+// MigrationFunc is the function applying or reverting a migration, which is called in a transaction.
+type MigrationFunc func(tx *TX) error
+
+// Migration is a versioned schema migration with its up and down operations.
+// The operations can be defined using Go functions or sql statements,
+// and the function takes precedence over the sql statements if both are given.
+type Migration struct {
+	Version int64         // Unique version which also determines the migrating order, like: 20210101120000.
+	Name    string        // Brief name of the migration, like: create_user_table.
+	Up      MigrationFunc // Function that applies the migration.
+	Down    MigrationFunc // Function that reverts the migration.
+	UpSql   string        // Sql statements that apply the migration, multiple statements are separated by ';'.
+	DownSql string        // Sql statements that revert the migration, multiple statements are separated by ';'.
+}
+
+// MigrationStatus is the applying status of a migration.
+type MigrationStatus struct {
+	Version    int64       // Version of the migration.
+	Name       string      // Name of the migration.
+	Applied    bool        // Whether the migration has been applied.
+	AppliedAt  *gtime.Time // The time when the migration was applied, it is nil if it's not applied.
+	Registered bool        // Whether the migration is registered, an applied migration might be not registered any longer.
+}
+
+// Migrator manages and applies versioned migrations for a database.
+// It records the applied migrations in a migrations table, and uses a lock table to make sure
+// that only one instance is migrating the database at the same time.
+//
+// Note that the migrations registering is not concurrent-safe,
+// which should be done before any migrating operations.
+type Migrator struct {
+	db          DB                   // Underlying database for migrating.
+	table       string               // Table name for recording the applied migrations.
+	lockTimeout time.Duration        // Max duration for waiting the migrating lock, also for expiring a stale lock.
+	migrations  map[int64]*Migration // Registered migrations, the key is the migration version.
+	dryRun      bool                 // If true, it only logs the migrations but changes nothing in database.
+}
+
+const (
+	defaultMigrationTable        = "gf_migrations"
+	defaultMigrationLockTimeout  = time.Minute
+	migrationLockTableSuffix     = "_lock"
+	migrationLockId              = 1
+	migrationLockRetryInterval   = 500 * time.Millisecond
+	migrationLockRefreshRatio    = 3 // The lock is refreshed every 1/3 of the lock timeout.
+	migrationFileNameRegPattern  = `^(\d+)_(.+)\.(up|down)\.sql$`
+	migrationDirectionUp         = "up"
+	migrationDirectionDown       = "down"
+	migrationTableCreateTemplate = `
+CREATE TABLE %s (
+	version    BIGINT NOT NULL PRIMARY KEY,
+	name       VARCHAR(255) NOT NULL,
+	applied_at VARCHAR(64) NOT NULL
+)`
+	migrationLockTableCreateTemplate = `
+CREATE TABLE %s (
+	id        INT NOT NULL PRIMARY KEY,
+	owner     VARCHAR(64) NOT NULL,
+	locked_at BIGINT NOT NULL
+)`
+)
+
+// NewMigrator creates and returns a migrator for database `db`.
+func NewMigrator(db DB) *Migrator {
+	return &Migrator{
+		db:          db,
+		table:       defaultMigrationTable,
+		lockTimeout: defaultMigrationLockTimeout,
+		migrations:  make(map[int64]*Migration),
+	}
+}
+
+// SetTable sets the table name for recording the applied migrations, which is "gf_migrations" in default.
+// The lock table is named with suffix "_lock" of this table name.
+func (m *Migrator) SetTable(table string) {
+	m.table = table
+}
+
+// SetLockTimeout sets the max duration for waiting the migrating lock, which is one minute in default.
+// A lock that is not refreshed in this duration is considered stale, in case that its holder crashed,
+// and it is removed automatically. The lock is refreshed periodically while migrating, and the migrating
+// is aborted if the lock is lost, which might be removed as a stale lock and taken by others.
+func (m *Migrator) SetLockTimeout(timeout time.Duration) {
+	m.lockTimeout = timeout
+}
+
+// DryRun enables or disables the dry-run feature, which is enabled if `enabled` is not given.
+// In dry-run mode, Up/Down only logs and returns the migrations that would be applied or reverted,
+// but changes nothing in database.
+func (m *Migrator) DryRun(enabled ...bool) *Migrator {
+	if len(enabled) > 0 {
+		m.dryRun = enabled[0]
+	} else {
+		m.dryRun = true
+	}
+	return m
+}
+
+// Register registers one or more migrations to the migrator.
+// It returns error if the migration version is not positive or already registered.
+func (m *Migrator) Register(migrations ...*Migration) error {
+	for _, migration := range migrations {
+		if migration.Version <= 0 {
+			return gerror.Newf(`invalid migration version "%d", it should be positive`, migration.Version)
+		}
+		if _, ok := m.migrations[migration.Version]; ok {
+			return gerror.Newf(`migration version "%d" is already registered`, migration.Version)
+		}
+		m.migrations[migration.Version] = migration
+	}
+	return nil
+}
+
+// LoadResource loads the sql migration files from resource manager gres in directory `path`.
+// The migration file should be named as "{version}_{name}.up.sql" or "{version}_{name}.down.sql",
+// like: "20210101120000_create_user_table.up.sql", "20210101120000_create_user_table.down.sql".
+func (m *Migrator) LoadResource(path string) error {
+	loaded := make(map[int64]*Migration)
+	for _, file := range gres.ScanDirFile(path, "*.sql") {
+		match, _ := gregex.MatchString(migrationFileNameRegPattern, gfile.Basename(file.Name()))
+		if len(match) == 0 {
+			continue
+		}
+		var (
+			version   = gconv.Int64(match[1])
+			migration = loaded[version]
+		)
+		if migration == nil {
+			migration = &Migration{
+				Version: version,
+				Name:    match[2],
+			}
+			loaded[version] = migration
+		}
+		switch match[3] {
+		case migrationDirectionUp:
+			migration.UpSql = string(file.Content())
+		case migrationDirectionDown:
+			migration.DownSql = string(file.Content())
+		}
+	}
+	for _, migration := range loaded {
+		if err := m.Register(migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Up applies all the registered migrations that are not applied yet in version ascending order.
+// Each migration is applied in a transaction with its recording in migrations table.
+// It returns the applied migrations, and stops at the first failed migration.
+func (m *Migrator) Up() (migrations []*Migration, err error) {
+	err = m.doWithLock(func(ctx context.Context) error {
+		appliedMap, err := m.getAppliedMap()
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.getSortedMigrations() {
+			if _, ok := appliedMap[migration.Version]; ok {
+				continue
+			}
+			if err = m.doMigrate(ctx, migration, migrationDirectionUp); err != nil {
+				return err
+			}
+			migrations = append(migrations, migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Down reverts the last `n` applied migrations in version descending order.
+// Each migration is reverted in a transaction with its record removing from migrations table.
+// It returns the reverted migrations, and stops at the first failed migration.
+func (m *Migrator) Down(n int) (migrations []*Migration, err error) {
+	if n <= 0 {
+		return nil, gerror.Newf(`invalid migration count "%d" for reverting, it should be positive`, n)
+	}
+	err = m.doWithLock(func(ctx context.Context) error {
+		appliedMap, err := m.getAppliedMap()
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(appliedMap))
+		for version := range appliedMap {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+		for i := 0; i < n && i < len(versions); i++ {
+			migration, ok := m.migrations[versions[i]]
+			if !ok {
+				return gerror.Newf(`applied migration version "%d" is not registered`, versions[i])
+			}
+			if err = m.doMigrate(ctx, migration, migrationDirectionDown); err != nil {
+				return err
+			}
+			migrations = append(migrations, migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Status retrieves and returns the status of all registered and applied migrations,
+// which is ordered by version ascending.
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	appliedMap, err := m.getAppliedMap()
+	if err != nil {
+		return nil, err
+	}
+	statusMap := make(map[int64]*MigrationStatus)
+	for version, migration := range m.migrations {
+		statusMap[version] = &MigrationStatus{
+			Version:    version,
+			Name:       migration.Name,
+			Registered: true,
+		}
+	}
+	for version, record := range appliedMap {
+		status, ok := statusMap[version]
+		if !ok {
+			status = &MigrationStatus{
+				Version: version,
+				Name:    record["name"].String(),
+			}
+			statusMap[version] = status
+		}
+		status.Applied = true
+		status.AppliedAt = record["applied_at"].GTime()
+	}
+	statusArray := make([]*MigrationStatus, 0, len(statusMap))
+	for _, status := range statusMap {
+		statusArray = append(statusArray, status)
+	}
+	sort.Slice(statusArray, func(i, j int) bool {
+		return statusArray[i].Version < statusArray[j].Version
+	})
+	return statusArray, nil
+}
+
+// doMigrate applies or reverts given `migration` according to `direction` in a transaction using `ctx`,
+// and updates its record in migrations table.
+func (m *Migrator) doMigrate(ctx context.Context, migration *Migration, direction string) error {
+	var (
+		migrationFunc MigrationFunc
+		migrationSql  string
+	)
+	if direction == migrationDirectionUp {
+		migrationFunc, migrationSql = migration.Up, migration.UpSql
+	} else {
+		migrationFunc, migrationSql = migration.Down, migration.DownSql
+	}
+	if migrationFunc == nil && strings.TrimSpace(migrationSql) == "" {
+		return gerror.Newf(
+			`migration "%d_%s" has no %s operation`, migration.Version, migration.Name, direction,
+		)
+	}
+	if m.dryRun {
+		if migrationFunc != nil {
+			migrationSql = "<go function>"
+		}
+		m.db.GetLogger().Ctx(m.db.GetCtx()).Infof(
+			`[dry-run] migrate %s "%d_%s": %s`,
+			direction, migration.Version, migration.Name, strings.TrimSpace(migrationSql),
+		)
+		return nil
+	}
+	err := m.db.Ctx(ctx).Transaction(func(tx *TX) error {
+		if migrationFunc != nil {
+			if err := migrationFunc(tx); err != nil {
+				return err
+			}
+		} else {
+			for _, statement := range splitSqlStatements(migrationSql) {
+				if _, err := tx.Exec(statement); err != nil {
+					return err
+				}
+			}
+		}
+		if direction == migrationDirectionUp {
+			_, err := tx.Model(m.table).Data(Map{
+				"version":    migration.Version,
+				"name":       migration.Name,
+				"applied_at": gtime.Now().String(),
+			}).Insert()
+			return err
+		}
+		_, err := tx.Model(m.table).Where("version", migration.Version).Delete()
+		return err
+	})
+	if err != nil {
+		return gerror.Wrapf(err, `migrate %s "%d_%s" failed`, direction, migration.Version, migration.Name)
+	}
+	return nil
+}
+
+// doWithLock calls `f` with the migrating lock held, which makes sure that only one instance
+// is migrating the database at the same time. The lock is refreshed periodically while `f` is running,
+// in case that the lock is considered stale during long migrations.
+//
+// The context `ctx` passed to `f` is canceled if the lock is lost, which rolls back the migrating
+// transaction using the context, and it then returns the error of the lost lock.
+// It does not lock anything in dry-run mode.
+func (m *Migrator) doWithLock(f func(ctx context.Context) error) error {
+	if m.dryRun {
+		return f(m.db.GetCtx())
+	}
+	if err := m.ensureTable(m.table, migrationTableCreateTemplate); err != nil {
+		return err
+	}
+	lockTable := m.table + migrationLockTableSuffix
+	if err := m.ensureTable(lockTable, migrationLockTableCreateTemplate); err != nil {
+		return err
+	}
+	var (
+		owner    = guid.S()
+		deadline = time.Now().Add(m.lockTimeout)
+	)
+	for {
+		_, err := m.db.Model(lockTable).Data(Map{
+			"id":        migrationLockId,
+			"owner":     owner,
+			"locked_at": gtime.Timestamp(),
+		}).Insert()
+		if err == nil {
+			break
+		}
+		// Remove the stale lock whose holder might have crashed.
+		_, _ = m.db.Model(lockTable).
+			Where("id", migrationLockId).
+			Where("locked_at<?", gtime.Timestamp()-int64(m.lockTimeout/time.Second)).
+			Delete()
+		if time.Now().After(deadline) {
+			return gerror.Wrapf(err, `acquiring migration lock timeout after %s`, m.lockTimeout)
+		}
+		time.Sleep(migrationLockRetryInterval)
+	}
+	defer func() {
+		if _, err := m.db.Model(lockTable).Where("id", migrationLockId).Where("owner", owner).Delete(); err != nil {
+			m.db.GetLogger().Ctx(m.db.GetCtx()).Error(err)
+		}
+	}()
+	var (
+		ctx, cancel = context.WithCancel(m.db.GetCtx())
+		done        = make(chan struct{})
+		lockErr     = gtype.NewInterface()
+	)
+	defer cancel()
+	refreshInterval := m.lockTimeout / migrationLockRefreshRatio
+	if refreshInterval <= 0 {
+		refreshInterval = migrationLockRetryInterval
+	}
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := m.refreshLock(lockTable, owner); err != nil {
+					lockErr.Set(err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	err := f(ctx)
+	close(done)
+	if v := lockErr.Val(); v != nil {
+		return gerror.Wrap(v.(error), `migrating aborted`)
+	}
+	return err
+}
+
+// refreshLock refreshes the locked time of the migrating lock held by `owner`.
+// It returns error if the lock is not held by `owner` any longer.
+func (m *Migrator) refreshLock(lockTable, owner string) error {
+	result, err := m.db.Model(lockTable).
+		Data("locked_at", gtime.Timestamp()).
+		Where("id", migrationLockId).
+		Where("owner", owner).
+		Update()
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	// No row is affected if the locked time is not changed in the same second for some databases,
+	// so it checks whether the lock still exists.
+	count, err := m.db.Model(lockTable).Where("id", migrationLockId).Where("owner", owner).Count()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return gerror.New(`migration lock is lost, it might be removed as a stale lock and taken by others`)
+	}
+	return nil
+}
+
+// getAppliedMap retrieves and returns the applied migration records from migrations table,
+// the key of the returned map is the migration version.
+// It returns an empty map if the migrations table does not exist.
+func (m *Migrator) getAppliedMap() (map[int64]Record, error) {
+	appliedMap := make(map[int64]Record)
+	exists, err := m.tableExists(m.table)
+	if err != nil || !exists {
+		return appliedMap, err
+	}
+	result, err := m.db.Model(m.table).Order("version asc").All()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range result {
+		appliedMap[record["version"].Int64()] = record
+	}
+	return appliedMap, nil
+}
+
+// getSortedMigrations returns the registered migrations in version ascending order.
+func (m *Migrator) getSortedMigrations() []*Migration {
+	migrations := make([]*Migration, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+// ensureTable creates the table `table` using `createTemplate` if it does not exist.
+func (m *Migrator) ensureTable(table string, createTemplate string) error {
+	exists, err := m.tableExists(table)
+	if err != nil || exists {
+		return err
+	}
+	_, err = m.db.Exec(fmt.Sprintf(createTemplate, m.db.QuotePrefixTableName(table)))
+	if err != nil {
+		// The table might be created by another instance at the same time.
+		if exists, _ = m.tableExists(table); exists {
+			return nil
+		}
+	}
+	return err
+}
+
+// tableExists checks and returns whether the table `table` exists in current schema.
+func (m *Migrator) tableExists(table string) (bool, error) {
+	tables, err := m.db.Tables()
+	if err != nil {
+		return false, err
+	}
+	table = m.db.GetPrefix() + table
+	for _, v := range tables {
+		if strings.EqualFold(v, table) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// splitSqlStatements splits the sql content `content` into separate statements by char ';',
+// ignoring the ';' in quoted strings and identifiers. The empty statements are removed.
+func splitSqlStatements(content string) []string {
+	var (
+		statements = make([]string, 0)
+		quoteChar  rune
+		start      = 0
+	)
+	for i, c := range content {
+		switch {
+		case quoteChar != 0:
+			if c == quoteChar {
+				quoteChar = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quoteChar = c
+		case c == ';':
+			if s := strings.TrimSpace(content[start:i]); s != "" {
+				statements = append(statements, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(content[start:]); s != "" {
+		statements = append(statements, s)
+	}
+	return statements
+}
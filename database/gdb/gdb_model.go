@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gconv"
 )
 
 // Model is the DAO for ORM.
@@ -23,10 +24,13 @@ type Model struct {
 	linkType      int            // Mark for operation on master or slave.
 	tablesInit    string         // Table names when model initialization.
 	tables        string         // Operation table names, which can be more than one table names and aliases, like: "user", "user u", "user u, user_detail ud".
+	tablesArgs    []interface{}  // Arguments for sub-query tables, like: "(SELECT * FROM user WHERE id>?) AS u".
 	fields        string         // Operation fields, multiple fields joined using char ','.
+	fieldsArgs    []interface{}  // Arguments for sub-query fields, like: "(SELECT COUNT(1) FROM user WHERE id>?) AS total".
 	fieldsEx      string         // Excluded operation fields, multiple fields joined using char ','.
-	withArray     []interface{}  // Arguments for With feature.
+	withArray     []*withItem    // Arguments for With feature.
 	withAll       bool           // Enable model association operations on all objects that have "with" tag in the struct.
+	withChain     []string       // Struct types of the parent relations, which prevents circular relation loading.
 	extraArgs     []interface{}  // Extra custom arguments for sql.
 	whereHolder   []*whereHolder // Condition strings for where operation.
 	groupBy       string         // Used for "group by" statement.
@@ -38,12 +42,18 @@ type Model struct {
 	offset        int            // Offset statement for some databases grammar.
 	data          interface{}    // Data for operation, which can be type of map/[]map/struct/*struct/string, etc.
 	batch         int            // Batch number for batch Insert/Replace/Save operations.
+	onConflict    []string       // Conflict columns for upsert of Save operation, which are required by pgsql/sqlite.
+	onDuplicate   interface{}    // onDuplicate is used for upsert of Save operation, specifying the updating fields.
+	onDuplicateEx interface{}    // onDuplicateEx is used for upsert of Save operation, specifying the excluded updating fields.
 	filter        bool           // Filter data and where key-value pairs according to the fields of the table.
 	lockInfo      string         // Lock for update or in shared lock.
 	cacheEnabled  bool           // Enable sql result cache feature.
 	cacheDuration time.Duration  // Cache TTL duration.
 	cacheName     string         // Cache name for custom operation.
+	hookHandler   HookHandler    // Hook functions for model hook feature.
+	shardingRule  ShardingRule   // Sharding rule for routing the table to physical tables and groups.
 	unscoped      bool           // Disables soft deleting features when select/delete operations.
+	err           error          // Error occurred when building the model, which fails the operations of the model.
 	safe          bool           // If true, it clones and returns a new model object whenever operation done; or else it changes the attribute of current model.
 }
 
@@ -64,6 +74,13 @@ const (
 	whereHolderWhere  = 1
 	whereHolderAnd    = 2
 	whereHolderOr     = 3
+	queryTypeNormal   = 0
+	queryTypeCount    = 1
+	unionTypeNormal   = 0
+	unionTypeAll      = 1
+
+	subQueryDefaultAlias = "sub_alias"   // Default alias name for sub-query table.
+	unionDefaultAlias    = "union_alias" // Alias name for the union result table.
 )
 
 // Table is alias of Core.Model.
@@ -128,12 +145,17 @@ func (tx *TX) With(object interface{}) *Model {
 }
 
 // Ctx sets the context for current operation.
+// If the context carries a transaction of the same configuration group, which is injected by
+// WithTX or comes from TX.GetCtx, the following operations of the model join the transaction.
 func (m *Model) Ctx(ctx context.Context) *Model {
 	if ctx == nil {
 		return m
 	}
 	model := m.getModel()
 	model.db = model.db.Ctx(ctx)
+	if model.tx == nil {
+		model.tx = TXFromCtx(ctx, model.db.GetGroup())
+	}
 	return model
 }
 
@@ -156,6 +178,35 @@ func (m *Model) As(as string) *Model {
 	return m
 }
 
+// Table sets/changes the operation table of current model, which can be a table name string or
+// a sub-query Model. The optional parameter `as` specifies the alias name for the table, which is
+// necessary for sub-query table and defaults to "sub_alias" if not given.
+// Eg:
+// Table("user")
+// Table("user", "u")
+// Table(db.Model("user").Where("status", 1), "u")
+func (m *Model) Table(table interface{}, as ...string) *Model {
+	model := m.getModel()
+	switch v := table.(type) {
+	case *Model:
+		alias := subQueryDefaultAlias
+		if len(as) > 0 && as[0] != "" {
+			alias = as[0]
+		}
+		subSql, subArgs := v.getFormattedSubQuery()
+		model.checkSubQueryError(v)
+		model.tables = fmt.Sprintf(`(%s) AS %s`, subSql, m.db.QuoteWord(alias))
+		model.tablesArgs = subArgs
+	default:
+		model.tables = m.db.QuotePrefixTableName(gconv.String(table))
+		if len(as) > 0 && as[0] != "" {
+			model.tables = fmt.Sprintf(`%s AS %s`, model.tables, m.db.QuoteWord(as[0]))
+		}
+		model.tablesArgs = nil
+	}
+	return model
+}
+
 // DB sets/changes the db object for current operation.
 func (m *Model) DB(db DB) *Model {
 	model := m.getModel()
@@ -171,6 +222,17 @@ func (m *Model) TX(tx *TX) *Model {
 	return model
 }
 
+// Transaction wraps the transaction logic using function `f`.
+// If the model is operating on a transaction, or its context carries a transaction of the same
+// configuration group, it creates a nested transaction using savepoint instead of starting a
+// new one. See DB.Transaction and TX.Transaction.
+func (m *Model) Transaction(f func(tx *TX) error) (err error) {
+	if m.tx != nil {
+		return m.tx.Transaction(f)
+	}
+	return m.db.Transaction(f)
+}
+
 // Schema sets the schema for current operation.
 func (m *Model) Schema(schema string) *Model {
 	model := m.getModel()
@@ -189,6 +251,14 @@ func (m *Model) Clone() *Model {
 	}
 	*newModel = *m
 	// Shallow copy slice attributes.
+	if n := len(m.tablesArgs); n > 0 {
+		newModel.tablesArgs = make([]interface{}, n)
+		copy(newModel.tablesArgs, m.tablesArgs)
+	}
+	if n := len(m.fieldsArgs); n > 0 {
+		newModel.fieldsArgs = make([]interface{}, n)
+		copy(newModel.fieldsArgs, m.fieldsArgs)
+	}
 	if n := len(m.extraArgs); n > 0 {
 		newModel.extraArgs = make([]interface{}, n)
 		copy(newModel.extraArgs, m.extraArgs)
@@ -198,7 +268,7 @@ func (m *Model) Clone() *Model {
 		copy(newModel.whereHolder, m.whereHolder)
 	}
 	if n := len(m.withArray); n > 0 {
-		newModel.withArray = make([]interface{}, n)
+		newModel.withArray = make([]*withItem, n)
 		copy(newModel.withArray, m.withArray)
 	}
 	return newModel
@@ -7,7 +7,35 @@
 package gdb
 
 import (
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/gogf/gf/container/gset"
+	"github.com/gogf/gf/internal/intlog"
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/guid"
+)
+
+// selectCacheItem is the cached item of selecting result, along with the versions of the tags
+// of the tables that the result touches.
+type selectCacheItem struct {
+	Result Result            `json:"result"` // Selecting result.
+	Tags   map[string]string `json:"tags"`   // Versions of the tags when the result is cached, which is tag key to version.
+}
+
+const (
+	// cacheTagKeyPrefix is the key prefix of cache tags, which store the versions of tables.
+	// The version of a table changes when it is written, which invalidates the cached
+	// selecting results touching the table.
+	cacheTagKeyPrefix = "gdb:cache:tag:"
+)
+
+var (
+	// cacheTagTableRegPattern is the regular expression pattern for retrieving table names from sql,
+	// which matches the table list after keyword FROM, or the table after keyword JOIN.
+	cacheTagTableRegPattern = `(?i)\b(FROM|JOIN)\s+([^()]+?)(\s+(WHERE|GROUP|ORDER|LIMIT|HAVING|UNION|ON|FOR|LOCK|LEFT|RIGHT|INNER|CROSS|FULL|OUTER|JOIN)\b|\)|$)`
 )
 
 // Cache sets the cache feature for the model. It caches the result of the sql, which means
@@ -22,6 +50,13 @@ import (
 // later control the cache like changing the `duration` or clearing the cache with specified
 // `name`.
 //
+// The cached result is tagged with the tables it touches, including the joined tables,
+// sub-queries and the relation tables of With feature, and it is invalidated automatically
+// if any Insert/Update/Delete operation of Model is performed on these tables, or after the
+// transaction of the operation ends. The tags are stored as versions in the cache of the database, so it also works with custom
+// cache adapter.
+// Note that the raw sql executed by Exec does not remove the cache.
+//
 // Note that, the cache feature is disabled if the model is performing select statement
 // on a transaction.
 func (m *Model) Cache(duration time.Duration, name ...string) *Model {
@@ -35,9 +70,114 @@ func (m *Model) Cache(duration time.Duration, name ...string) *Model {
 }
 
 // checkAndRemoveCache checks and removes the cache in insert/update/delete statement if
-// cache feature is enabled.
+// cache feature is enabled. It also removes the cached results that are tagged with
+// the table of the model.
 func (m *Model) checkAndRemoveCache() {
 	if m.cacheEnabled && m.cacheDuration < 0 && len(m.cacheName) > 0 {
 		m.db.GetCache().Ctx(m.db.GetCtx()).Remove(m.cacheName)
 	}
+	m.removeCacheByTags(getTableNamesFromSql("FROM "+m.tables, m.db))
+}
+
+// getCacheTags retrieves and returns the current versions of the tags of the tables touched by `sql`,
+// which are stored along with the cached selecting result.
+func (m *Model) getCacheTags(sql string) map[string]string {
+	var (
+		cacheObj = m.db.GetCache().Ctx(m.db.GetCtx())
+		tags     = make(map[string]string)
+	)
+	for _, table := range getTableNamesFromSql(sql, m.db) {
+		tagKey := m.getCacheTagKey(table)
+		v, err := cacheObj.GetVar(tagKey)
+		if err != nil {
+			intlog.Error(err)
+		}
+		tags[tagKey] = v.String()
+	}
+	return tags
+}
+
+// isCacheTagsValid checks and returns whether the versions of tags `tags` are still the current ones,
+// which means the tables of the cached selecting result are not written after it is cached.
+func (m *Model) isCacheTagsValid(tags map[string]string) bool {
+	cacheObj := m.db.GetCache().Ctx(m.db.GetCtx())
+	for tagKey, version := range tags {
+		v, err := cacheObj.GetVar(tagKey)
+		if err != nil {
+			intlog.Error(err)
+			return false
+		}
+		if v.String() != version {
+			return false
+		}
+	}
+	return true
+}
+
+// removeCacheByTags invalidates the cached selecting results that touch `tables` by changing
+// the versions of their tags, which does not need reading or locking the tags.
+// The versions are always changed, as the results might be cached by other processes sharing
+// the cache adapter, like redis.
+//
+// If the model is operating on a transaction, the versions are changed after the transaction is
+// committed or rolled back, in case that the uncommitted data is read and cached by others.
+func (m *Model) removeCacheByTags(tables []string) {
+	if m.tx != nil {
+		m.tx.addCacheTagTables(tables)
+		return
+	}
+	removeCacheByTags(m.db, tables)
+}
+
+// getCacheTagKey returns the cache key of the tag for `table`.
+func (m *Model) getCacheTagKey(table string) string {
+	return getCacheTagKey(m.db, table)
+}
+
+// removeCacheByTags changes the versions of the tags of `tables` in the cache of `db`.
+func removeCacheByTags(db DB, tables []string) {
+	var (
+		cacheObj = db.GetCache().Ctx(db.GetCtx())
+		version  = guid.S()
+	)
+	for _, table := range tables {
+		if err := cacheObj.Set(getCacheTagKey(db, table), version, 0); err != nil {
+			intlog.Error(err)
+		}
+	}
+}
+
+// getCacheTagKey returns the cache key of the tag for `table` of `db`.
+func getCacheTagKey(db DB, table string) string {
+	return fmt.Sprintf(`%s%s:%s`, cacheTagKeyPrefix, db.GetGroup(), table)
+}
+
+// getTableNamesFromSql retrieves and returns the table names that `sql` touches, which are
+// the tables after keyword FROM or JOIN, including the ones of sub-queries. The returned
+// table names are unquoted and lower-cased, with their schema removed.
+// Eg:
+// SELECT * FROM `user` u LEFT JOIN `user_detail` ud ON (ud.uid=u.uid) => [user user_detail]
+// SELECT * FROM `user` u,`user_detail` ud WHERE ud.uid=u.uid        => [user user_detail]
+func getTableNamesFromSql(sql string, db DB) []string {
+	var (
+		charLeft, charRight = db.GetChars()
+		tableSet            = gset.NewStrSet()
+		tableNames          = make([]string, 0)
+	)
+	match, _ := gregex.MatchAllString(cacheTagTableRegPattern, sql)
+	for _, v := range match {
+		for _, item := range gstr.SplitAndTrim(v[2], ",") {
+			// Removes the alias name.
+			table := strings.Fields(item)[0]
+			// Removes the schema.
+			if pos := gstr.PosR(table, "."); pos != -1 {
+				table = table[pos+1:]
+			}
+			table = strings.ToLower(gstr.Trim(table, charLeft+charRight+`"`))
+			if table != "" && tableSet.AddIfNotExist(table) {
+				tableNames = append(tableNames, table)
+			}
+		}
+	}
+	return tableNames
 }
@@ -31,6 +31,7 @@ func (m *Model) Where(where interface{}, args ...interface{}) *Model {
 		where:    where,
 		args:     args,
 	})
+	model.checkSubQueryError(where, args)
 	return model
 }
 
@@ -42,6 +43,7 @@ func (m *Model) Having(having interface{}, args ...interface{}) *Model {
 	model.having = []interface{}{
 		having, args,
 	}
+	model.checkSubQueryError(having, args)
 	return model
 }
 
@@ -69,6 +71,7 @@ func (m *Model) And(where interface{}, args ...interface{}) *Model {
 		where:    where,
 		args:     args,
 	})
+	model.checkSubQueryError(where, args)
 	return model
 }
 
@@ -83,6 +86,7 @@ func (m *Model) Or(where interface{}, args ...interface{}) *Model {
 		where:    where,
 		args:     args,
 	})
+	model.checkSubQueryError(where, args)
 	return model
 }
 
@@ -8,9 +8,7 @@ package gdb
 
 import (
 	"database/sql"
-	"fmt"
 	"github.com/gogf/gf/errors/gerror"
-	"github.com/gogf/gf/os/gtime"
 	"github.com/gogf/gf/text/gstr"
 )
 
@@ -21,28 +19,43 @@ func (m *Model) Delete(where ...interface{}) (result sql.Result, err error) {
 	if len(where) > 0 {
 		return m.Where(where[0], where[1:]...).Delete()
 	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.shardingRule != nil {
+		return m.doShardingDelete()
+	}
 	defer func() {
 		if err == nil {
 			m.checkAndRemoveCache()
 		}
 	}()
 	var (
-		fieldNameDelete                               = m.getSoftFieldNameDeleted()
 		conditionWhere, conditionExtra, conditionArgs = m.formatCondition(false, false)
+		conditionStr                                  = conditionWhere + conditionExtra
 	)
-	// Soft deleting.
-	if !m.unscoped && fieldNameDelete != "" {
-		return m.db.DoUpdate(
-			m.getLink(true),
-			m.tables,
-			fmt.Sprintf(`%s=?`, m.db.QuoteString(fieldNameDelete)),
-			conditionWhere+conditionExtra,
-			append([]interface{}{gtime.Now().String()}, conditionArgs...),
-		)
-	}
-	conditionStr := conditionWhere + conditionExtra
 	if !gstr.ContainsI(conditionStr, " WHERE ") {
 		return nil, gerror.New("there should be WHERE condition statement for DELETE operation")
 	}
-	return m.db.DoDelete(m.getLink(true), m.tables, conditionStr, conditionArgs...)
+	in := &HookDeleteInput{
+		internalParamHookDelete: internalParamHookDelete{
+			internalParamHook: internalParamHook{
+				link: m.getLink(true),
+			},
+			handler: m.hookHandler.Delete,
+		},
+		Model:     m,
+		Table:     m.tables,
+		Condition: conditionStr,
+		Args:      conditionArgs,
+	}
+	return in.Next(m.db.GetCtx())
+}
+
+// ForceDelete does "DELETE FROM ... " statement for the model, which deletes the records
+// permanently even if soft deleting feature is enabled for the table.
+// The optional parameter `where` is the same as the parameter of Model.Where function,
+// see Model.Where.
+func (m *Model) ForceDelete(where ...interface{}) (result sql.Result, err error) {
+	return m.Unscoped().Delete(where...)
 }
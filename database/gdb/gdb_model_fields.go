@@ -27,20 +27,34 @@ func (m *Model) Filter() *Model {
 
 // Fields sets the operation fields of the model, multiple fields joined using char ','.
 // The parameter `fieldNamesOrMapStruct` can be type of string/map/*map/struct/*struct.
+//
+// It also supports sub-query fields, in which the first parameter is a string containing '?' holders
+// and the following parameters are its arguments, which can be sub-query Model, like:
+// Fields("id, (?) AS total", db.Model("user_score").Fields("COUNT(1)").Where("uid=user.id"))
 func (m *Model) Fields(fieldNamesOrMapStruct ...interface{}) *Model {
 	length := len(fieldNamesOrMapStruct)
 	if length == 0 {
 		return m
 	}
 	switch {
+	// Fields with holders and arguments.
+	case length >= 2 && gstr.Contains(gconv.String(fieldNamesOrMapStruct[0]), "?"):
+		model := m.getModel()
+		model.fields, model.fieldsArgs = handleArguments(
+			gconv.String(fieldNamesOrMapStruct[0]), fieldNamesOrMapStruct[1:],
+		)
+		model.checkSubQueryError(fieldNamesOrMapStruct[1:])
+		return model
 	// String slice.
 	case length >= 2:
 		model := m.getModel()
 		model.fields = gstr.Join(m.mappingAndFilterToTableFields(gconv.Strings(fieldNamesOrMapStruct), true), ",")
+		model.fieldsArgs = nil
 		return model
 	// It need type asserting.
 	case length == 1:
 		model := m.getModel()
+		model.fieldsArgs = nil
 		switch r := fieldNamesOrMapStruct[0].(type) {
 		case string:
 			model.fields = gstr.Join(m.mappingAndFilterToTableFields([]string{r}, false), ",")
@@ -0,0 +1,175 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type (
+	HookFuncSelect func(ctx context.Context, in *HookSelectInput) (result Result, err error)
+	HookFuncInsert func(ctx context.Context, in *HookInsertInput) (result sql.Result, err error)
+	HookFuncUpdate func(ctx context.Context, in *HookUpdateInput) (result sql.Result, err error)
+	HookFuncDelete func(ctx context.Context, in *HookDeleteInput) (result sql.Result, err error)
+)
+
+// HookHandler manages all supported hook functions for Model.
+// Each hook function receives the structured operation of the model, which it can modify
+// before calling its Next function to commit the operation to the database.
+// It can also modify the result returned by Next, or abort the operation by returning
+// an error without calling Next.
+type HookHandler struct {
+	Select HookFuncSelect
+	Insert HookFuncInsert
+	Update HookFuncUpdate
+	Delete HookFuncDelete
+}
+
+// internalParamHook manages all internal parameters for hook operations.
+// The `internal` obviously means you cannot access these parameters outside this package.
+type internalParamHook struct {
+	link          Link // Connection object from third party sql driver.
+	handlerCalled bool // Simple mark for custom handler called, in case that recursive calling.
+}
+
+type internalParamHookSelect struct {
+	internalParamHook
+	handler HookFuncSelect
+}
+
+type internalParamHookInsert struct {
+	internalParamHook
+	handler HookFuncInsert
+	batch   bool
+}
+
+type internalParamHookUpdate struct {
+	internalParamHook
+	handler HookFuncUpdate
+}
+
+type internalParamHookDelete struct {
+	internalParamHook
+	handler HookFuncDelete
+}
+
+// HookSelectInput holds the parameters for select hook operation.
+// Note that, COUNT statement will also be hooked by this feature,
+// which is usually not be interesting for upper business hook handler.
+type HookSelectInput struct {
+	internalParamHookSelect
+	Model *Model        // Current operation Model.
+	Table string        // The table name that to be used. Update this attribute to change target table name.
+	Sql   string        // The sql string that to be committed.
+	Args  []interface{} // The arguments of sql.
+}
+
+// HookInsertInput holds the parameters for insert hook operation.
+type HookInsertInput struct {
+	internalParamHookInsert
+	Model        *Model         // Current operation Model.
+	Table        string         // The table name that to be used. Update this attribute to change target table name.
+	Data         List           // The data records list to be inserted/saved into table.
+	Option       int            // The insert option: default/replace/save/ignore.
+	Batch        int            // The batch number for batch operations.
+	UpsertOption DoInsertOption // The upsert options for save operation, of which InsertOption and BatchCount are ignored.
+}
+
+// HookUpdateInput holds the parameters for update hook operation.
+type HookUpdateInput struct {
+	internalParamHookUpdate
+	Model     *Model        // Current operation Model.
+	Table     string        // The table name that to be used. Update this attribute to change target table name.
+	Data      interface{}   // Data can be type of: map[string]interface{}/string. You can use type assertion on `Data`.
+	Condition string        // The where condition string for updating, like: " WHERE id=?".
+	Args      []interface{} // The arguments for sql place-holders.
+}
+
+// HookDeleteInput holds the parameters for delete hook operation.
+type HookDeleteInput struct {
+	internalParamHookDelete
+	Model     *Model        // Current operation Model.
+	Table     string        // The table name that to be used. Update this attribute to change target table name.
+	Condition string        // The where condition string for deleting, like: " WHERE id=?".
+	Args      []interface{} // The arguments for sql place-holders.
+}
+
+// IsTransaction checks and returns whether current operation is during transaction.
+func (h *internalParamHook) IsTransaction() bool {
+	_, ok := h.link.(*sql.Tx)
+	return ok
+}
+
+// Next calls the next hook handler.
+func (h *HookSelectInput) Next(ctx context.Context) (result Result, err error) {
+	if h.handler != nil && !h.handlerCalled {
+		h.handlerCalled = true
+		return h.handler(ctx, h)
+	}
+	return h.Model.db.DoGetAll(h.link, h.Sql, h.Args...)
+}
+
+// Next calls the next hook handler.
+func (h *HookInsertInput) Next(ctx context.Context) (result sql.Result, err error) {
+	if h.handler != nil && !h.handlerCalled {
+		h.handlerCalled = true
+		return h.handler(ctx, h)
+	}
+	if !h.UpsertOption.hasUpsert() {
+		if !h.batch && len(h.Data) == 1 {
+			return h.Model.db.DoInsert(h.link, h.Table, h.Data[0], h.Option)
+		}
+		return h.Model.db.DoBatchInsert(h.link, h.Table, h.Data, h.Option, h.Batch)
+	}
+	option := h.UpsertOption
+	option.InsertOption = h.Option
+	option.BatchCount = h.Batch
+	if !h.batch && len(h.Data) == 1 {
+		return h.Model.db.DoInsertWithOption(h.link, h.Table, h.Data[0], option)
+	}
+	return h.Model.db.DoBatchInsertWithOption(h.link, h.Table, h.Data, option)
+}
+
+// Next calls the next hook handler.
+func (h *HookUpdateInput) Next(ctx context.Context) (result sql.Result, err error) {
+	if h.handler != nil && !h.handlerCalled {
+		h.handlerCalled = true
+		return h.handler(ctx, h)
+	}
+	return h.Model.db.DoUpdate(h.link, h.Table, h.Data, h.Condition, h.Args...)
+}
+
+// Next calls the next hook handler.
+func (h *HookDeleteInput) Next(ctx context.Context) (result sql.Result, err error) {
+	if h.handler != nil && !h.handlerCalled {
+		h.handlerCalled = true
+		return h.handler(ctx, h)
+	}
+	// Soft deleting.
+	if fieldNameDelete := h.Model.getSoftFieldNameDeleted(); !h.Model.unscoped && fieldNameDelete != "" {
+		fieldValueDelete := h.Model.getSoftFieldValue(h.Model.getPrimaryTableName(), fieldNameDelete, true)
+		return h.Model.db.DoUpdate(
+			h.link,
+			h.Table,
+			fmt.Sprintf(`%s=?`, h.Model.db.QuoteString(fieldNameDelete)),
+			h.Condition,
+			append([]interface{}{fieldValueDelete}, h.Args...)...,
+		)
+	}
+	return h.Model.db.DoDelete(h.link, h.Table, h.Condition, h.Args...)
+}
+
+// Hook sets the hook functions for current model.
+// The hook functions are called around the select/insert/update/delete operations of the model,
+// which can be used for audit trails, data injection or transformation, etc.
+func (m *Model) Hook(hook HookHandler) *Model {
+	model := m.getModel()
+	model.hookHandler = hook
+	return model
+}
@@ -9,7 +9,6 @@ package gdb
 import (
 	"database/sql"
 	"github.com/gogf/gf/errors/gerror"
-	"github.com/gogf/gf/os/gtime"
 	"github.com/gogf/gf/text/gstr"
 	"github.com/gogf/gf/util/gconv"
 	"github.com/gogf/gf/util/gutil"
@@ -142,8 +141,125 @@ func (m *Model) Save(data ...interface{}) (result sql.Result, err error) {
 	return m.doInsertWithOption(insertOptionSave)
 }
 
+// OnConflict sets the conflict columns for upsert of Save operation, which are used in statement
+// "INSERT INTO ... ON CONFLICT (columns) DO UPDATE SET ..." by databases like pgsql/sqlite.
+// The primary keys of the table are used as the conflict columns if it's not set.
+// It makes no sense for mysql, which uses unique/primary keys for conflict detecting automatically.
+// Eg:
+// OnConflict("passport")
+// OnConflict("uid", "type")
+// OnConflict("uid,type")
+func (m *Model) OnConflict(columns ...string) *Model {
+	model := m.getModel()
+	model.onConflict = gstr.SplitAndTrim(gstr.Join(columns, ","), ",")
+	return model
+}
+
+// OnDuplicate sets the fields that are updated when there's conflict for Save operation.
+// The parameter `onDuplicate` can be type of string/Raw/[]string/map.
+// Eg:
+// OnDuplicate("nickname, age")
+// OnDuplicate("nickname", "age")
+// OnDuplicate("nickname=VALUES(nickname), count=count+1")
+// OnDuplicate(g.Map{"nickname": "passport", "count": gdb.Raw("count+VALUES(count)")})
+//
+// For map parameter, the value can be a column name whose inserting value is used for updating,
+// or a Raw expression, or a Counter which updates the field by increment.
+func (m *Model) OnDuplicate(onDuplicate ...interface{}) *Model {
+	model := m.getModel()
+	if len(onDuplicate) > 1 {
+		model.onDuplicate = gconv.Strings(onDuplicate)
+	} else if len(onDuplicate) == 1 {
+		model.onDuplicate = onDuplicate[0]
+	}
+	return model
+}
+
+// OnDuplicateEx sets the excluded fields that are not updated when there's conflict for Save operation.
+// The parameter `onDuplicateEx` can be type of string/[]string/map, the keys of map are used as fields.
+// Eg:
+// OnDuplicateEx("passport, password")
+// OnDuplicateEx("passport", "password")
+func (m *Model) OnDuplicateEx(onDuplicateEx ...interface{}) *Model {
+	model := m.getModel()
+	if len(onDuplicateEx) > 1 {
+		model.onDuplicateEx = gconv.Strings(onDuplicateEx)
+	} else if len(onDuplicateEx) == 1 {
+		model.onDuplicateEx = onDuplicateEx[0]
+	}
+	return model
+}
+
+// getDoInsertOption creates and returns the DoInsertOption for given insert operation `option`.
+func (m *Model) getDoInsertOption(option int) (doInsertOption DoInsertOption, err error) {
+	doInsertOption = DoInsertOption{
+		InsertOption: option,
+		BatchCount:   defaultBatchNumber,
+	}
+	if m.batch > 0 {
+		doInsertOption.BatchCount = m.batch
+	}
+	if option != insertOptionSave {
+		return
+	}
+	doInsertOption.OnConflict = m.onConflict
+	if len(doInsertOption.OnConflict) == 0 {
+		// Only the drivers requiring conflict columns use the primary keys by default,
+		// which keeps the Save operation without upsert options as it is for other drivers.
+		if _, ok := m.db.(upsertConflictRequired); ok {
+			doInsertOption.OnConflict = m.getPrimaryKeys()
+		}
+	}
+	if m.onDuplicate != nil {
+		switch v := m.onDuplicate.(type) {
+		case Raw:
+			doInsertOption.OnDuplicateStr = string(v)
+		case string:
+			if gstr.Contains(v, "=") {
+				doInsertOption.OnDuplicateStr = v
+			} else {
+				doInsertOption.OnDuplicateMap = m.getOnDuplicateMapByFields(gstr.SplitAndTrim(v, ","))
+			}
+		case []string:
+			doInsertOption.OnDuplicateMap = m.getOnDuplicateMapByFields(v)
+		default:
+			if reflect.Indirect(reflect.ValueOf(v)).Kind() != reflect.Map {
+				return doInsertOption, gerror.Newf(`unsupported OnDuplicate parameter type "%s"`, reflect.TypeOf(v))
+			}
+			doInsertOption.OnDuplicateMap = gconv.Map(v)
+		}
+	}
+	if m.onDuplicateEx != nil {
+		switch v := m.onDuplicateEx.(type) {
+		case string:
+			doInsertOption.OnDuplicateEx = gstr.SplitAndTrim(v, ",")
+		case []string:
+			doInsertOption.OnDuplicateEx = v
+		default:
+			if reflect.Indirect(reflect.ValueOf(v)).Kind() != reflect.Map {
+				return doInsertOption, gerror.Newf(`unsupported OnDuplicateEx parameter type "%s"`, reflect.TypeOf(v))
+			}
+			doInsertOption.OnDuplicateEx = gutil.Keys(v)
+		}
+	}
+	return
+}
+
+// getOnDuplicateMapByFields creates and returns the OnDuplicateMap which updates given `fields`
+// with their own inserting values.
+func (m *Model) getOnDuplicateMapByFields(fields []string) map[string]interface{} {
+	onDuplicateMap := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		onDuplicateMap[field] = field
+	}
+	return onDuplicateMap
+}
+
 // doInsertWithOption inserts data with option parameter.
 func (m *Model) doInsertWithOption(option int) (result sql.Result, err error) {
+	if m.shardingRule != nil {
+		return m.doShardingInsert(option)
+	}
 	defer func() {
 		if err == nil {
 			m.checkAndRemoveCache()
@@ -153,16 +269,24 @@ func (m *Model) doInsertWithOption(option int) (result sql.Result, err error) {
 		return nil, gerror.New("inserting into table with empty data")
 	}
 	var (
-		nowString       = gtime.Now().String()
-		fieldNameCreate = m.getSoftFieldNameCreated()
-		fieldNameUpdate = m.getSoftFieldNameUpdated()
-		fieldNameDelete = m.getSoftFieldNameDeleted()
+		tableName        = m.getPrimaryTableName()
+		fieldNameCreate  = m.getSoftFieldNameCreated()
+		fieldNameUpdate  = m.getSoftFieldNameUpdated()
+		fieldNameDelete  = m.getSoftFieldNameDeleted()
+		fieldValueCreate interface{}
+		fieldValueUpdate interface{}
 	)
+	if fieldNameCreate != "" {
+		fieldValueCreate = m.getSoftFieldValue(tableName, fieldNameCreate, false)
+	}
+	if fieldNameUpdate != "" {
+		fieldValueUpdate = m.getSoftFieldValue(tableName, fieldNameUpdate, false)
+	}
 	// Batch operation.
 	if list, ok := m.data.(List); ok {
-		batch := defaultBatchNumber
-		if m.batch > 0 {
-			batch = m.batch
+		doInsertOption, err := m.getDoInsertOption(option)
+		if err != nil {
+			return nil, err
 		}
 		newData, err := m.filterDataForInsertOrUpdate(list)
 		if err != nil {
@@ -174,24 +298,37 @@ func (m *Model) doInsertWithOption(option int) (result sql.Result, err error) {
 			for k, v := range list {
 				gutil.MapDelete(v, fieldNameCreate, fieldNameUpdate, fieldNameDelete)
 				if fieldNameCreate != "" {
-					v[fieldNameCreate] = nowString
+					v[fieldNameCreate] = fieldValueCreate
 				}
 				if fieldNameUpdate != "" {
-					v[fieldNameUpdate] = nowString
+					v[fieldNameUpdate] = fieldValueUpdate
 				}
 				list[k] = v
 			}
 		}
-		return m.db.DoBatchInsert(
-			m.getLink(true),
-			m.tables,
-			newData,
-			option,
-			batch,
-		)
+		in := &HookInsertInput{
+			internalParamHookInsert: internalParamHookInsert{
+				internalParamHook: internalParamHook{
+					link: m.getLink(true),
+				},
+				handler: m.hookHandler.Insert,
+				batch:   true,
+			},
+			Model:        m,
+			Table:        m.tables,
+			Data:         list,
+			Option:       option,
+			Batch:        doInsertOption.BatchCount,
+			UpsertOption: doInsertOption,
+		}
+		return in.Next(m.db.GetCtx())
 	}
 	// Single operation.
 	if data, ok := m.data.(Map); ok {
+		doInsertOption, err := m.getDoInsertOption(option)
+		if err != nil {
+			return nil, err
+		}
 		newData, err := m.filterDataForInsertOrUpdate(data)
 		if err != nil {
 			return nil, err
@@ -201,18 +338,27 @@ func (m *Model) doInsertWithOption(option int) (result sql.Result, err error) {
 		if !m.unscoped && (fieldNameCreate != "" || fieldNameUpdate != "") {
 			gutil.MapDelete(data, fieldNameCreate, fieldNameUpdate, fieldNameDelete)
 			if fieldNameCreate != "" {
-				data[fieldNameCreate] = nowString
+				data[fieldNameCreate] = fieldValueCreate
 			}
 			if fieldNameUpdate != "" {
-				data[fieldNameUpdate] = nowString
+				data[fieldNameUpdate] = fieldValueUpdate
 			}
 		}
-		return m.db.DoInsert(
-			m.getLink(true),
-			m.tables,
-			newData,
-			option,
-		)
+		in := &HookInsertInput{
+			internalParamHookInsert: internalParamHookInsert{
+				internalParamHook: internalParamHook{
+					link: m.getLink(true),
+				},
+				handler: m.hookHandler.Insert,
+			},
+			Model:        m,
+			Table:        m.tables,
+			Data:         List{data},
+			Option:       option,
+			Batch:        doInsertOption.BatchCount,
+			UpsertOption: doInsertOption,
+		}
+		return in.Next(m.db.GetCtx())
 	}
 	return nil, gerror.New("inserting into table with invalid data type")
 }
@@ -0,0 +1,232 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gogf/gf/container/gvar"
+	"github.com/gogf/gf/errors/gerror"
+)
+
+// Iterator is the streaming iterator for query result of Model, which keeps the underlying sql.Rows
+// open and decodes the records lazily one by one, so that large result would not be loaded into memory.
+//
+// Usage:
+// iterator, err := db.Model("user").Where("status", 1).Iterator()
+// if err != nil { return err }
+// defer iterator.Close()
+// for iterator.Next() { record := iterator.Record() }
+// return iterator.Err()
+//
+// Note that the iterator does not use the cache feature and select hook of the model,
+// and the underlying connection is occupied until the iterator is closed.
+type Iterator struct {
+	model       *Model          // The model for querying.
+	ctx         context.Context // The context for cancellation checks during iterating.
+	chunkSize   int             // Chunk size for keyset pagination, which is not enabled if it's not greater than 0.
+	primaryKey  string          // Primary key name for keyset pagination.
+	lastKey     interface{}     // Primary key value of the last iterated record for keyset pagination.
+	chunkCount  int             // Iterated record count of current chunk.
+	rows        *sql.Rows       // Currently opened rows.
+	columnNames []string        // Column names of current rows.
+	columnTypes []string        // Column types of current rows.
+	values      []interface{}   // Scanned values of current record.
+	scanArgs    []interface{}   // Scan arguments pointing to values.
+	record      Record          // Current record.
+	err         error           // Error that occurs during iterating.
+	done        bool            // Whether the iterating is done.
+}
+
+// Iterator creates and returns a streaming iterator for the query of current model.
+//
+// If the optional parameter `chunkSize` is not given, all the records are retrieved using one
+// sql.Rows of single query. If `chunkSize` is given, it uses keyset pagination on the primary key,
+// which queries the records in chunks with condition "primary key > last key" ordered by the primary key,
+// so that no long-running statement would be kept open. Note that it does not support custom
+// order or limit of the model for keyset pagination, and the primary key should be in the selected fields.
+//
+// The context of the model is checked before each record is retrieved,
+// and the iterating stops with the context error if it's canceled.
+func (m *Model) Iterator(chunkSize ...int) (*Iterator, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.shardingRule != nil {
+		return nil, gerror.New(`iterator is not supported for sharding table`)
+	}
+	iterator := &Iterator{
+		model: m,
+		ctx:   m.db.GetCtx(),
+	}
+	if len(chunkSize) > 0 && chunkSize[0] > 0 {
+		if m.orderBy != "" || m.limit > 0 || m.start > 0 {
+			return nil, gerror.New(`custom order or limit is not supported for keyset pagination`)
+		}
+		iterator.chunkSize = chunkSize[0]
+		iterator.primaryKey = m.getPrimaryKey()
+		if iterator.primaryKey == "" {
+			return nil, gerror.Newf(`no primary key found for keyset pagination of table "%s"`, m.tables)
+		}
+	}
+	if err := iterator.openRows(); err != nil {
+		return nil, err
+	}
+	return iterator, nil
+}
+
+// Each iterates the records of current model query one by one using Iterator,
+// and calls `callback` for each record. It stops iterating if `callback` returns an error,
+// and returns the error. The optional parameter `chunkSize` enables keyset pagination,
+// see Model.Iterator.
+func (m *Model) Each(callback func(record Record) error, chunkSize ...int) error {
+	iterator, err := m.Iterator(chunkSize...)
+	if err != nil {
+		return err
+	}
+	defer iterator.Close()
+	for iterator.Next() {
+		if err = callback(iterator.Record()); err != nil {
+			return err
+		}
+	}
+	return iterator.Err()
+}
+
+// Next retrieves the next record, which can be fetched by Record.
+// It returns false if there are no more records or any error occurs, which can be checked by Err.
+// The iterator is closed automatically if it returns false.
+func (it *Iterator) Next() bool {
+	if it.done {
+		return false
+	}
+	for {
+		if err := it.ctx.Err(); err != nil {
+			return it.stop(err)
+		}
+		if it.rows.Next() {
+			if err := it.scanRecord(); err != nil {
+				return it.stop(err)
+			}
+			return true
+		}
+		if err := it.rows.Err(); err != nil {
+			return it.stop(err)
+		}
+		if err := it.rows.Close(); err != nil {
+			return it.stop(err)
+		}
+		it.rows = nil
+		// Keyset pagination, retrieves the next chunk only if current chunk is full.
+		if it.chunkSize <= 0 || it.chunkCount < it.chunkSize {
+			return it.stop(nil)
+		}
+		if err := it.openRows(); err != nil {
+			return it.stop(err)
+		}
+	}
+}
+
+// Record returns the current record retrieved by Next.
+func (it *Iterator) Record() Record {
+	return it.record
+}
+
+// Err returns the error that occurs during iterating, which is nil if the iterating completes.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close closes the iterator and releases its underlying sql.Rows.
+// It is safe calling Close multiple times.
+func (it *Iterator) Close() error {
+	it.done = true
+	if it.rows != nil {
+		rows := it.rows
+		it.rows = nil
+		return rows.Close()
+	}
+	return nil
+}
+
+// stop closes the iterator with error `err` and returns false.
+func (it *Iterator) stop(err error) bool {
+	if closeErr := it.Close(); err == nil {
+		err = closeErr
+	}
+	it.err = err
+	it.record = nil
+	return false
+}
+
+// openRows queries and opens the rows of the model, or the rows of next chunk for keyset pagination.
+func (it *Iterator) openRows() error {
+	model := it.model
+	if it.chunkSize > 0 {
+		// It clones the model in case that the conditions of chunks are appended to the same model.
+		model = model.Clone()
+		if it.lastKey != nil {
+			model = model.Where(fmt.Sprintf(`%s>?`, model.db.QuoteWord(it.primaryKey)), it.lastKey)
+		}
+		model = model.Order(it.primaryKey + " ASC").Limit(it.chunkSize)
+		it.chunkCount = 0
+	}
+	sqlWithHolder, holderArgs := model.getFormattedSqlAndArgs(queryTypeNormal, false)
+	rows, err := model.db.DoQuery(model.getLink(false), sqlWithHolder, model.mergeArguments(holderArgs)...)
+	if err != nil {
+		return err
+	}
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		return err
+	}
+	it.rows = rows
+	it.columnNames = make([]string, len(columns))
+	it.columnTypes = make([]string, len(columns))
+	for k, v := range columns {
+		it.columnNames[k] = v.Name()
+		it.columnTypes[k] = v.DatabaseTypeName()
+	}
+	it.values = make([]interface{}, len(columns))
+	it.scanArgs = make([]interface{}, len(columns))
+	for i := range it.values {
+		it.scanArgs[i] = &it.values[i]
+	}
+	return nil
+}
+
+// scanRecord scans and decodes current row of rows to the record.
+func (it *Iterator) scanRecord() error {
+	if err := it.rows.Scan(it.scanArgs...); err != nil {
+		return err
+	}
+	record := make(Record, len(it.columnNames))
+	for i, value := range it.values {
+		if value == nil {
+			record[it.columnNames[i]] = gvar.New(nil)
+		} else {
+			localValue, err := it.model.db.convertFieldValueToLocalValue(value, it.columnTypes[i])
+			if err != nil {
+				return err
+			}
+			record[it.columnNames[i]] = gvar.New(localValue)
+		}
+	}
+	if it.chunkSize > 0 {
+		value, ok := record[it.primaryKey]
+		if !ok || value.IsNil() {
+			return gerror.Newf(`primary key "%s" is not found in the record for keyset pagination`, it.primaryKey)
+		}
+		it.lastKey = value.Val()
+		it.chunkCount++
+	}
+	it.record = record
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"github.com/gogf/gf/container/gset"
 	"github.com/gogf/gf/container/gvar"
+	"github.com/gogf/gf/errors/gerror"
 	"github.com/gogf/gf/internal/intlog"
 	"github.com/gogf/gf/internal/json"
 	"github.com/gogf/gf/text/gstr"
@@ -45,18 +46,62 @@ func (m *Model) doGetAll(limit1 bool, where ...interface{}) (Result, error) {
 	if len(where) > 0 {
 		return m.Where(where[0], where[1:]...).All()
 	}
-	conditionWhere, conditionExtra, conditionArgs := m.formatCondition(limit1, false)
-	// DO NOT quote the m.fields where, in case of fields like:
-	// DISTINCT t.user_id uid
-	return m.doGetAllBySql(
-		fmt.Sprintf(
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.shardingRule != nil {
+		return m.doShardingGetAll(limit1)
+	}
+	sqlWithHolder, holderArgs := m.getFormattedSqlAndArgs(queryTypeNormal, limit1)
+	return m.doGetAllBySql(sqlWithHolder, holderArgs...)
+}
+
+// getFormattedSqlAndArgs returns the formatted SELECT statement and its arguments of current model,
+// according to given `queryType`. The parameter `limit1` specifies whether limits querying only one
+// record if m.limit is not set.
+//
+// Note that the returned arguments do not contain the extra arguments set by Model.Args,
+// which are merged in function doGetAllBySql.
+func (m *Model) getFormattedSqlAndArgs(queryType int, limit1 bool) (sqlWithHolder string, holderArgs []interface{}) {
+	switch queryType {
+	case queryTypeCount:
+		countFields := "COUNT(1)"
+		if m.fields != "" && m.fields != "*" && len(m.fieldsArgs) == 0 {
+			// DO NOT quote the m.fields here, in case of fields like:
+			// DISTINCT t.user_id uid
+			countFields = fmt.Sprintf(`COUNT(%s)`, m.fields)
+		}
+		conditionWhere, conditionExtra, conditionArgs := m.formatCondition(false, true)
+		sqlWithHolder = fmt.Sprintf("SELECT %s FROM %s%s", countFields, m.tables, conditionWhere+conditionExtra)
+		if len(m.groupBy) > 0 {
+			sqlWithHolder = fmt.Sprintf("SELECT COUNT(1) FROM (%s) count_alias", sqlWithHolder)
+		}
+		holderArgs = append(holderArgs, m.tablesArgs...)
+		holderArgs = append(holderArgs, conditionArgs...)
+
+	default:
+		conditionWhere, conditionExtra, conditionArgs := m.formatCondition(limit1, false)
+		// DO NOT quote the m.fields where, in case of fields like:
+		// DISTINCT t.user_id uid
+		sqlWithHolder = fmt.Sprintf(
 			"SELECT %s FROM %s%s",
 			m.getFieldsFiltered(),
 			m.tables,
 			conditionWhere+conditionExtra,
-		),
-		conditionArgs...,
-	)
+		)
+		// The arguments are ordered as their holders in the statement: fields, tables and conditions.
+		holderArgs = append(holderArgs, m.fieldsArgs...)
+		holderArgs = append(holderArgs, m.tablesArgs...)
+		holderArgs = append(holderArgs, conditionArgs...)
+	}
+	return
+}
+
+// getFormattedSubQuery returns the SELECT statement and all its arguments of current model,
+// which is used as a sub-query of another statement.
+func (m *Model) getFormattedSubQuery() (sqlWithHolder string, holderArgs []interface{}) {
+	sqlWithHolder, holderArgs = m.getFormattedSqlAndArgs(queryTypeNormal, false)
+	return sqlWithHolder, m.mergeArguments(holderArgs)
 }
 
 // getFieldsFiltered checks the fields and fieldsEx attributes, filters and returns the fields that will
@@ -330,18 +375,14 @@ func (m *Model) Count(where ...interface{}) (int, error) {
 	if len(where) > 0 {
 		return m.Where(where[0], where[1:]...).Count()
 	}
-	countFields := "COUNT(1)"
-	if m.fields != "" && m.fields != "*" {
-		// DO NOT quote the m.fields here, in case of fields like:
-		// DISTINCT t.user_id uid
-		countFields = fmt.Sprintf(`COUNT(%s)`, m.fields)
+	if m.err != nil {
+		return 0, m.err
 	}
-	conditionWhere, conditionExtra, conditionArgs := m.formatCondition(false, true)
-	s := fmt.Sprintf("SELECT %s FROM %s%s", countFields, m.tables, conditionWhere+conditionExtra)
-	if len(m.groupBy) > 0 {
-		s = fmt.Sprintf("SELECT COUNT(1) FROM (%s) count_alias", s)
+	if m.shardingRule != nil {
+		return m.doShardingCount()
 	}
-	list, err := m.doGetAllBySql(s, conditionArgs...)
+	sqlWithHolder, holderArgs := m.getFormattedSqlAndArgs(queryTypeCount, false)
+	list, err := m.doGetAllBySql(sqlWithHolder, holderArgs...)
 	if err != nil {
 		return 0, err
 	}
@@ -353,6 +394,53 @@ func (m *Model) Count(where ...interface{}) (int, error) {
 	return 0, nil
 }
 
+// Min does "SELECT MIN(x) FROM ..." statement for the model.
+// The optional parameter `where` is the same as the parameter of Model.Where function,
+// see Model.Where.
+func (m *Model) Min(column string, where ...interface{}) (float64, error) {
+	return m.doGetAggregateValue("MIN", column, where...)
+}
+
+// Max does "SELECT MAX(x) FROM ..." statement for the model.
+// The optional parameter `where` is the same as the parameter of Model.Where function,
+// see Model.Where.
+func (m *Model) Max(column string, where ...interface{}) (float64, error) {
+	return m.doGetAggregateValue("MAX", column, where...)
+}
+
+// Avg does "SELECT AVG(x) FROM ..." statement for the model.
+// The optional parameter `where` is the same as the parameter of Model.Where function,
+// see Model.Where.
+func (m *Model) Avg(column string, where ...interface{}) (float64, error) {
+	return m.doGetAggregateValue("AVG", column, where...)
+}
+
+// Sum does "SELECT SUM(x) FROM ..." statement for the model.
+// The optional parameter `where` is the same as the parameter of Model.Where function,
+// see Model.Where.
+func (m *Model) Sum(column string, where ...interface{}) (float64, error) {
+	return m.doGetAggregateValue("SUM", column, where...)
+}
+
+// doGetAggregateValue retrieves and returns the result of aggregate function `function`
+// on `column` for the model. It returns 0 if there's no record matching the conditions.
+func (m *Model) doGetAggregateValue(function string, column string, where ...interface{}) (float64, error) {
+	if len(where) > 0 {
+		return m.Where(where[0], where[1:]...).doGetAggregateValue(function, column)
+	}
+	if len(column) == 0 {
+		return 0, gerror.Newf(`column name cannot be empty for aggregate function %s`, function)
+	}
+	if m.shardingRule != nil {
+		return m.doShardingAggregateValue(function, column)
+	}
+	value, err := m.Fields(fmt.Sprintf(`%s(%s)`, function, m.db.QuoteWord(column))).Value()
+	if err != nil {
+		return 0, err
+	}
+	return value.Float64(), nil
+}
+
 // FindOne retrieves and returns a single Record by Model.WherePri and Model.One.
 // Also see Model.WherePri and Model.One.
 func (m *Model) FindOne(where ...interface{}) (Record, error) {
@@ -416,8 +504,11 @@ func (m *Model) FindScan(pointer interface{}, where ...interface{}) error {
 
 // doGetAllBySql does the select statement on the database.
 func (m *Model) doGetAllBySql(sql string, args ...interface{}) (result Result, err error) {
-	cacheKey := ""
-	cacheObj := m.db.GetCache().Ctx(m.db.GetCtx())
+	var (
+		cacheKey  = ""
+		cacheTags map[string]string
+		cacheObj  = m.db.GetCache().Ctx(m.db.GetCtx())
+	)
 	// Retrieve from cache.
 	if m.cacheEnabled && m.tx == nil {
 		cacheKey = m.cacheName
@@ -425,21 +516,36 @@ func (m *Model) doGetAllBySql(sql string, args ...interface{}) (result Result, e
 			cacheKey = sql + ", @PARAMS:" + gconv.String(args)
 		}
 		if v, _ := cacheObj.GetVar(cacheKey); !v.IsNil() {
-			if result, ok := v.Val().(Result); ok {
-				// In-memory cache.
-				return result, nil
-			} else {
+			item, ok := v.Val().(*selectCacheItem)
+			if !ok {
 				// Other cache, it needs conversion.
-				var result Result
-				if err = json.Unmarshal(v.Bytes(), &result); err != nil {
-					return nil, err
-				} else {
-					return result, nil
+				if err = json.Unmarshal(v.Bytes(), &item); err != nil {
+					intlog.Error(err)
 				}
 			}
+			if item != nil && m.isCacheTagsValid(item.Tags) {
+				return item.Result, nil
+			}
+		}
+		// The versions of tags should be retrieved before selecting,
+		// in case of any writing during the selecting.
+		if m.cacheDuration >= 0 {
+			cacheTags = m.getCacheTags(sql)
 		}
 	}
-	result, err = m.db.DoGetAll(m.getLink(false), sql, m.mergeArguments(args)...)
+	in := &HookSelectInput{
+		internalParamHookSelect: internalParamHookSelect{
+			internalParamHook: internalParamHook{
+				link: m.getLink(false),
+			},
+			handler: m.hookHandler.Select,
+		},
+		Model: m,
+		Table: m.tables,
+		Sql:   sql,
+		Args:  m.mergeArguments(args),
+	}
+	result, err = in.Next(m.db.GetCtx())
 	// Cache the result.
 	if cacheKey != "" && err == nil {
 		if m.cacheDuration < 0 {
@@ -447,7 +553,11 @@ func (m *Model) doGetAllBySql(sql string, args ...interface{}) (result Result, e
 				intlog.Error(err)
 			}
 		} else {
-			if err := cacheObj.Set(cacheKey, result, m.cacheDuration); err != nil {
+			item := &selectCacheItem{
+				Result: result,
+				Tags:   cacheTags,
+			}
+			if err := cacheObj.Set(cacheKey, item, m.cacheDuration); err != nil {
 				intlog.Error(err)
 			}
 		}
@@ -0,0 +1,690 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gconv"
+	"github.com/gogf/gf/util/gutil"
+)
+
+// ShardingRule is the interface for table sharding of Model, which routes the logical table
+// of the model to physical tables and optional configuration groups by the value of sharding key.
+type ShardingRule interface {
+	// ShardingKey returns the field name of the sharding key.
+	ShardingKey() string
+
+	// Shard returns the shard that stores the record having sharding key `value`.
+	Shard(value interface{}) (*Shard, error)
+
+	// Shards returns the shards that store the records having sharding key in range [`min`, `max`].
+	// The parameter `min` or `max` is nil if the range has no lower or upper bound.
+	Shards(min, max interface{}) ([]*Shard, error)
+}
+
+// Shard is the physical storage of the records of sharding table.
+type Shard struct {
+	Group string // (Optional) Configuration group name, which is the group of the model if it's empty.
+	Table string // Physical table name.
+}
+
+// ShardingModulo is the sharding rule that splits the table by modulo of the integer sharding key.
+// The physical table name is the prefix and the modulo with at least two digits, like: user_log_07.
+type ShardingModulo struct {
+	Key    string   // Field name of the sharding key.
+	Count  int      // Count of the shards.
+	Prefix string   // Prefix of the physical table name, like: user_log_.
+	Groups []string // (Optional) Configuration groups, the shard of modulo i is in group Groups[i%len(Groups)].
+}
+
+// ShardingMonth is the sharding rule that splits the table by month of the datetime sharding key.
+// The physical table name is the prefix and the month in format "200601", like: order_202601.
+type ShardingMonth struct {
+	Key    string    // Field name of the sharding key.
+	Prefix string    // Prefix of the physical table name, like: order_.
+	Start  time.Time // The month of the first shard, which is the lower bound of the queries that have no lower bound.
+}
+
+// shardingCondition is the sharding key condition parsed from where conditions of the model.
+type shardingCondition struct {
+	values []interface{} // Values of equal or IN conditions.
+	min    interface{}   // Lower bound of range conditions.
+	max    interface{}   // Upper bound of range conditions.
+}
+
+const (
+	// shardingWhereRegPattern matches the simple condition on single field, like:
+	// "user_id", "user_id=?", "user_id>=?", "user_id IN(?)", "user_id BETWEEN ? AND ?".
+	shardingWhereRegPattern = `(?i)^\s*(?:[\w\x60"\[\]]+\.)?[\x60"\[]?(\w+)[\x60"\]]?\s*(>=|<=|=|>|<|\bIN\b|\bBETWEEN\b)?\s*(.*?)\s*$`
+
+	// shardingAggregateRegPattern matches the fields containing aggregate functions, like: "SUM(amount)".
+	shardingAggregateRegPattern = `(?i)\b(COUNT|SUM|MIN|MAX|AVG|GROUP_CONCAT)\s*\(`
+)
+
+// Sharding sets the sharding rule for the model, which makes the model operate on the physical
+// tables and configuration groups routed by the sharding key, instead of the logical table of the model.
+//
+// The shard of Insert/Replace/Save operations is routed by the sharding key value of the data,
+// and the batch data is split and written to their shards respectively.
+// The shards of Select/Update/Delete operations are routed by the simple conditions of the
+// sharding key, like: Where("user_id", 7), Where("user_id IN(?)", ids), Where("created_at>=?", t).
+// The operations are performed on all the shards of the rule if there's no sharding key condition,
+// or there's any Or condition.
+//
+// The select operations spanning multiple shards fan out and merge the results, with ordering
+// and limit applied on the merged result, and the aggregate functions count/sum/min/max/avg
+// are merged too. Note that the GROUP BY statement is performed in each shard, and the
+// operations spanning multiple groups are not in one transaction. The aggregate fields without
+// GROUP BY, like Fields("SUM(amount)").Value(), return error if they span multiple shards,
+// as they cannot be merged, use Count/Sum/Min/Max/Avg of Model instead.
+//
+// Eg:
+// db.Model("user_log").Sharding(&gdb.ShardingModulo{Key: "user_id", Count: 8, Prefix: "user_log_"})
+// db.Model("order").Sharding(&gdb.ShardingMonth{Key: "created_at", Prefix: "order_", Start: start})
+func (m *Model) Sharding(rule ShardingRule) *Model {
+	model := m.getModel()
+	model.shardingRule = rule
+	return model
+}
+
+// ShardingKey implements interface ShardingRule.
+func (r *ShardingModulo) ShardingKey() string {
+	return r.Key
+}
+
+// Shard implements interface ShardingRule.
+func (r *ShardingModulo) Shard(value interface{}) (*Shard, error) {
+	if r.Count <= 0 {
+		return nil, gerror.New(`shard count should be greater than 0`)
+	}
+	if !gstr.IsNumeric(gconv.String(value)) {
+		return nil, gerror.Newf(`invalid sharding key value "%v" for modulo sharding`, value)
+	}
+	modulo := int(gconv.Int64(value) % int64(r.Count))
+	if modulo < 0 {
+		modulo = -modulo
+	}
+	return r.getShard(modulo), nil
+}
+
+// Shards implements interface ShardingRule.
+// The values of the range are mapped to shards by absolute value of the modulo, so the negative
+// and positive values can be in the same shard, like -1 and 1, which returns the shard only once.
+func (r *ShardingModulo) Shards(min, max interface{}) ([]*Shard, error) {
+	if r.Count <= 0 {
+		return nil, gerror.New(`shard count should be greater than 0`)
+	}
+	shards := make([]*Shard, 0, r.Count)
+	if min != nil && max != nil {
+		minValue, maxValue := gconv.Int64(min), gconv.Int64(max)
+		if maxValue-minValue+1 < int64(r.Count) {
+			tables := make(map[string]struct{})
+			for v := minValue; v <= maxValue; v++ {
+				shard, err := r.Shard(v)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := tables[shard.Table]; !ok {
+					tables[shard.Table] = struct{}{}
+					shards = append(shards, shard)
+				}
+			}
+			return shards, nil
+		}
+	}
+	for i := 0; i < r.Count; i++ {
+		shards = append(shards, r.getShard(i))
+	}
+	return shards, nil
+}
+
+// getShard returns the shard of modulo `modulo`.
+func (r *ShardingModulo) getShard(modulo int) *Shard {
+	width := len(strconv.Itoa(r.Count - 1))
+	if width < 2 {
+		width = 2
+	}
+	shard := &Shard{
+		Table: fmt.Sprintf(`%s%0*d`, r.Prefix, width, modulo),
+	}
+	if len(r.Groups) > 0 {
+		shard.Group = r.Groups[modulo%len(r.Groups)]
+	}
+	return shard
+}
+
+// ShardingKey implements interface ShardingRule.
+func (r *ShardingMonth) ShardingKey() string {
+	return r.Key
+}
+
+// Shard implements interface ShardingRule.
+func (r *ShardingMonth) Shard(value interface{}) (*Shard, error) {
+	t, err := getShardingTime(value)
+	if err != nil {
+		return nil, err
+	}
+	return &Shard{Table: r.Prefix + t.Format("200601")}, nil
+}
+
+// Shards implements interface ShardingRule.
+func (r *ShardingMonth) Shards(min, max interface{}) ([]*Shard, error) {
+	var (
+		from = r.Start
+		to   = time.Now()
+	)
+	if min != nil {
+		t, err := getShardingTime(min)
+		if err != nil {
+			return nil, err
+		}
+		if from.IsZero() || t.After(from) {
+			from = t
+		}
+	}
+	if from.IsZero() {
+		return nil, gerror.New(`start month of sharding rule is required for queries without lower bound`)
+	}
+	if max != nil {
+		t, err := getShardingTime(max)
+		if err != nil {
+			return nil, err
+		}
+		to = t
+	}
+	var (
+		shards = make([]*Shard, 0)
+		month  = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	)
+	for !month.After(to) {
+		shards = append(shards, &Shard{Table: r.Prefix + month.Format("200601")})
+		month = month.AddDate(0, 1, 0)
+	}
+	return shards, nil
+}
+
+// getShardingTime converts the sharding key `value` to time.Time,
+// which can be time.Time/*gtime.Time, datetime string or unix timestamp.
+func getShardingTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		return *v, nil
+	case gtime.Time:
+		return v.Time, nil
+	case *gtime.Time:
+		return v.Time, nil
+	}
+	s := gconv.String(value)
+	if gstr.IsNumeric(s) {
+		return gtime.NewFromTimeStamp(gconv.Int64(s)).Time, nil
+	}
+	t, err := gtime.StrToTime(s)
+	if err != nil {
+		return time.Time{}, gerror.Newf(`invalid sharding key value "%v" for month sharding: %v`, value, err)
+	}
+	return t.Time, nil
+}
+
+// getShards returns the shards of current model according to the sharding key conditions.
+func (m *Model) getShards() ([]*Shard, error) {
+	var (
+		err       error
+		shards    []*Shard
+		condition = m.getShardingCondition()
+	)
+	if len(condition.values) > 0 {
+		shards = make([]*Shard, 0, len(condition.values))
+		for _, value := range condition.values {
+			shard, err := m.shardingRule.Shard(value)
+			if err != nil {
+				return nil, err
+			}
+			shards = append(shards, shard)
+		}
+	} else if shards, err = m.shardingRule.Shards(condition.min, condition.max); err != nil {
+		return nil, err
+	}
+	// Removes the duplicated shards.
+	var (
+		uniqueShards = make([]*Shard, 0, len(shards))
+		uniqueKeys   = make(map[string]struct{}, len(shards))
+	)
+	for _, shard := range shards {
+		key := shard.Group + "@" + shard.Table
+		if _, ok := uniqueKeys[key]; !ok {
+			uniqueKeys[key] = struct{}{}
+			uniqueShards = append(uniqueShards, shard)
+		}
+	}
+	return uniqueShards, nil
+}
+
+// getShardingCondition parses and returns the sharding key condition from where conditions of the model.
+// The complex conditions that cannot be parsed are ignored, as they only narrow the records.
+// It returns an empty condition if there's any Or condition.
+func (m *Model) getShardingCondition() *shardingCondition {
+	condition := &shardingCondition{}
+	for _, holder := range m.whereHolder {
+		if holder.operator == whereHolderOr {
+			return &shardingCondition{}
+		}
+		switch reflect.Indirect(reflect.ValueOf(holder.where)).Kind() {
+		case reflect.Map, reflect.Struct:
+			for k, v := range DataToMapDeep(holder.where) {
+				m.parseShardingCondition(condition, k, []interface{}{v})
+			}
+		default:
+			m.parseShardingCondition(condition, gconv.String(holder.where), holder.args)
+		}
+	}
+	return condition
+}
+
+// parseShardingCondition parses the sharding key condition from the simple condition string `where`
+// and its arguments `args`, and merges it into `condition`.
+func (m *Model) parseShardingCondition(condition *shardingCondition, where string, args []interface{}) {
+	match, _ := gregex.MatchString(shardingWhereRegPattern, where)
+	if len(match) == 0 || !strings.EqualFold(match[1], m.shardingRule.ShardingKey()) {
+		return
+	}
+	var (
+		operator = strings.ToUpper(match[2])
+		holder   = match[3]
+	)
+	// The condition value is given in the condition string, like: user_id=7.
+	if len(args) == 0 {
+		if operator == "=" && holder != "" && !gstr.Contains(holder, "?") {
+			condition.values = append(condition.values, gstr.Trim(holder, `'"`))
+		}
+		return
+	}
+	switch operator {
+	case "", "=":
+		if holder != "" && holder != "?" {
+			return
+		}
+		condition.values = append(condition.values, getShardingValues(args[0])...)
+	case "IN":
+		if gstr.Count(holder, "?") == 1 {
+			condition.values = append(condition.values, getShardingValues(args[0])...)
+		} else {
+			condition.values = append(condition.values, args...)
+		}
+	case ">", ">=":
+		if condition.min == nil || compareShardingValue(args[0], condition.min) > 0 {
+			condition.min = args[0]
+		}
+	case "<", "<=":
+		if condition.max == nil || compareShardingValue(args[0], condition.max) < 0 {
+			condition.max = args[0]
+		}
+	case "BETWEEN":
+		if len(args) < 2 {
+			return
+		}
+		if condition.min == nil || compareShardingValue(args[0], condition.min) > 0 {
+			condition.min = args[0]
+		}
+		if condition.max == nil || compareShardingValue(args[1], condition.max) < 0 {
+			condition.max = args[1]
+		}
+	}
+}
+
+// getShardingValues returns the values of condition argument `arg`, which can be a slice for IN condition.
+func getShardingValues(arg interface{}) []interface{} {
+	if _, ok := arg.([]byte); !ok {
+		switch reflect.Indirect(reflect.ValueOf(arg)).Kind() {
+		case reflect.Slice, reflect.Array:
+			return gconv.Interfaces(arg)
+		}
+	}
+	return []interface{}{arg}
+}
+
+// compareShardingValue compares `a` and `b`, which returns -1 if a < b, 0 if a == b and 1 if a > b.
+// It compares them as numbers if both of them are numeric, or else as strings.
+func compareShardingValue(a, b interface{}) int {
+	var (
+		aString = gconv.String(a)
+		bString = gconv.String(b)
+	)
+	if gstr.IsNumeric(aString) && gstr.IsNumeric(bString) {
+		aFloat, bFloat := gconv.Float64(aString), gconv.Float64(bString)
+		switch {
+		case aFloat < bFloat:
+			return -1
+		case aFloat > bFloat:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(aString, bString)
+}
+
+// getShardModel returns a new model operating on the physical table and group of `shard`.
+// The custom cache name is suffixed with the shard, as the shards cache their results respectively.
+func (m *Model) getShardModel(shard *Shard) (*Model, error) {
+	model := m.Clone()
+	model.shardingRule = nil
+	if model.cacheName != "" {
+		model.cacheName += "@" + shard.Group + "@" + shard.Table
+	}
+	if shard.Group != "" && shard.Group != m.db.GetGroup() {
+		if m.tx != nil {
+			return nil, gerror.Newf(`cannot operate on shard of group "%s" in transaction`, shard.Group)
+		}
+		db, err := Instance(shard.Group)
+		if err != nil {
+			return nil, err
+		}
+		model.db = db.Ctx(m.db.GetCtx())
+	}
+	var (
+		logicalTable  = gstr.SplitAndTrim(gstr.SplitAndTrim(m.tables, ",")[0], " ")[0]
+		physicalTable = model.db.QuotePrefixTableName(shard.Table)
+	)
+	model.tables = strings.Replace(m.tables, logicalTable, physicalTable, 1)
+	model.tablesInit = strings.Replace(m.tablesInit, logicalTable, physicalTable, 1)
+	return model, nil
+}
+
+// getShardModels returns the models operating on the shards of current model.
+func (m *Model) getShardModels() ([]*Model, error) {
+	shards, err := m.getShards()
+	if err != nil {
+		return nil, err
+	}
+	models := make([]*Model, len(shards))
+	for i, shard := range shards {
+		if models[i], err = m.getShardModel(shard); err != nil {
+			return nil, err
+		}
+	}
+	return models, nil
+}
+
+// doShardingInsert splits the data by sharding key and inserts them into their shards.
+func (m *Model) doShardingInsert(option int) (result sql.Result, err error) {
+	var list List
+	switch value := m.data.(type) {
+	case Map:
+		list = List{value}
+	case List:
+		list = value
+	default:
+		return nil, gerror.New(`the data of sharding table should be type of map/struct or their slice`)
+	}
+	var (
+		shardingKey = m.shardingRule.ShardingKey()
+		shardKeys   = make([]string, 0)
+		shardMap    = make(map[string]*Shard)
+		shardData   = make(map[string]List)
+	)
+	for _, item := range list {
+		key, value := gutil.MapPossibleItemByKey(item, shardingKey)
+		if key == "" {
+			return nil, gerror.Newf(`sharding key "%s" is not found in the data`, shardingKey)
+		}
+		shard, err := m.shardingRule.Shard(value)
+		if err != nil {
+			return nil, err
+		}
+		shardKey := shard.Group + "@" + shard.Table
+		if _, ok := shardMap[shardKey]; !ok {
+			shardKeys = append(shardKeys, shardKey)
+			shardMap[shardKey] = shard
+		}
+		shardData[shardKey] = append(shardData[shardKey], item)
+	}
+	return m.doShardingExec(shardKeys, func(i int, shardKey string) (sql.Result, error) {
+		model, err := m.getShardModel(shardMap[shardKey])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := m.data.(Map); ok {
+			model.data = shardData[shardKey][0]
+		} else {
+			model.data = shardData[shardKey]
+		}
+		return model.doInsertWithOption(option)
+	})
+}
+
+// doShardingUpdate updates the records in the shards of current model.
+func (m *Model) doShardingUpdate() (result sql.Result, err error) {
+	models, err := m.getShardModels()
+	if err != nil {
+		return nil, err
+	}
+	return m.doShardingExec(make([]string, len(models)), func(i int, _ string) (sql.Result, error) {
+		return models[i].Update()
+	})
+}
+
+// doShardingDelete deletes the records in the shards of current model.
+func (m *Model) doShardingDelete() (result sql.Result, err error) {
+	models, err := m.getShardModels()
+	if err != nil {
+		return nil, err
+	}
+	return m.doShardingExec(make([]string, len(models)), func(i int, _ string) (sql.Result, error) {
+		return models[i].Delete()
+	})
+}
+
+// doShardingExec calls `f` for each shard of `shardKeys` and returns the merged result,
+// of which the affected rows is the sum of all shards, and the last insert id is of the last shard.
+func (m *Model) doShardingExec(shardKeys []string, f func(i int, shardKey string) (sql.Result, error)) (sql.Result, error) {
+	var (
+		affected   int64
+		lastResult sql.Result
+	)
+	for i, shardKey := range shardKeys {
+		result, err := f(i, shardKey)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			affected += n
+		}
+		lastResult = result
+	}
+	return &SqlResult{
+		result:   lastResult,
+		affected: affected,
+	}, nil
+}
+
+// doShardingGetAll queries the records from the shards of current model,
+// and merges the results with ordering and limit applied.
+func (m *Model) doShardingGetAll(limit1 bool) (Result, error) {
+	models, err := m.getShardModels()
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 1 {
+		return models[0].doGetAll(limit1)
+	}
+	// The aggregate fields are computed in each shard, of which the results cannot be merged.
+	if m.groupBy == "" && gregex.IsMatchString(shardingAggregateRegPattern, m.fields) {
+		return nil, gerror.Newf(
+			`aggregate fields "%s" are not supported across multiple shards, use Count/Sum/Min/Max/Avg of Model instead`,
+			m.fields,
+		)
+	}
+	var (
+		start  = 0
+		result = make(Result, 0)
+	)
+	if m.start > 0 {
+		start += m.start
+	}
+	if m.offset > 0 {
+		start += m.offset
+	}
+	for _, model := range models {
+		if m.limit > 0 {
+			// Each shard returns the records from the beginning to the end of the paging.
+			model.start, model.offset, model.limit = -1, -1, start+m.limit
+		}
+		records, err := model.doGetAll(limit1)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, records...)
+	}
+	if m.orderBy != "" {
+		sortShardingResult(result, m.orderBy)
+	}
+	if m.limit > 0 {
+		if start >= len(result) {
+			return Result{}, nil
+		}
+		result = result[start:]
+		if len(result) > m.limit {
+			result = result[:m.limit]
+		}
+	} else if limit1 && len(result) > 1 {
+		result = result[:1]
+	}
+	return result, nil
+}
+
+// doShardingCount returns the sum of record count of the shards of current model.
+func (m *Model) doShardingCount() (int, error) {
+	models, err := m.getShardModels()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, model := range models {
+		count, err := model.Count()
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// doShardingAggregateValue returns the merged result of aggregate function `function`
+// on `column` of the shards of current model.
+func (m *Model) doShardingAggregateValue(function string, column string) (float64, error) {
+	models, err := m.getShardModels()
+	if err != nil {
+		return 0, err
+	}
+	if len(models) == 1 {
+		return models[0].doGetAggregateValue(function, column)
+	}
+	var (
+		result float64
+		count  float64
+		found  bool
+	)
+	for _, model := range models {
+		if function == "AVG" {
+			one, err := model.Fields(fmt.Sprintf(
+				`SUM(%s) AS sum_value,COUNT(%s) AS count_value`,
+				m.db.QuoteWord(column), m.db.QuoteWord(column),
+			)).One()
+			if err != nil {
+				return 0, err
+			}
+			result += one["sum_value"].Float64()
+			count += one["count_value"].Float64()
+			continue
+		}
+		value, err := model.Fields(fmt.Sprintf(`%s(%s)`, function, m.db.QuoteWord(column))).Value()
+		if err != nil {
+			return 0, err
+		}
+		if value.IsNil() {
+			continue
+		}
+		switch v := value.Float64(); {
+		case !found, function == "MIN" && v < result, function == "MAX" && v > result:
+			result = v
+		case function == "SUM":
+			result += v
+		}
+		found = true
+	}
+	if function == "AVG" {
+		if count == 0 {
+			return 0, nil
+		}
+		return result / count, nil
+	}
+	return result, nil
+}
+
+// sortShardingResult sorts `result` in place by the ORDER BY statement `orderBy`, like: "id DESC, name".
+// The order items that are not fields of the result, like functions, are ignored.
+func sortShardingResult(result Result, orderBy string) {
+	type orderItem struct {
+		field string
+		desc  bool
+	}
+	items := make([]orderItem, 0)
+	for _, s := range gstr.SplitAndTrim(orderBy, ",") {
+		var (
+			array = strings.Fields(s)
+			item  = orderItem{}
+		)
+		item.field = gstr.Trim(array[0], "`\"[]")
+		if pos := gstr.PosR(item.field, "."); pos != -1 {
+			item.field = gstr.Trim(item.field[pos+1:], "`\"[]")
+		}
+		if len(array) > 1 && strings.EqualFold(array[1], "DESC") {
+			item.desc = true
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		for _, item := range items {
+			a, ok1 := result[i][item.field]
+			b, ok2 := result[j][item.field]
+			if !ok1 || !ok2 {
+				continue
+			}
+			var c int
+			switch {
+			case a.IsNil() && b.IsNil():
+				c = 0
+			case a.IsNil():
+				c = -1
+			case b.IsNil():
+				c = 1
+			default:
+				c = compareShardingValue(a.Val(), b.Val())
+			}
+			if c == 0 {
+				continue
+			}
+			if item.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
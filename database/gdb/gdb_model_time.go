@@ -8,13 +8,23 @@ package gdb
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/gogf/gf/container/garray"
+	"github.com/gogf/gf/os/gtime"
 	"github.com/gogf/gf/text/gregex"
 	"github.com/gogf/gf/text/gstr"
 	"github.com/gogf/gf/util/gconv"
 	"github.com/gogf/gf/util/gutil"
 )
 
+const (
+	SoftTimeTypeAuto      = ""          // Value type is detected from the field type of table, which is the default.
+	SoftTimeTypeDatetime  = "datetime"  // Datetime string, like: 2006-01-02 15:04:05.
+	SoftTimeTypeTimestamp = "timestamp" // Unix timestamp in seconds.
+	SoftTimeTypeFlag      = "flag"      // Flag 1/0 for deleted field, the created/updated fields use unix timestamp.
+)
+
 var (
 	createdFiledNames = []string{"created_at", "create_at"} // Default filed names of table for automatic-filled created datetime.
 	updatedFiledNames = []string{"updated_at", "update_at"} // Default filed names of table for automatic-filled updated datetime.
@@ -85,7 +95,7 @@ func (m *Model) getSoftFieldNameDeleted(table ...string) (field string) {
 		tableName = m.getPrimaryTableName()
 	}
 	config := m.db.GetConfig()
-	if config.UpdatedAt != "" {
+	if config.DeletedAt != "" {
 		return m.getSoftFieldName(tableName, []string{config.DeletedAt})
 	}
 	return m.getSoftFieldName(tableName, deletedFiledNames)
@@ -107,6 +117,91 @@ func (m *Model) getSoftFieldName(table string, keys []string) (field string) {
 	return
 }
 
+// getSoftFieldType checks and returns the value type of created/updated/deleted field `fieldName` of `table`.
+// It uses the configured SoftTimeType if it is set, or else it detects the type from the field type of table:
+// the boolean or tiny integer field is flag, the other integer field is unix timestamp, and others are datetime.
+func (m *Model) getSoftFieldType(table string, fieldName string) string {
+	if softTimeType := m.db.GetConfig().SoftTimeType; softTimeType != SoftTimeTypeAuto {
+		return softTimeType
+	}
+	fieldsMap, _ := m.db.TableFields(table)
+	field, ok := fieldsMap[fieldName]
+	if !ok {
+		return SoftTimeTypeDatetime
+	}
+	fieldType := strings.ToLower(field.Type)
+	switch {
+	case
+		gstr.HasPrefix(fieldType, "tinyint"),
+		gstr.HasPrefix(fieldType, "bit"),
+		gstr.Contains(fieldType, "bool"):
+		return SoftTimeTypeFlag
+	case gstr.Contains(fieldType, "int"):
+		return SoftTimeTypeTimestamp
+	}
+	return SoftTimeTypeDatetime
+}
+
+// isSoftFieldBool checks and returns whether the field `fieldName` of `table` is boolean type,
+// like the "bool" field of pgsql, which cannot be compared with integer.
+func (m *Model) isSoftFieldBool(table string, fieldName string) bool {
+	fieldsMap, _ := m.db.TableFields(table)
+	if field, ok := fieldsMap[fieldName]; ok {
+		return gstr.Contains(strings.ToLower(field.Type), "bool")
+	}
+	return false
+}
+
+// getSoftFieldValue returns the value of created/updated field `fieldName` of `table` for current time.
+// If `deleted` is true, it returns the value of deleted field, which is 1, or true for boolean flag field.
+func (m *Model) getSoftFieldValue(table string, fieldName string, deleted bool) interface{} {
+	switch m.getSoftFieldType(table, fieldName) {
+	case SoftTimeTypeFlag:
+		if deleted {
+			if m.isSoftFieldBool(table, fieldName) {
+				return true
+			}
+			return 1
+		}
+		return gtime.Timestamp()
+	case SoftTimeTypeTimestamp:
+		return gtime.Timestamp()
+	}
+	return gtime.Now().String()
+}
+
+// getSoftFieldValueString returns the value of getSoftFieldValue as string for sql statement.
+func (m *Model) getSoftFieldValueString(table string, fieldName string, deleted bool) string {
+	value := m.getSoftFieldValue(table, fieldName, deleted)
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf(`'%s'`, s)
+	}
+	return gconv.String(value)
+}
+
+// getConditionOfSoftDeletedField returns the condition of not deleted records for the deleted field
+// `fieldName` of `table`. The parameter `prefix` is the quoted table name or alias for the field.
+// The datetime field of deleted records is not null, and the flag or timestamp field is not zero,
+// or not true for boolean field.
+func (m *Model) getConditionOfSoftDeletedField(prefix string, table string, fieldName string) string {
+	quotedField := m.db.QuoteWord(fieldName)
+	if prefix != "" {
+		quotedField = prefix + "." + quotedField
+	}
+	if m.getSoftFieldType(table, fieldName) == SoftTimeTypeDatetime {
+		return fmt.Sprintf(`%s IS NULL`, quotedField)
+	}
+	notDeleted := "0"
+	if m.isSoftFieldBool(table, fieldName) {
+		notDeleted = "FALSE"
+	}
+	fieldsMap, _ := m.db.TableFields(table)
+	if field, ok := fieldsMap[fieldName]; ok && !field.Null {
+		return fmt.Sprintf(`%s=%s`, quotedField, notDeleted)
+	}
+	return fmt.Sprintf(`(%s IS NULL OR %s=%s)`, quotedField, quotedField, notDeleted)
+}
+
 // getConditionForSoftDeleting retrieves and returns the condition string for soft deleting.
 // It supports multiple tables string like:
 // "user u, user_detail ud"
@@ -114,7 +209,8 @@ func (m *Model) getSoftFieldName(table string, keys []string) (field string) {
 // "user LEFT JOIN user_detail ON(user_detail.uid=user.uid)"
 // "user u LEFT JOIN user_detail ud ON(ud.uid=u.uid) LEFT JOIN user_stats us ON(us.uid=u.uid)"
 func (m *Model) getConditionForSoftDeleting() string {
-	if m.unscoped {
+	// The soft deleting condition of sub-query table is handled by the sub-query model itself.
+	if m.unscoped || isSubQuery(m.tables) {
 		return ""
 	}
 	conditionArray := garray.NewStrArray()
@@ -140,7 +236,7 @@ func (m *Model) getConditionForSoftDeleting() string {
 	}
 	// Only one table.
 	if fieldName := m.getSoftFieldNameDeleted(); fieldName != "" {
-		return fmt.Sprintf(`%s IS NULL`, m.db.QuoteWord(fieldName))
+		return m.getConditionOfSoftDeletedField("", m.getPrimaryTableName(), fieldName)
 	}
 	return ""
 }
@@ -163,12 +259,12 @@ func (m *Model) getConditionOfTableStringForSoftDeleting(s string) string {
 		return ""
 	}
 	if len(array1) >= 3 {
-		return fmt.Sprintf(`%s.%s IS NULL`, m.db.QuoteWord(array1[2]), m.db.QuoteWord(field))
+		return m.getConditionOfSoftDeletedField(m.db.QuoteWord(array1[2]), table, field)
 	}
 	if len(array1) >= 2 {
-		return fmt.Sprintf(`%s.%s IS NULL`, m.db.QuoteWord(array1[1]), m.db.QuoteWord(field))
+		return m.getConditionOfSoftDeletedField(m.db.QuoteWord(array1[1]), table, field)
 	}
-	return fmt.Sprintf(`%s.%s IS NULL`, m.db.QuoteWord(table), m.db.QuoteWord(field))
+	return m.getConditionOfSoftDeletedField(m.db.QuoteWord(table), table, field)
 }
 
 // getPrimaryTableName parses and returns the primary table name.
@@ -0,0 +1,61 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"fmt"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// Union does "(SELECT xxx FROM xxx) UNION (SELECT xxx FROM xxx) ..." statement for current model
+// and given `unions` models, which removes the duplicated records from the result.
+//
+// It returns a new model that selects from the union result table, which can be chained with
+// Where/Order/Limit/... functions to filter the union result, like:
+// db.Model("user").Where("id<?", 10).Union(db.Model("user").Where("id>?", 100)).Order("id desc").All()
+func (m *Model) Union(unions ...*Model) *Model {
+	return m.doUnion(unionTypeNormal, unions...)
+}
+
+// UnionAll does "(SELECT xxx FROM xxx) UNION ALL (SELECT xxx FROM xxx) ..." statement for current
+// model and given `unions` models, which keeps the duplicated records in the result.
+// See Model.Union.
+func (m *Model) UnionAll(unions ...*Model) *Model {
+	return m.doUnion(unionTypeAll, unions...)
+}
+
+// doUnion builds the union statement of current model and given `unions` models,
+// and returns a new model operating on the union result table.
+func (m *Model) doUnion(unionType int, unions ...*Model) *Model {
+	var (
+		unionKeyword  = " UNION "
+		unionSqlArray = make([]string, 0, len(unions)+1)
+		unionArgs     = make([]interface{}, 0)
+	)
+	if unionType == unionTypeAll {
+		unionKeyword = " UNION ALL "
+	}
+	for _, v := range append([]*Model{m}, unions...) {
+		subSql, subArgs := v.getFormattedSubQuery()
+		unionSqlArray = append(unionSqlArray, fmt.Sprintf(`(%s)`, subSql))
+		unionArgs = append(unionArgs, subArgs...)
+	}
+	model := m.db.Model()
+	model.checkSubQueryError(m)
+	for _, v := range unions {
+		model.checkSubQueryError(v)
+	}
+	model.tx = m.tx
+	model.schema = m.schema
+	model.linkType = m.linkType
+	model.safe = m.safe
+	model.tables = fmt.Sprintf(
+		`(%s) AS %s`, gstr.Join(unionSqlArray, unionKeyword), m.db.QuoteWord(unionDefaultAlias),
+	)
+	model.tablesArgs = unionArgs
+	return model
+}
@@ -10,7 +10,6 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/gogf/gf/errors/gerror"
-	"github.com/gogf/gf/os/gtime"
 	"github.com/gogf/gf/text/gstr"
 	"github.com/gogf/gf/util/gconv"
 	"github.com/gogf/gf/util/gutil"
@@ -32,6 +31,12 @@ func (m *Model) Update(dataAndWhere ...interface{}) (result sql.Result, err erro
 			return m.Data(dataAndWhere[0]).Update()
 		}
 	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.shardingRule != nil {
+		return m.doShardingUpdate()
+	}
 	defer func() {
 		if err == nil {
 			m.checkAndRemoveCache()
@@ -45,6 +50,8 @@ func (m *Model) Update(dataAndWhere ...interface{}) (result sql.Result, err erro
 		fieldNameCreate                               = m.getSoftFieldNameCreated()
 		fieldNameUpdate                               = m.getSoftFieldNameUpdated()
 		fieldNameDelete                               = m.getSoftFieldNameDeleted()
+		fieldNameVersion                              = m.getFieldNameVersion()
+		versionValue                                  interface{}
 		conditionWhere, conditionExtra, conditionArgs = m.formatCondition(false, false)
 	)
 	// Automatically update the record updating time.
@@ -62,17 +69,32 @@ func (m *Model) Update(dataAndWhere ...interface{}) (result sql.Result, err erro
 			dataMap := ConvertDataForTableRecord(m.data)
 			gutil.MapDelete(dataMap, fieldNameCreate, fieldNameUpdate, fieldNameDelete)
 			if fieldNameUpdate != "" {
-				dataMap[fieldNameUpdate] = gtime.Now().String()
+				dataMap[fieldNameUpdate] = m.getSoftFieldValue(m.getPrimaryTableName(), fieldNameUpdate, false)
 			}
 			updateData = dataMap
 		default:
 			updates := gconv.String(m.data)
 			if fieldNameUpdate != "" && !gstr.Contains(updates, fieldNameUpdate) {
-				updates += fmt.Sprintf(`,%s='%s'`, fieldNameUpdate, gtime.Now().String())
+				updates += fmt.Sprintf(
+					`,%s=%s`,
+					fieldNameUpdate, m.getSoftFieldValueString(m.getPrimaryTableName(), fieldNameUpdate, false),
+				)
 			}
 			updateData = updates
 		}
 	}
+	// Optimistic locking, which increases the version automatically, and updates the record
+	// only if its version equals to the version value in the updating data.
+	if fieldNameVersion != "" {
+		updateData, versionValue = m.formatDataForVersion(updateData, fieldNameVersion)
+		if versionValue != nil && conditionWhere != "" {
+			conditionWhere = fmt.Sprintf(
+				` WHERE (%s) AND %s=?`,
+				gstr.TrimLeftStr(conditionWhere, " WHERE "), m.db.QuoteWord(fieldNameVersion),
+			)
+			conditionArgs = append(conditionArgs, versionValue)
+		}
+	}
 	newData, err := m.filterDataForInsertOrUpdate(updateData)
 	if err != nil {
 		return nil, err
@@ -81,11 +103,155 @@ func (m *Model) Update(dataAndWhere ...interface{}) (result sql.Result, err erro
 	if !gstr.ContainsI(conditionStr, " WHERE ") {
 		return nil, gerror.New("there should be WHERE condition statement for UPDATE operation")
 	}
-	return m.db.DoUpdate(
-		m.getLink(true),
-		m.tables,
-		newData,
-		conditionStr,
-		m.mergeArguments(conditionArgs)...,
+	in := &HookUpdateInput{
+		internalParamHookUpdate: internalParamHookUpdate{
+			internalParamHook: internalParamHook{
+				link: m.getLink(true),
+			},
+			handler: m.hookHandler.Update,
+		},
+		Model:     m,
+		Table:     m.tables,
+		Data:      newData,
+		Condition: conditionStr,
+		Args:      m.mergeArguments(conditionArgs),
+	}
+	result, err = in.Next(m.db.GetCtx())
+	if err == nil && versionValue != nil && !m.db.GetDryRun() {
+		if n, _ := result.RowsAffected(); n == 0 {
+			return result, &VersionConflictError{
+				Table:   m.tables,
+				Field:   fieldNameVersion,
+				Version: versionValue,
+			}
+		}
+	}
+	return result, err
+}
+
+// Increment increments a column's value by a given amount, which does
+// "UPDATE ... SET column=column+amount WHERE ..." statement for the model.
+// The parameter `amount` can be type of float or integer.
+func (m *Model) Increment(column string, amount interface{}) (sql.Result, error) {
+	return m.getModel().Data(column, &Counter{
+		Field: column,
+		Value: gconv.Float64(amount),
+	}).Update()
+}
+
+// Decrement decrements a column's value by a given amount, which does
+// "UPDATE ... SET column=column-amount WHERE ..." statement for the model.
+// The parameter `amount` can be type of float or integer.
+func (m *Model) Decrement(column string, amount interface{}) (sql.Result, error) {
+	return m.getModel().Data(column, &Counter{
+		Field: column,
+		Value: -gconv.Float64(amount),
+	}).Update()
+}
+
+// BatchUpdate updates the records of `list` with their own data in batch, which are identified by
+// the field `keyField` of each record, using "UPDATE ... SET field=CASE key WHEN ... THEN ... END"
+// statement, or the equivalent statement of the driver like "UPDATE ... FROM (VALUES ...)" for pgsql.
+//
+// The parameter `list` should be type of slice of map or struct, like: List, Result, []User.
+// The optional parameter `keyField` specifies the field identifying the records, which is the primary
+// key of the table in default. The records are split into chunks by Batch, of which each chunk is
+// updated by one statement, and the conditions of the model are also applied to the statements.
+// It returns the total affected rows count of all the chunks.
+//
+// Note that the chunks are not updated atomically unless it is in transaction, and it returns error
+// for the table having optimistic locking version field, as the versions cannot be checked and
+// increased for each record in batch, in which case use Update for each record instead.
+func (m *Model) BatchUpdate(list interface{}, keyField ...string) (result sql.Result, err error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.shardingRule != nil {
+		return nil, gerror.New("BatchUpdate is not supported for sharding model")
+	}
+	if fieldNameVersion := m.getFieldNameVersion(); fieldNameVersion != "" {
+		return nil, gerror.Newf(
+			`BatchUpdate is not supported for table "%s" with optimistic locking version field "%s"`,
+			m.getPrimaryTableName(), fieldNameVersion,
+		)
+	}
+	defer func() {
+		if err == nil {
+			m.checkAndRemoveCache()
+		}
+	}()
+	var (
+		data            List
+		key             string
+		batchNum        = defaultBatchNumber
+		batchResult     = new(SqlResult)
+		fieldNameCreate = m.getSoftFieldNameCreated()
+		fieldNameUpdate = m.getSoftFieldNameUpdated()
+		fieldNameDelete = m.getSoftFieldNameDeleted()
 	)
+	switch v := m.Clone().Data(list).data.(type) {
+	case List:
+		data = v
+	case Map:
+		data = List{v}
+	default:
+		return nil, gerror.Newf(`unsupported list type "%T" for BatchUpdate`, list)
+	}
+	if len(data) == 0 {
+		return nil, gerror.New("updating table with empty data")
+	}
+	if len(keyField) > 0 && keyField[0] != "" {
+		key = keyField[0]
+	} else if key = m.getPrimaryKey(); key == "" {
+		return nil, gerror.New("key field should be given for BatchUpdate as there's no primary key of the table")
+	}
+	newData, err := m.filterDataForInsertOrUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+	data = newData.(List)
+	for _, item := range data {
+		if _, ok := item[key]; !ok {
+			foundKey, _ := gutil.MapPossibleItemByKey(item, key)
+			if foundKey == "" {
+				return nil, gerror.Newf(`key field "%s" is missing in the data of BatchUpdate`, key)
+			}
+			item[key] = item[foundKey]
+			delete(item, foundKey)
+		}
+		// Automatically update the record updating time.
+		if !m.unscoped && fieldNameUpdate != "" {
+			gutil.MapDelete(item, fieldNameCreate, fieldNameUpdate, fieldNameDelete)
+			item[fieldNameUpdate] = m.getSoftFieldValue(m.getPrimaryTableName(), fieldNameUpdate, false)
+		}
+	}
+	if m.batch > 0 {
+		batchNum = m.batch
+	}
+	for i := 0; i < len(data); i += batchNum {
+		var (
+			chunk = data[i:]
+			keys  = make([]interface{}, 0, batchNum)
+		)
+		if len(chunk) > batchNum {
+			chunk = chunk[:batchNum]
+		}
+		for _, item := range chunk {
+			keys = append(keys, item[key])
+		}
+		conditionWhere, _, conditionArgs := m.Clone().Where(
+			fmt.Sprintf(`%s IN(?)`, m.db.QuoteWord(key)), keys,
+		).formatCondition(false, false)
+		r, err := m.db.DoBatchUpdate(m.getLink(true), m.tables, chunk, key, conditionWhere, conditionArgs...)
+		if err != nil {
+			return r, err
+		}
+		n, err := r.RowsAffected()
+		if err != nil {
+			return r, err
+		}
+		batchResult.result = r
+		batchResult.affected += n
+	}
+	return batchResult, nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/gogf/gf/text/gstr"
 	"github.com/gogf/gf/util/gconv"
 	"github.com/gogf/gf/util/gutil"
+	"sort"
 	"time"
 )
 
@@ -183,21 +184,37 @@ func (m *Model) getLink(master bool) Link {
 	return nil
 }
 
-// getPrimaryKey retrieves and returns the primary key name of the model table.
+// getPrimaryKey retrieves and returns the first primary key name of the model table.
+func (m *Model) getPrimaryKey() string {
+	if primaryKeys := m.getPrimaryKeys(); len(primaryKeys) > 0 {
+		return primaryKeys[0]
+	}
+	return ""
+}
+
+// getPrimaryKeys retrieves and returns all the primary keys of the model table in table order.
 // It parses m.tables to retrieve the primary table name, supporting m.tables like:
 // "user", "user u", "user as u, user_detail as ud".
-func (m *Model) getPrimaryKey() string {
+func (m *Model) getPrimaryKeys() []string {
 	table := gstr.SplitAndTrim(m.tables, " ")[0]
 	tableFields, err := m.db.TableFields(table)
 	if err != nil {
-		return ""
+		return nil
 	}
-	for name, field := range tableFields {
+	primaryFields := make([]*TableField, 0)
+	for _, field := range tableFields {
 		if gstr.ContainsI(field.Key, "pri") {
-			return name
+			primaryFields = append(primaryFields, field)
 		}
 	}
-	return ""
+	sort.Slice(primaryFields, func(i, j int) bool {
+		return primaryFields[i].Index < primaryFields[j].Index
+	})
+	primaryKeys := make([]string, len(primaryFields))
+	for i, field := range primaryFields {
+		primaryKeys[i] = field.Name
+	}
+	return primaryKeys
 }
 
 // formatCondition formats where arguments of the model and returns a new condition sql and its arguments.
@@ -205,54 +222,7 @@ func (m *Model) getPrimaryKey() string {
 //
 // The parameter `limit1` specifies whether limits querying only one record if m.limit is not set.
 func (m *Model) formatCondition(limit1 bool, isCountStatement bool) (conditionWhere string, conditionExtra string, conditionArgs []interface{}) {
-	if len(m.whereHolder) > 0 {
-		for _, v := range m.whereHolder {
-			switch v.operator {
-			case whereHolderWhere:
-				if conditionWhere == "" {
-					newWhere, newArgs := formatWhere(
-						m.db, v.where, v.args, m.option&OptionOmitEmpty > 0,
-					)
-					if len(newWhere) > 0 {
-						conditionWhere = newWhere
-						conditionArgs = newArgs
-					}
-					continue
-				}
-				fallthrough
-
-			case whereHolderAnd:
-				newWhere, newArgs := formatWhere(
-					m.db, v.where, v.args, m.option&OptionOmitEmpty > 0,
-				)
-				if len(newWhere) > 0 {
-					if len(conditionWhere) == 0 {
-						conditionWhere = newWhere
-					} else if conditionWhere[0] == '(' {
-						conditionWhere = fmt.Sprintf(`%s AND (%s)`, conditionWhere, newWhere)
-					} else {
-						conditionWhere = fmt.Sprintf(`(%s) AND (%s)`, conditionWhere, newWhere)
-					}
-					conditionArgs = append(conditionArgs, newArgs...)
-				}
-
-			case whereHolderOr:
-				newWhere, newArgs := formatWhere(
-					m.db, v.where, v.args, m.option&OptionOmitEmpty > 0,
-				)
-				if len(newWhere) > 0 {
-					if len(conditionWhere) == 0 {
-						conditionWhere = newWhere
-					} else if conditionWhere[0] == '(' {
-						conditionWhere = fmt.Sprintf(`%s OR (%s)`, conditionWhere, newWhere)
-					} else {
-						conditionWhere = fmt.Sprintf(`(%s) OR (%s)`, conditionWhere, newWhere)
-					}
-					conditionArgs = append(conditionArgs, newArgs...)
-				}
-			}
-		}
-	}
+	conditionWhere, conditionArgs = m.formatWhereHolder()
 	// Soft deletion.
 	softDeletingCondition := m.getConditionForSoftDeleting()
 	if !m.unscoped && softDeletingCondition != "" {
@@ -307,6 +277,58 @@ func (m *Model) formatCondition(limit1 bool, isCountStatement bool) (conditionWh
 	return
 }
 
+// formatWhereHolder formats the where conditions of the model, which are joined using "AND" or "OR"
+// according to their operators, and returns the condition sql without "WHERE" and its arguments.
+func (m *Model) formatWhereHolder() (conditionWhere string, conditionArgs []interface{}) {
+	for _, v := range m.whereHolder {
+		switch v.operator {
+		case whereHolderWhere:
+			if conditionWhere == "" {
+				newWhere, newArgs := formatWhere(
+					m.db, v.where, v.args, m.option&OptionOmitEmpty > 0,
+				)
+				if len(newWhere) > 0 {
+					conditionWhere = newWhere
+					conditionArgs = newArgs
+				}
+				continue
+			}
+			fallthrough
+
+		case whereHolderAnd:
+			newWhere, newArgs := formatWhere(
+				m.db, v.where, v.args, m.option&OptionOmitEmpty > 0,
+			)
+			if len(newWhere) > 0 {
+				if len(conditionWhere) == 0 {
+					conditionWhere = newWhere
+				} else if conditionWhere[0] == '(' {
+					conditionWhere = fmt.Sprintf(`%s AND (%s)`, conditionWhere, newWhere)
+				} else {
+					conditionWhere = fmt.Sprintf(`(%s) AND (%s)`, conditionWhere, newWhere)
+				}
+				conditionArgs = append(conditionArgs, newArgs...)
+			}
+
+		case whereHolderOr:
+			newWhere, newArgs := formatWhere(
+				m.db, v.where, v.args, m.option&OptionOmitEmpty > 0,
+			)
+			if len(newWhere) > 0 {
+				if len(conditionWhere) == 0 {
+					conditionWhere = newWhere
+				} else if conditionWhere[0] == '(' {
+					conditionWhere = fmt.Sprintf(`%s OR (%s)`, conditionWhere, newWhere)
+				} else {
+					conditionWhere = fmt.Sprintf(`(%s) OR (%s)`, conditionWhere, newWhere)
+				}
+				conditionArgs = append(conditionArgs, newArgs...)
+			}
+		}
+	}
+	return
+}
+
 // mergeArguments creates and returns new arguments by merging <m.extraArgs> and given `args`.
 func (m *Model) mergeArguments(args []interface{}) []interface{} {
 	if len(m.extraArgs) > 0 {
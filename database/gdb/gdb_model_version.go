@@ -0,0 +1,103 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/gogf/gf/internal/empty"
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/util/gconv"
+	"github.com/gogf/gf/util/gutil"
+)
+
+var (
+	versionFieldNames = []string{"version", "revision"} // Default field names of table for optimistic locking.
+
+	// ErrVersionConflict is the error that can be checked using errors.Is for the error returned by
+	// Model.Update, if no record is updated with optimistic locking.
+	ErrVersionConflict = errors.New("version conflict")
+)
+
+// VersionConflictError is the error returned by Model.Update with optimistic locking if no record is
+// updated, which means the record has been updated by others, or it does not exist.
+type VersionConflictError struct {
+	Table   string      // Table of the updating operation.
+	Field   string      // Version field name.
+	Version interface{} // Version value expected by the updating operation.
+}
+
+// Error implements the interface error.
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf(
+		`version conflict on table %s: no record is updated with %s=%v`,
+		e.Table, e.Field, e.Version,
+	)
+}
+
+// Is implements the interface for errors.Is, which checks whether `target` is ErrVersionConflict.
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// getFieldNameVersion checks and returns the field name for optimistic locking version.
+// If there's no field name for storing version, it returns an empty string.
+// It checks the key with or without cases or chars '-'/'_'/'.'/' '.
+func (m *Model) getFieldNameVersion(table ...string) string {
+	tableName := ""
+	if len(table) > 0 {
+		tableName = table[0]
+	} else {
+		tableName = m.getPrimaryTableName()
+	}
+	config := m.db.GetConfig()
+	if config.VersionAt != "" {
+		return m.getSoftFieldName(tableName, []string{config.VersionAt})
+	}
+	return m.getSoftFieldName(tableName, versionFieldNames)
+}
+
+// formatDataForVersion adds the version increasing to updating `data`, and returns the new data and
+// the expected version value in `data`, which is nil if there's no version value given in `data`.
+func (m *Model) formatDataForVersion(data interface{}, fieldNameVersion string) (newData interface{}, version interface{}) {
+	var (
+		quotedField = m.db.QuoteWord(fieldNameVersion)
+		reflectKind = reflect.Indirect(reflect.ValueOf(data)).Kind()
+	)
+	switch reflectKind {
+	case reflect.Map, reflect.Struct:
+		dataMap := ConvertDataForTableRecord(data)
+		if key, value := gutil.MapPossibleItemByKey(dataMap, fieldNameVersion); key != "" {
+			switch value.(type) {
+			case Raw, Counter, *Counter:
+				// Custom version updating.
+				return dataMap, nil
+			}
+			delete(dataMap, key)
+			if !empty.IsNil(value) {
+				version = value
+			}
+		}
+		dataMap[fieldNameVersion] = &Counter{
+			Field: fieldNameVersion,
+			Value: 1,
+		}
+		return dataMap, version
+	default:
+		// Custom version updating if the version field is assigned in the updating string,
+		// like: "version=version+2", "`version` = 10".
+		updates := gconv.String(data)
+		pattern := fmt.Sprintf("(?i)(^|[\\s,.])[`\"]?%s[`\"]?\\s*=", regexp.QuoteMeta(fieldNameVersion))
+		if !gregex.IsMatchString(pattern, updates) {
+			updates += fmt.Sprintf(`,%s=%s+1`, quotedField, quotedField)
+		}
+		return updates, nil
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gogf/gf/errors/gerror"
+)
+
+var (
+	// columnNameRegex is the regular expression for each part of the column name,
+	// which is the same as the quotable words of QuoteWord.
+	columnNameRegex = regexp.MustCompile(`^[a-zA-Z0-9\-_]+$`)
+)
+
+// WhereIn builds "column IN(?)" statement, in which the parameter `in` is usually a slice or
+// a sub-query model. It builds a condition that matches nothing if `in` is an empty slice.
+func (m *Model) WhereIn(column string, in interface{}) *Model {
+	if isEmptySlice(in) {
+		// It uses its own condition instead of the empty slice, which makes the condition
+		// matching nothing work well with other conditions joined using "OR".
+		return m.Where("0=1")
+	}
+	return m.whereColumn(`%s IN(?)`, column, in)
+}
+
+// WhereNotIn builds "column NOT IN(?)" statement, in which the parameter `in` is usually a slice.
+// It does nothing if `in` is an empty slice.
+func (m *Model) WhereNotIn(column string, in interface{}) *Model {
+	if isEmptySlice(in) {
+		return m.getModel()
+	}
+	return m.whereColumn(`%s NOT IN(?)`, column, in)
+}
+
+// WhereBetween builds "column BETWEEN min AND max" statement.
+func (m *Model) WhereBetween(column string, min, max interface{}) *Model {
+	return m.whereColumn(`%s BETWEEN ? AND ?`, column, min, max)
+}
+
+// WhereNotBetween builds "column NOT BETWEEN min AND max" statement.
+func (m *Model) WhereNotBetween(column string, min, max interface{}) *Model {
+	return m.whereColumn(`%s NOT BETWEEN ? AND ?`, column, min, max)
+}
+
+// WhereLike builds "column LIKE like" statement, in which the parameter `like` can contain
+// wildcards, like: "john%".
+func (m *Model) WhereLike(column string, like interface{}) *Model {
+	return m.whereColumn(`%s LIKE ?`, column, like)
+}
+
+// WhereNotLike builds "column NOT LIKE like" statement.
+func (m *Model) WhereNotLike(column string, like interface{}) *Model {
+	return m.whereColumn(`%s NOT LIKE ?`, column, like)
+}
+
+// WhereNull builds "column IS NULL" statement for each of `columns`, which are joined using "AND".
+func (m *Model) WhereNull(columns ...string) *Model {
+	model := m.getModel()
+	for _, column := range columns {
+		model = model.whereColumn(`%s IS NULL`, column)
+	}
+	return model
+}
+
+// WhereNotNull builds "column IS NOT NULL" statement for each of `columns`, which are joined using "AND".
+func (m *Model) WhereNotNull(columns ...string) *Model {
+	model := m.getModel()
+	for _, column := range columns {
+		model = model.whereColumn(`%s IS NOT NULL`, column)
+	}
+	return model
+}
+
+// WhereGT builds "column > value" statement.
+func (m *Model) WhereGT(column string, value interface{}) *Model {
+	return m.whereColumn(`%s > ?`, column, value)
+}
+
+// WhereGTE builds "column >= value" statement.
+func (m *Model) WhereGTE(column string, value interface{}) *Model {
+	return m.whereColumn(`%s >= ?`, column, value)
+}
+
+// WhereLT builds "column < value" statement.
+func (m *Model) WhereLT(column string, value interface{}) *Model {
+	return m.whereColumn(`%s < ?`, column, value)
+}
+
+// WhereLTE builds "column <= value" statement.
+func (m *Model) WhereLTE(column string, value interface{}) *Model {
+	return m.whereColumn(`%s <= ?`, column, value)
+}
+
+// WhereBuilder builds the conditions of `f` as a group, which is enclosed in parentheses
+// and joined with other conditions using "AND". It is used for nested conditions.
+// Eg:
+// WhereBuilder(func(b *Model) *Model { return b.Where("a", 1).Or("b", 2) }).Where("c", 3)
+// builds condition "((a=1) OR (b=2)) AND (c=3)".
+//
+// The model `b` passed to `f` is used only for building the conditions, of which the methods other
+// than the where builders, like Order/Limit, make no sense.
+func (m *Model) WhereBuilder(f func(b *Model) *Model) *Model {
+	condition, args, err := m.buildWhereGroup(f)
+	if err != nil {
+		model := m.getModel()
+		model.err = err
+		return model
+	}
+	if condition == "" {
+		return m.getModel()
+	}
+	return m.Where(condition, args...)
+}
+
+// OrBuilder builds the conditions of `f` as a group, which is enclosed in parentheses
+// and joined with other conditions using "OR". See WhereBuilder.
+func (m *Model) OrBuilder(f func(b *Model) *Model) *Model {
+	condition, args, err := m.buildWhereGroup(f)
+	if err != nil {
+		model := m.getModel()
+		model.err = err
+		return model
+	}
+	if condition == "" {
+		return m.getModel()
+	}
+	return m.Or(condition, args...)
+}
+
+// buildWhereGroup calls `f` with an empty condition model and returns its conditions enclosed
+// in parentheses, along with the condition arguments and the error occurred in building.
+func (m *Model) buildWhereGroup(f func(b *Model) *Model) (condition string, args []interface{}, err error) {
+	builder := m.Clone()
+	builder.whereHolder = nil
+	if builder = f(builder); builder == nil {
+		return "", nil, nil
+	}
+	if builder.err != nil {
+		return "", nil, builder.err
+	}
+	if condition, args = builder.formatWhereHolder(); condition != "" {
+		condition = "(" + condition + ")"
+	}
+	return
+}
+
+// checkSubQueryError marks the model failed with the error of the sub-query models in `values`,
+// which prevents the failed sub-query being committed without its conditions.
+func (m *Model) checkSubQueryError(values ...interface{}) {
+	if m.err != nil {
+		return
+	}
+	m.err = getSubQueryError(values...)
+}
+
+// whereColumn adds the condition formatted using `format` and quoted `column` with arguments `args`.
+// It marks the model failed if `column` is not a valid column name, which prevents SQL injection.
+func (m *Model) whereColumn(format string, column string, args ...interface{}) *Model {
+	quoted, err := m.quoteColumn(column)
+	if err != nil {
+		model := m.getModel()
+		model.err = err
+		return model
+	}
+	return m.Where(fmt.Sprintf(format, quoted), args...)
+}
+
+// quoteColumn quotes the column name using QuoteWord of the driver,
+// which can be prefixed with table name or alias, like: "user.id", "u.id".
+// It returns error if any part of the column name is not a valid identifier.
+func (m *Model) quoteColumn(column string) (string, error) {
+	array := strings.Split(strings.TrimSpace(column), ".")
+	for i, v := range array {
+		if !columnNameRegex.MatchString(v) {
+			return "", gerror.Newf(`invalid column name "%s"`, column)
+		}
+		array[i] = m.db.QuoteWord(v)
+	}
+	return strings.Join(array, "."), nil
+}
+
+// isEmptySlice checks and returns whether `value` is a slice or array without elements.
+func isEmptySlice(value interface{}) bool {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return rv.Len() == 0
+	}
+	return false
+}
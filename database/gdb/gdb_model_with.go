@@ -7,15 +7,24 @@
 package gdb
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/gogf/gf/errors/gerror"
 	"github.com/gogf/gf/internal/structs"
 	"github.com/gogf/gf/internal/utils"
 	"github.com/gogf/gf/text/gregex"
 	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gconv"
 	"reflect"
+	"sort"
 )
 
+// withItem is the item of With feature, which is the relation object along with its query option.
+type withItem struct {
+	object interface{}           // Relation object, like: User{}.UserDetail, UserDetail{}.
+	option func(m *Model) *Model // Custom option for the relation query, like conditions, ordering, limits and fields.
+}
+
 // With creates and returns an ORM model based on meta data of given object.
 // It also enables model association operations feature on given `object`.
 // It can be called multiple times to add one or more objects to model and enable
@@ -32,13 +41,27 @@ import (
 //     db.With(User{}.UserDetail).With(User{}.UserDetail).Scan(xxx)
 // Or:
 //     db.With(UserDetail{}).With(UserDetail{}).Scan(xxx)
-func (m *Model) With(object interface{}) *Model {
+//
+// The relations of the related objects are also loaded if they are enabled using With or WithAll,
+// like attribute `Address` of `UserDetail`. The related records of a struct slice are retrieved using
+// only one "IN" query for each relation attribute.
+//
+// The optional parameter `option` customizes the relation query, like conditions, ordering, limits
+// and fields. Note that the limits are applied for each of the parent records, eg:
+// db.With(User{}.UserScores, func(m *Model) *Model { return m.Order("score desc").Limit(3) })
+func (m *Model) With(object interface{}, option ...func(m *Model) *Model) *Model {
 	model := m.getModel()
 	if m.tables == "" {
 		m.tables = m.db.QuotePrefixTableName(getTableNameFromOrmTag(object))
 		return model
 	}
-	model.withArray = append(model.withArray, object)
+	item := &withItem{
+		object: object,
+	}
+	if len(option) > 0 {
+		item.option = option[0]
+	}
+	model.withArray = append(model.withArray, item)
 	return model
 }
 
@@ -49,18 +72,59 @@ func (m *Model) WithAll() *Model {
 	return model
 }
 
-// getWithTagObjectArrayFrom retrieves and returns object array that have "with" tag in the struct.
-func (m *Model) getWithTagObjectArrayFrom(pointer interface{}) ([]interface{}, error) {
-	fieldMap, err := structs.FieldMap(pointer, nil)
+// getWithItem retrieves and returns the with item of which the relation object type is `fieldType`.
+// It returns nil if there's no matched item.
+func (m *Model) getWithItem(fieldType string) (*withItem, error) {
+	for _, item := range m.withArray {
+		itemType, err := structs.StructType(item.object)
+		if err != nil {
+			return nil, err
+		}
+		if gstr.TrimAll(itemType.String(), "*[]") == fieldType {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+// doWithScanStruct handles model association operations feature for single struct.
+func (m *Model) doWithScanStruct(pointer interface{}) error {
+	return m.doWithScan(pointer)
+}
+
+// doWithScanStructs handles model association operations feature for struct slice.
+func (m *Model) doWithScanStructs(pointer interface{}) error {
+	return m.doWithScan(pointer)
+}
+
+// doWithScan handles model association operations feature for `pointer`,
+// which can be type of *struct/**struct/*[]struct/*[]*struct.
+func (m *Model) doWithScan(pointer interface{}) error {
+	if !m.withAll && len(m.withArray) == 0 {
+		return nil
+	}
+	parents := getWithParentValues(pointer)
+	if len(parents) == 0 {
+		return nil
+	}
+	fieldMap, err := structs.FieldMap(parents[0].Addr().Interface(), nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	withTagObjectArray := make([]interface{}, 0)
-	for _, fieldValue := range fieldMap {
+	parentTypeStr := parents[0].Type().String()
+	// Sort the attribute names for stable relation query ordering.
+	fieldNames := make([]string, 0, len(fieldMap))
+	for fieldName := range fieldMap {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
 		var (
-			withTag  string
-			ormTag   = fieldValue.Tag(OrmTagForStruct)
-			match, _ = gregex.MatchString(
+			fieldValue   = fieldMap[fieldName]
+			fieldTypeStr = gstr.TrimAll(fieldValue.Type().String(), "*[]")
+			withTag      string
+			ormTag       = fieldValue.Tag(OrmTagForStruct)
+			match, _     = gregex.MatchString(
 				fmt.Sprintf(`%s\s*:\s*([^,]+)`, OrmTagForWith),
 				ormTag,
 			)
@@ -71,186 +135,210 @@ func (m *Model) getWithTagObjectArrayFrom(pointer interface{}) ([]interface{}, e
 		if withTag == "" {
 			continue
 		}
-		withTagObjectArray = append(withTagObjectArray, fieldValue.Value.Interface())
+		// Circular relations are loaded only once in the relation chain.
+		if gstr.InArray(m.withChain, fieldTypeStr) {
+			continue
+		}
+		item, err := m.getWithItem(fieldTypeStr)
+		if err != nil {
+			return err
+		}
+		if item == nil && !m.withAll {
+			continue
+		}
+		array := gstr.SplitAndTrim(withTag, "=")
+		if len(array) != 2 {
+			return gerror.Newf(`invalid with tag "%s"`, withTag)
+		}
+		var (
+			relatedFieldName = array[0]
+			relatedAttrName  string
+		)
+		// Find the related attribute name from parent struct.
+		for attributeName := range fieldMap {
+			if utils.EqualFoldWithoutChars(attributeName, array[1]) {
+				relatedAttrName = attributeName
+				break
+			}
+		}
+		if relatedAttrName == "" {
+			return gerror.Newf(
+				`cannot find the related value for attribute name "%s" of with tag "%s"`,
+				array[1], withTag,
+			)
+		}
+		err = m.doWithScanRelation(parents, parentTypeStr, fieldName, relatedFieldName, relatedAttrName, item)
+		if err != nil {
+			return err
+		}
 	}
-	return withTagObjectArray, nil
+	return nil
 }
 
-// doWithScanStruct handles model association operations feature for single struct.
-func (m *Model) doWithScanStruct(pointer interface{}) error {
+// doWithScanRelation retrieves the related records of attribute `fieldName` for all `parents` using
+// one "IN" query, and binds them to the attribute of each parent by the related field value.
+func (m *Model) doWithScanRelation(
+	parents []reflect.Value, parentTypeStr, fieldName, relatedFieldName, relatedAttrName string, item *withItem,
+) error {
+	fieldType, err := structs.StructType(parents[0].FieldByName(fieldName))
+	if err != nil {
+		return err
+	}
 	var (
-		err       error
-		withArray = m.withArray
+		relatedValues = make([]interface{}, 0, len(parents))
+		relatedKeyMap = make(map[string]struct{}, len(parents))
 	)
-	if m.withAll {
-		withArray, err = m.getWithTagObjectArrayFrom(pointer)
-		if err != nil {
-			return err
+	for _, parent := range parents {
+		v := parent.FieldByName(relatedAttrName).Interface()
+		k := gconv.String(v)
+		if _, ok := relatedKeyMap[k]; ok {
+			continue
 		}
+		relatedKeyMap[k] = struct{}{}
+		relatedValues = append(relatedValues, v)
 	}
-	if len(withArray) == 0 {
-		return nil
+	// The relation attribute name of the related struct, eg: Uid.
+	var relatedFieldAttrName string
+	for i := 0; i < fieldType.NumField(); i++ {
+		if utils.EqualFoldWithoutChars(fieldType.Field(i).Name, relatedFieldName) {
+			relatedFieldAttrName = fieldType.Field(i).Name
+			break
+		}
 	}
-	fieldMap, err := structs.FieldMap(pointer, nil)
-	if err != nil {
+	if relatedFieldAttrName == "" {
+		return gerror.Newf(
+			`cannot find the related attribute for field name "%s" in struct "%s"`,
+			relatedFieldName, fieldType.String(),
+		)
+	}
+	model := m.db.With(reflect.New(fieldType.Type).Interface())
+	if m.tx != nil {
+		model = m.tx.With(reflect.New(fieldType.Type).Interface())
+	}
+	// The relations of the related struct are loaded with the same with items.
+	model.withArray = m.withArray
+	model.withAll = m.withAll
+	model.withChain = append(append(make([]string, 0, len(m.withChain)+1), m.withChain...), parentTypeStr)
+	// The relation query is also cached and tagged with its table if the main query is cached.
+	if m.cacheEnabled && m.cacheDuration >= 0 {
+		model = model.Cache(m.cacheDuration)
+	}
+	model = model.Fields(fieldType.FieldKeys())
+	if item != nil && item.option != nil {
+		if model = item.option(model); model == nil {
+			return gerror.Newf(`option function of with attribute "%s" returns nil model`, fieldName)
+		}
+		// The related field is necessary for binding.
+		if model.fields != "*" {
+			fields := gstr.SplitAndTrim(model.fields, ",")
+			if !gstr.InArray(fields, relatedFieldName) && !gstr.InArray(fields, m.db.QuoteWord(relatedFieldName)) {
+				model.fields += "," + relatedFieldName
+			}
+		}
+	}
+	// The limits are applied for each parent record, but not the relation query.
+	var (
+		start = model.start
+		limit = model.limit
+	)
+	model.start, model.limit = -1, 0
+	var (
+		listValue   = reflect.New(reflect.SliceOf(reflect.PtrTo(fieldType.Type)))
+		relatedList = make(map[string][]reflect.Value)
+	)
+	err = model.Where(relatedFieldName, relatedValues).Scan(listValue.Interface())
+	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
-	for withIndex, withItem := range withArray {
-		withItemReflectValueType, err := structs.StructType(withItem)
-		if err != nil {
-			return err
+	listValue = listValue.Elem()
+	for i := 0; i < listValue.Len(); i++ {
+		var (
+			itemValue = listValue.Index(i)
+			key       = gconv.String(itemValue.Elem().FieldByName(relatedFieldAttrName).Interface())
+		)
+		relatedList[key] = append(relatedList[key], itemValue)
+	}
+	// Bind the related records to each parent.
+	for _, parent := range parents {
+		var (
+			items       = relatedList[gconv.String(parent.FieldByName(relatedAttrName).Interface())]
+			bindToValue = parent.FieldByName(fieldName)
+		)
+		if len(items) == 0 {
+			continue
 		}
-		withItemReflectValueTypeStr := gstr.TrimAll(withItemReflectValueType.String(), "*[]")
-		for _, fieldValue := range fieldMap {
-			var (
-				fieldType    = fieldValue.Type()
-				fieldTypeStr = gstr.TrimAll(fieldType.String(), "*[]")
-			)
-			if gstr.Compare(fieldTypeStr, withItemReflectValueTypeStr) == 0 {
-				var (
-					withTag  string
-					ormTag   = fieldValue.Tag(OrmTagForStruct)
-					match, _ = gregex.MatchString(
-						fmt.Sprintf(`%s\s*:\s*([^,]+)`, OrmTagForWith),
-						ormTag,
-					)
-				)
-				if len(match) > 1 {
-					withTag = match[1]
-				}
-				if withTag == "" {
+		switch bindToValue.Kind() {
+		case reflect.Slice:
+			if start > 0 {
+				if start >= len(items) {
 					continue
 				}
-				array := gstr.SplitAndTrim(withTag, "=")
-				if len(array) != 2 {
-					return gerror.Newf(`invalid with tag "%s"`, withTag)
-				}
-				var (
-					relatedFieldName  = array[0]
-					relatedAttrName   = array[1]
-					relatedFieldValue interface{}
-				)
-				// Find the value of related attribute from `pointer`.
-				for attributeName, attributeValue := range fieldMap {
-					if utils.EqualFoldWithoutChars(attributeName, relatedAttrName) {
-						relatedFieldValue = attributeValue.Value.Interface()
-						break
-					}
-				}
-				if relatedFieldValue == nil {
-					return gerror.Newf(
-						`cannot find the related value for attribute name "%s" of with tag "%s"`,
-						relatedAttrName, withTag,
-					)
-				}
-				bindToReflectValue := fieldValue.Value
-				switch bindToReflectValue.Kind() {
-				case reflect.Array, reflect.Slice:
-					if bindToReflectValue.CanAddr() {
-						bindToReflectValue = bindToReflectValue.Addr()
-					}
-				}
-				model := m.db.With(fieldValue.Value)
-				for i, v := range withArray {
-					if i == withIndex {
-						continue
-					}
-					model = model.With(v)
-				}
-				err = model.Fields(withItemReflectValueType.FieldKeys()).
-					Where(relatedFieldName, relatedFieldValue).
-					Scan(bindToReflectValue)
-				if err != nil {
-					return err
+				items = items[start:]
+			}
+			if limit > 0 && limit < len(items) {
+				items = items[:limit]
+			}
+			var (
+				isPtrElem  = bindToValue.Type().Elem().Kind() == reflect.Ptr
+				sliceValue = reflect.MakeSlice(bindToValue.Type(), 0, len(items))
+			)
+			for _, v := range items {
+				if isPtrElem {
+					sliceValue = reflect.Append(sliceValue, v)
+				} else {
+					sliceValue = reflect.Append(sliceValue, v.Elem())
 				}
 			}
+			bindToValue.Set(sliceValue)
+
+		case reflect.Ptr:
+			bindToValue.Set(items[0])
+
+		case reflect.Struct:
+			bindToValue.Set(items[0].Elem())
+
+		default:
+			return gerror.Newf(
+				`unsupported type "%s" of with attribute "%s"`, bindToValue.Type().String(), fieldName,
+			)
 		}
 	}
 	return nil
 }
 
-// doWithScanStructs handles model association operations feature for struct slice.
-func (m *Model) doWithScanStructs(pointer interface{}) error {
-	var (
-		err       error
-		withArray = m.withArray
-	)
-	if m.withAll {
-		withArray, err = m.getWithTagObjectArrayFrom(pointer)
-		if err != nil {
-			return err
-		}
-	}
-	if len(withArray) == 0 {
-		return nil
+// getWithParentValues retrieves and returns the struct values from `pointer`, which can be type of
+// *struct/**struct/*[]struct/*[]*struct. The nil items are ignored.
+func getWithParentValues(pointer interface{}) []reflect.Value {
+	var reflectValue reflect.Value
+	if v, ok := pointer.(reflect.Value); ok {
+		reflectValue = v
+	} else {
+		reflectValue = reflect.ValueOf(pointer)
 	}
-	fieldMap, err := structs.FieldMap(pointer, nil)
-	if err != nil {
-		return err
+	for reflectValue.Kind() == reflect.Ptr || reflectValue.Kind() == reflect.Interface {
+		if reflectValue.IsNil() {
+			return nil
+		}
+		reflectValue = reflectValue.Elem()
 	}
-	for withIndex, withItem := range withArray {
-		withItemReflectValueType, err := structs.StructType(withItem)
-		if err != nil {
-			return err
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		if reflectValue.CanAddr() {
+			return []reflect.Value{reflectValue}
 		}
-		withItemReflectValueTypeStr := gstr.TrimAll(withItemReflectValueType.String(), "*[]")
-		for fieldName, fieldValue := range fieldMap {
-			var (
-				fieldType    = fieldValue.Type()
-				fieldTypeStr = gstr.TrimAll(fieldType.String(), "*[]")
-			)
-			if gstr.Compare(fieldTypeStr, withItemReflectValueTypeStr) == 0 {
-				var (
-					withTag  string
-					ormTag   = fieldValue.Tag(OrmTagForStruct)
-					match, _ = gregex.MatchString(
-						fmt.Sprintf(`%s\s*:\s*([^,]+)`, OrmTagForWith),
-						ormTag,
-					)
-				)
-				if len(match) > 1 {
-					withTag = match[1]
-				}
-				if withTag == "" {
-					continue
-				}
-				array := gstr.SplitAndTrim(withTag, "=")
-				if len(array) != 2 {
-					return gerror.Newf(`invalid with tag "%s"`, withTag)
-				}
-				var (
-					relatedFieldName  = array[0]
-					relatedAttrName   = array[1]
-					relatedFieldValue interface{}
-				)
-				// Find the value slice of related attribute from `pointer`.
-				for attributeName, _ := range fieldMap {
-					if utils.EqualFoldWithoutChars(attributeName, relatedAttrName) {
-						relatedFieldValue = ListItemValuesUnique(pointer, attributeName)
-						break
-					}
-				}
-				if relatedFieldValue == nil {
-					return gerror.Newf(
-						`cannot find the related value for attribute name "%s" of with tag "%s"`,
-						relatedAttrName, withTag,
-					)
-				}
-				model := m.db.With(fieldValue.Value)
-				for i, v := range withArray {
-					if i == withIndex {
-						continue
-					}
-					model = model.With(v)
-				}
-				err = model.Fields(withItemReflectValueType.FieldKeys()).
-					Where(relatedFieldName, relatedFieldValue).
-					ScanList(pointer, fieldName, withTag)
-				if err != nil {
-					return err
-				}
+
+	case reflect.Slice, reflect.Array:
+		values := make([]reflect.Value, 0, reflectValue.Len())
+		for i := 0; i < reflectValue.Len(); i++ {
+			itemValue := reflectValue.Index(i)
+			for itemValue.Kind() == reflect.Ptr && !itemValue.IsNil() {
+				itemValue = itemValue.Elem()
+			}
+			if itemValue.Kind() == reflect.Struct && itemValue.CanAddr() {
+				values = append(values, itemValue)
 			}
 		}
+		return values
 	}
 	return nil
 }
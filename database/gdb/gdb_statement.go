@@ -11,6 +11,7 @@ import (
 	"database/sql"
 	"github.com/gogf/gf/errors/gerror"
 	"github.com/gogf/gf/os/gtime"
+	"time"
 )
 
 // Stmt is a prepared statement.
@@ -38,6 +39,7 @@ const (
 func (s *Stmt) doStmtCommit(stmtType string, ctx context.Context, args ...interface{}) (result interface{}, err error) {
 	var (
 		cancelFuncForTimeout context.CancelFunc
+		startTime            = time.Now()
 		timestampMilli1      = gtime.TimestampMilli()
 	)
 	switch stmtType {
@@ -60,6 +62,7 @@ func (s *Stmt) doStmtCommit(stmtType string, ctx context.Context, args ...interf
 		panic(gerror.Newf(`invalid stmtType: %s`, stmtType))
 	}
 	var (
+		latency         = time.Since(startTime)
 		timestampMilli2 = gtime.TimestampMilli()
 		sqlObj          = &Sql{
 			Sql:    s.sql,
@@ -72,7 +75,17 @@ func (s *Stmt) doStmtCommit(stmtType string, ctx context.Context, args ...interf
 			Group:  s.core.db.GetGroup(),
 		}
 	)
+	if stmtType == stmtTypeExecContext && err == nil {
+		// The statement prepared by DB with context marks both the contexts of the DB and the statement.
+		s.core.markWritten(s.core.GetCtx())
+		s.core.markWritten(ctx)
+	}
 	s.core.addSqlToTracing(ctx, sqlObj)
+	if sqlResult, ok := result.(sql.Result); ok {
+		s.core.addSqlToStats(sqlObj, sqlResult, latency)
+	} else {
+		s.core.addSqlToStats(sqlObj, nil, latency)
+	}
 	if s.core.db.GetDebug() {
 		s.core.writeSqlToLogger(sqlObj)
 	}
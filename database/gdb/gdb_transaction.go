@@ -7,28 +7,213 @@
 package gdb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
 
+	"github.com/gogf/gf/errors/gerror"
 	"github.com/gogf/gf/text/gregex"
 )
 
 // TX is the struct for transaction management.
 type TX struct {
-	db     DB
-	tx     *sql.Tx
-	master *sql.DB
+	db               DB              // db is the current gdb database manager.
+	tx               *sql.Tx         // tx is the raw and underlying transaction manager.
+	ctx              context.Context // ctx is the context for this transaction only, which carries the transaction itself.
+	master           *sql.DB         // master is the raw and underlying database manager.
+	transactionCount int             // transactionCount marks the nested level of Begin calls using savepoints.
+	isClosed         bool            // isClosed marks this transaction has already been committed or rolled back.
+	cacheTagTables   []string        // cacheTagTables is the written tables of which the cache tags are changed after the transaction ends.
+}
+
+const (
+	savePointOperationCreate    = 1                            // Creates a savepoint.
+	savePointOperationRollback  = 2                            // Rollbacks to a savepoint.
+	savePointOperationRelease   = 3                            // Releases a savepoint.
+	transactionSavePointPrefix  = "gf_savepoint_"              // Prefix of the automatically created savepoint names for nested transactions.
+	contextTransactionKeyPrefix = "TransactionObjectForGroup_" // Context key prefix for storing transaction object of certain group.
+)
+
+// WithTX injects given transaction object into context and returns a new context.
+// Any DB/Model operation using the returned context on the same configuration group
+// joins the transaction, and DB.Transaction/Model.Transaction create nested transactions
+// using savepoints instead of starting a new transaction.
+func WithTX(ctx context.Context, tx *TX) context.Context {
+	if tx == nil {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Check repeat injection.
+	if ctxTx := TXFromCtx(ctx, tx.db.GetGroup()); ctxTx == tx {
+		return ctx
+	}
+	return context.WithValue(ctx, transactionKeyForContext(tx.db.GetGroup()), tx)
+}
+
+// TXFromCtx retrieves and returns the transaction object of configuration group `group`
+// from context. It returns nil if there's no transaction in the context, or the transaction
+// is already closed.
+func TXFromCtx(ctx context.Context, group string) *TX {
+	if ctx == nil {
+		return nil
+	}
+	v := ctx.Value(transactionKeyForContext(group))
+	if v != nil {
+		tx := v.(*TX)
+		if tx.IsClosed() {
+			return nil
+		}
+		return tx
+	}
+	return nil
+}
+
+// transactionKeyForContext forms and returns a string for storing transaction object
+// of certain database group into context.
+func transactionKeyForContext(group string) string {
+	return contextTransactionKeyPrefix + group
+}
+
+// GetCtx returns the context of current transaction, which carries the transaction object.
+// It can be passed to DB.Ctx/Model.Ctx so that the following operations join the transaction.
+func (tx *TX) GetCtx() context.Context {
+	return tx.ctx
+}
+
+// GetDB returns the DB of current transaction.
+func (tx *TX) GetDB() DB {
+	return tx.db
+}
+
+// IsClosed checks and returns whether this transaction has already been committed or rolled back.
+func (tx *TX) IsClosed() bool {
+	return tx.isClosed
+}
+
+// Begin starts a nested transaction using savepoint.
+// The nested transaction should be committed or rolled back using Commit or Rollback,
+// which releases or rollbacks to the savepoint that Begin creates.
+func (tx *TX) Begin() error {
+	if err := tx.SavePoint(tx.transactionSavePointName()); err != nil {
+		return err
+	}
+	tx.transactionCount++
+	return nil
 }
 
 // Commit commits the transaction.
+// If it is a nested transaction started by TX.Begin, it releases the savepoint of the
+// nested transaction and does not commit the underlying transaction.
 func (tx *TX) Commit() error {
-	return tx.tx.Commit()
+	if tx.transactionCount > 0 {
+		tx.transactionCount--
+		return tx.ReleaseSavePoint(tx.transactionSavePointName())
+	}
+	defer tx.removeCacheByTags()
+	if err := tx.tx.Commit(); err != nil {
+		return err
+	}
+	tx.isClosed = true
+	return nil
 }
 
 // Rollback aborts the transaction.
+// If it is a nested transaction started by TX.Begin, it rollbacks to the savepoint of the
+// nested transaction and does not roll back the underlying transaction.
 func (tx *TX) Rollback() error {
-	return tx.tx.Rollback()
+	if tx.transactionCount > 0 {
+		tx.transactionCount--
+		return tx.RollbackTo(tx.transactionSavePointName())
+	}
+	defer tx.removeCacheByTags()
+	if err := tx.tx.Rollback(); err != nil {
+		return err
+	}
+	tx.isClosed = true
+	return nil
+}
+
+// addCacheTagTables adds the written `tables`, of which the cache tags are changed after the transaction ends.
+func (tx *TX) addCacheTagTables(tables []string) {
+	tx.cacheTagTables = append(tx.cacheTagTables, tables...)
+}
+
+// removeCacheByTags changes the cache tags of the tables written in the transaction,
+// which invalidates the cached selecting results touching these tables.
+func (tx *TX) removeCacheByTags() {
+	if len(tx.cacheTagTables) > 0 {
+		removeCacheByTags(tx.db, tx.cacheTagTables)
+		tx.cacheTagTables = nil
+	}
+}
+
+// SavePoint creates a savepoint named `point` in the transaction.
+// It can later be rolled back to using RollbackTo.
+func (tx *TX) SavePoint(point string) error {
+	return tx.doSavePoint(savePointOperationCreate, point)
+}
+
+// RollbackTo rollbacks the transaction to the savepoint named `point`.
+func (tx *TX) RollbackTo(point string) error {
+	return tx.doSavePoint(savePointOperationRollback, point)
+}
+
+// ReleaseSavePoint releases the savepoint named `point` in the transaction.
+// It does nothing if the database does not support releasing savepoints.
+func (tx *TX) ReleaseSavePoint(point string) error {
+	return tx.doSavePoint(savePointOperationRelease, point)
+}
+
+// doSavePoint executes the savepoint sql of current database type for given `operation`.
+func (tx *TX) doSavePoint(operation int, point string) error {
+	if tx.isClosed {
+		return gerror.New("transaction is already closed")
+	}
+	sqlStr := tx.db.GetSavePointSql(operation, point)
+	if sqlStr == "" {
+		return nil
+	}
+	_, err := tx.Exec(sqlStr)
+	return err
+}
+
+// transactionSavePointName returns the savepoint name for current nested level.
+func (tx *TX) transactionSavePointName() string {
+	return fmt.Sprintf(`%s%d`, transactionSavePointPrefix, tx.transactionCount)
+}
+
+// Transaction wraps the nested transaction logic using function `f`.
+// It creates a savepoint before calling `f`, rollbacks to the savepoint and returns the
+// error from function `f` if it returns non-nil error, or else it releases the savepoint.
+// The outer transaction is neither committed nor rolled back by this function.
+//
+// Note that, you should not Commit or Rollback the transaction in function `f`
+// as it is automatically handled by this function.
+func (tx *TX) Transaction(f func(tx *TX) error) (err error) {
+	if err = tx.Begin(); err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			if e := recover(); e != nil {
+				err = fmt.Errorf("%v", e)
+			}
+		}
+		if err != nil {
+			if e := tx.Rollback(); e != nil {
+				err = e
+			}
+		} else {
+			if e := tx.Commit(); e != nil {
+				err = e
+			}
+		}
+	}()
+	err = f(tx)
+	return
 }
 
 // Query does query operation on transaction.
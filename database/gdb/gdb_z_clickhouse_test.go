@@ -0,0 +1,127 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/test/gtest"
+)
+
+// FakeClickhouse is the clickhouse driver running on the underlying sql driver of gdb.DriverMock,
+// which is used for testing the statements of clickhouse without server.
+type FakeClickhouse struct {
+	*gdb.DriverClickhouse
+}
+
+func (d *FakeClickhouse) New(core *gdb.Core, node *gdb.ConfigNode) (gdb.DB, error) {
+	return &FakeClickhouse{
+		&gdb.DriverClickhouse{
+			Core: core,
+		},
+	}, nil
+}
+
+func (d *FakeClickhouse) Open(config *gdb.ConfigNode) (*sql.DB, error) {
+	return sql.Open("gdb-mock", config.LinkInfo)
+}
+
+func init() {
+	gdb.Register("fake-clickhouse", &FakeClickhouse{})
+}
+
+func newFakeClickhouseDB(t *gtest.T) (gdb.DB, *gdb.Mock) {
+	mock := gdb.NewMock()
+	node := mock.ConfigNode()
+	node.Type = "fake-clickhouse"
+	gdb.AddConfigNode(mock.Name(), node)
+	db, err := gdb.New(mock.Name())
+	t.AssertNil(err)
+	return db, mock
+}
+
+func Test_Clickhouse_Model(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newFakeClickhouseDB(t)
+		mock.ExpectQuery("FROM system.columns").WithArgs("visits").WillReturnRows(
+			g.Map{"name": "id", "type": "UInt64", "default_expression": "", "comment": "", "is_in_primary_key": 1},
+			g.Map{"name": "url", "type": "String", "default_expression": "", "comment": "", "is_in_primary_key": 0},
+			g.Map{"name": "referer", "type": "Nullable(String)", "default_expression": "", "comment": "", "is_in_primary_key": 0},
+		)
+		fields, err := db.TableFields("visits")
+		t.AssertNil(err)
+		t.Assert(len(fields), 3)
+		t.Assert(fields["id"].Key, "PRI")
+		t.Assert(fields["referer"].Null, true)
+
+		mock.ExpectQuery("SELECT `url` FROM `visits` WHERE `id`=? LIMIT 1").WithArgs(1).WillReturnRows(
+			g.Map{"url": "/index"},
+		)
+		value, err := db.Model("visits").Fields("url").WherePri(1).Value()
+		t.AssertNil(err)
+		t.Assert(value, "/index")
+
+		mock.ExpectExec("ALTER TABLE `visits` UPDATE `url`=? WHERE `id`=?").WithArgs("/home", 1)
+		_, err = db.Model("visits").Data("url", "/home").WherePri(1).Update()
+		t.AssertNil(err)
+
+		mock.ExpectExec("ALTER TABLE `visits` DELETE WHERE `id` > ?").WithArgs(100)
+		_, err = db.Model("visits").WhereGT("id", 100).Delete()
+		t.AssertNil(err)
+
+		t.AssertNil(mock.ExpectationsWereMet())
+	})
+}
+
+func Test_Clickhouse_BatchInsert(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newFakeClickhouseDB(t)
+		mock.ExpectExec("INSERT INTO `visits`(`id`) VALUES(?)").Times(3)
+		r, err := db.Model("visits").Data(g.List{{"id": 1}, {"id": 2}, {"id": 3}}).Batch(2).Insert()
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 3)
+		t.AssertNil(mock.ExpectationsWereMet())
+
+		// The batches are sent in transactions of the underlying driver.
+		sqlArray := make([]string, 0)
+		for _, record := range mock.Records() {
+			if record.Type == gdb.MockTypeTx {
+				sqlArray = append(sqlArray, record.Sql)
+			}
+		}
+		t.Assert(sqlArray, g.SliceStr{"BEGIN", "COMMIT", "BEGIN", "COMMIT"})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		db, _ := newFakeClickhouseDB(t)
+		_, err := db.Model("visits").Data(g.Map{"id": 1}).Replace()
+		t.AssertNE(err, nil)
+		err = db.Transaction(func(tx *gdb.TX) error {
+			return nil
+		})
+		t.Assert(err, gdb.ErrClickhouseTxUnsupported)
+	})
+}
+
+func Test_Clickhouse_CreateTableSql(t *testing.T) {
+	type Visit struct {
+		Id      uint64 `orm:"id,primary"`
+		Url     string `orm:"url,comment:visited url"`
+		Referer *string
+	}
+	gtest.C(t, func(t *gtest.T) {
+		db, _ := newFakeClickhouseDB(t)
+		statements, err := db.CreateTableSql(Visit{}, "visits")
+		t.AssertNil(err)
+		t.Assert(statements, g.SliceStr{
+			"CREATE TABLE `visits` (\n\t`id` UInt64,\n\t`url` String COMMENT 'visited url',\n\t`referer` Nullable(String)\n) ENGINE = MergeTree() ORDER BY (`id`)",
+		})
+	})
+}
@@ -0,0 +1,601 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/test/gtest"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// insertOptionMock is the mock driver recording the insert options of DoInsert.
+type insertOptionMock struct {
+	*gdb.DriverMock
+	options []int
+}
+
+func (d *insertOptionMock) New(core *gdb.Core, node *gdb.ConfigNode) (gdb.DB, error) {
+	db, err := d.DriverMock.New(core, node)
+	if err != nil {
+		return nil, err
+	}
+	return &insertOptionMock{DriverMock: db.(*gdb.DriverMock)}, nil
+}
+
+func (d *insertOptionMock) DoInsert(link gdb.Link, table string, data interface{}, option int, batch ...int) (sql.Result, error) {
+	d.options = append(d.options, option)
+	return d.DriverMock.DoInsert(link, table, data, option, batch...)
+}
+
+func init() {
+	gdb.Register("insert-option-mock", &insertOptionMock{DriverMock: &gdb.DriverMock{}})
+}
+
+func newMockDB(t *gtest.T) (gdb.DB, *gdb.Mock) {
+	mock := gdb.NewMock()
+	gdb.AddConfigNode(mock.Name(), mock.ConfigNode())
+	db, err := gdb.New(mock.Name())
+	t.AssertNil(err)
+	mock.SetTableFields(
+		"user",
+		&gdb.TableField{Name: "id", Type: "int(10) unsigned", Key: "PRI"},
+		&gdb.TableField{Name: "passport", Type: "varchar(45)"},
+		&gdb.TableField{Name: "nickname", Type: "varchar(45)"},
+	)
+	return db, mock
+}
+
+func Test_Mock_Query(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery("SELECT * FROM `user` WHERE `id`=?").WithArgs(1).WillReturnRows(
+			g.Map{"id": 1, "passport": "user_1", "nickname": "name_1"},
+		)
+		mock.ExpectQuery("select count(1) from `user`").WillReturnRows(g.Map{"count": 10})
+
+		one, err := db.Model("user").Where("id", 1).One()
+		t.AssertNil(err)
+		t.Assert(one["passport"], "user_1")
+
+		count, err := db.Model("user").Count()
+		t.AssertNil(err)
+		t.Assert(count, 10)
+
+		t.AssertNil(mock.ExpectationsWereMet())
+
+		// Unexpected statement.
+		_, err = db.Model("user").Where("id", 2).One()
+		t.AssertNE(err, nil)
+
+		records := mock.Records()
+		t.Assert(len(records), 3)
+		t.Assert(records[0].Type, gdb.MockTypeQuery)
+		t.Assert(records[0].Args, g.Slice{1})
+		t.AssertNE(records[2].Error, nil)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery("FROM `user`").WillReturnError(errors.New("mock error"))
+		mock.ExpectQuery("FROM `user`").Times(2)
+
+		_, err := db.Model("user").All()
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), "mock error"), true)
+
+		_, err = db.Model("user").All()
+		t.AssertNil(err)
+		t.AssertNE(mock.ExpectationsWereMet(), nil)
+	})
+}
+
+func Test_Mock_Exec(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectExec("INSERT INTO `user`").WillReturnAffected(1, 100)
+		mock.ExpectExec("UPDATE `user` SET `nickname`=?").WithArgs("name_100", 100).WillReturnAffected(1)
+
+		r, err := db.Model("user").Data(g.Map{"passport": "user_100", "nonexistent": 1}).Filter().Insert()
+		t.AssertNil(err)
+		id, _ := r.LastInsertId()
+		t.Assert(id, 100)
+
+		r, err = db.Model("user").Data("nickname", "name_100").WherePri(100).Update()
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 1)
+
+		t.AssertNil(mock.ExpectationsWereMet())
+		t.Assert(mock.Records()[0].Args, g.Slice{"user_100"})
+	})
+	// Transaction.
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectExec("DELETE FROM `user`").WillReturnAffected(1)
+		err := db.Transaction(func(tx *gdb.TX) error {
+			_, err := tx.Model("user").WherePri(1).Delete()
+			return err
+		})
+		t.AssertNil(err)
+		records := mock.Records()
+		t.Assert(len(records), 3)
+		t.Assert(records[0].Sql, "BEGIN")
+		t.Assert(records[2].Sql, "COMMIT")
+	})
+	// DryRun.
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		db.SetDryRun(true)
+		_, err := db.Model("user").Data("nickname", "name").WherePri(1).Update()
+		t.AssertNil(err)
+		records := mock.Records()
+		t.Assert(len(records), 1)
+		t.Assert(records[0].DryRun, true)
+		t.Assert(records[0].Args, g.Slice{"name", 1})
+	})
+}
+
+func Test_Mock_SoftDelete_Bool(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		mock := gdb.NewMock()
+		node := mock.ConfigNode()
+		node.DeletedAt = "is_deleted"
+		gdb.AddConfigNode(mock.Name(), node)
+		db, err := gdb.New(mock.Name())
+		t.AssertNil(err)
+		// Field types of pgsql.
+		mock.SetTableFields(
+			"user",
+			&gdb.TableField{Name: "id", Type: "int4", Key: "PRI"},
+			&gdb.TableField{Name: "is_deleted", Type: "bool"},
+		)
+		mock.ExpectQuery("SELECT * FROM `user` WHERE (`id`=?) AND `is_deleted`=FALSE").WithArgs(1)
+		mock.ExpectExec("UPDATE `user` SET `is_deleted`=? WHERE (`id`=?) AND `is_deleted`=FALSE").WithArgs(true, 1)
+
+		_, err = db.Model("user").Where("id", 1).All()
+		t.AssertNil(err)
+		_, err = db.Model("user").Where("id", 1).Delete()
+		t.AssertNil(err)
+		t.AssertNil(mock.ExpectationsWereMet())
+	})
+	// The flag field is not detected without configuration.
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.SetTableFields(
+			"user",
+			&gdb.TableField{Name: "id", Type: "int4", Key: "PRI"},
+			&gdb.TableField{Name: "is_deleted", Type: "bool"},
+		)
+		mock.ExpectExec("DELETE FROM `user` WHERE `id`=?").WithArgs(1)
+
+		_, err := db.Model("user").Where("id", 1).Delete()
+		t.AssertNil(err)
+		t.AssertNil(mock.ExpectationsWereMet())
+	})
+}
+
+func Test_Mock_Cache_Tags(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery("FROM `user`").WillReturnRows(g.Map{"id": 1, "nickname": "name_1"})
+		mock.ExpectExec("UPDATE `user_detail`").WillReturnAffected(1)
+		mock.ExpectQuery("FROM `user`").WillReturnRows(g.Map{"id": 1, "nickname": "name_2"})
+
+		model := db.Model("user u").LeftJoin("user_detail ud", "ud.uid=u.id").Cache(0)
+		all, err := model.All()
+		t.AssertNil(err)
+		t.Assert(all[0]["nickname"], "name_1")
+		all, err = model.All()
+		t.AssertNil(err)
+		t.Assert(all[0]["nickname"], "name_1")
+		t.Assert(len(mock.Records()), 1)
+
+		// Writing the joined table invalidates the cache.
+		_, err = db.Model("user_detail").Data("address", "address").Where("uid", 1).Update()
+		t.AssertNil(err)
+		all, err = model.All()
+		t.AssertNil(err)
+		t.Assert(all[0]["nickname"], "name_2")
+		t.AssertNil(mock.ExpectationsWereMet())
+	})
+	// Named cache.
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery("FROM `user`").WillReturnRows(g.Map{"id": 1, "nickname": "name_1"})
+		mock.ExpectExec("DELETE FROM `user`").WillReturnAffected(1)
+		mock.ExpectQuery("FROM `user`").WillReturnRows(g.Map{"id": 2, "nickname": "name_2"})
+
+		one, err := db.Model("user").Cache(0, "user").One()
+		t.AssertNil(err)
+		t.Assert(one["id"], 1)
+		one, err = db.Model("user").Cache(0, "user").One()
+		t.AssertNil(err)
+		t.Assert(one["id"], 1)
+
+		_, err = db.Model("user").WherePri(1).Delete()
+		t.AssertNil(err)
+		one, err = db.Model("user").Cache(0, "user").One()
+		t.AssertNil(err)
+		t.Assert(one["id"], 2)
+		t.AssertNil(mock.ExpectationsWereMet())
+	})
+	// Writing in transaction invalidates the cache after the transaction ends.
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery("FROM `user`").WillReturnRows(g.Map{"id": 1, "nickname": "name_1"})
+		mock.ExpectExec("UPDATE `user`").WillReturnAffected(1).Times(2)
+		mock.ExpectQuery("FROM `user`").WillReturnRows(g.Map{"id": 1, "nickname": "name_2"})
+		mock.ExpectQuery("FROM `user`").WillReturnRows(g.Map{"id": 1, "nickname": "name_3"})
+
+		model := db.Model("user").Cache(0)
+		one, err := model.One()
+		t.AssertNil(err)
+		t.Assert(one["nickname"], "name_1")
+		err = db.Transaction(func(tx *gdb.TX) error {
+			if _, err := tx.Model("user").Data("nickname", "name_2").WherePri(1).Update(); err != nil {
+				return err
+			}
+			// The uncommitted writing does not invalidate the cache.
+			one, err := model.One()
+			t.AssertNil(err)
+			t.Assert(one["nickname"], "name_1")
+			return nil
+		})
+		t.AssertNil(err)
+		one, err = model.One()
+		t.AssertNil(err)
+		t.Assert(one["nickname"], "name_2")
+
+		err = db.Transaction(func(tx *gdb.TX) error {
+			if _, err := tx.Model("user").Data("nickname", "name_3").WherePri(1).Update(); err != nil {
+				return err
+			}
+			return errors.New("rollback")
+		})
+		t.AssertNE(err, nil)
+		one, err = model.One()
+		t.AssertNil(err)
+		t.Assert(one["nickname"], "name_3")
+		t.AssertNil(mock.ExpectationsWereMet())
+	})
+}
+
+func Test_Mock_Stats(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery("SELECT * FROM `user`").WillReturnRows(g.Map{"id": 1}).Times(3)
+		_, err := db.Model("user").Where("id", 1).One()
+		t.AssertNil(err)
+		t.Assert(len(db.Stats().Statements), 0)
+
+		db.GetConfig().StatsEnabled = true
+		for i := 1; i <= 2; i++ {
+			_, err = db.Model("user").Where("id", i).One()
+			t.AssertNil(err)
+		}
+		statements := db.Stats().Statements
+		t.Assert(len(statements), 1)
+		t.Assert(statements[0].Sql, "SELECT * FROM `user` WHERE `id`=? LIMIT ?")
+		t.Assert(statements[0].Count, 2)
+		t.Assert(statements[0].TotalLatency > 0, true)
+		t.Assert(statements[0].TotalLatency >= statements[0].MaxLatency, true)
+	})
+}
+
+func Test_Mock_Update_Version_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.SetTableFields(
+			"item",
+			&gdb.TableField{Name: "id", Type: "int(10) unsigned", Key: "PRI"},
+			&gdb.TableField{Name: "old_version", Type: "int(10)"},
+			&gdb.TableField{Name: "version", Type: "int(10)"},
+		)
+		mock.ExpectExec("UPDATE `item`").WillReturnAffected(1).Times(3)
+		_, err := db.Model("item").Data("old_version=1").WherePri(1).Update()
+		t.AssertNil(err)
+		_, err = db.Model("item").Data("old_version=1, `version` = 10").WherePri(1).Update()
+		t.AssertNil(err)
+		_, err = db.Model("item").Data("version=version+2").WherePri(1).Update()
+		t.AssertNil(err)
+
+		records := mock.Records()
+		t.Assert(len(records), 3)
+		t.Assert(records[0].Sql, "UPDATE `item` SET old_version=1,`version`=`version`+1 WHERE `id`=?")
+		t.Assert(records[1].Sql, "UPDATE `item` SET old_version=1, `version` = 10 WHERE `id`=?")
+		t.Assert(records[2].Sql, "UPDATE `item` SET version=version+2 WHERE `id`=?")
+	})
+}
+
+func Test_Mock_BatchUpdate_Version(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.SetTableFields(
+			"item",
+			&gdb.TableField{Name: "id", Type: "int(10) unsigned", Key: "PRI"},
+			&gdb.TableField{Name: "name", Type: "varchar(45)"},
+			&gdb.TableField{Name: "version", Type: "int(10)"},
+		)
+		_, err := db.Model("item").BatchUpdate(g.List{
+			{"id": 1, "name": "name_1", "version": 1},
+			{"id": 2, "name": "name_2"},
+		})
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), `version field "version"`), true)
+		t.Assert(len(mock.Records()), 0)
+	})
+}
+
+func Test_Mock_ReadYourWrites(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			master = gdb.NewMock()
+			slave  = gdb.NewMock()
+			group  = master.Name() + "_group"
+		)
+		masterNode, slaveNode := master.ConfigNode(), slave.ConfigNode()
+		masterNode.ReadYourWrites = time.Minute
+		slaveNode.Role = "slave"
+		gdb.SetConfigGroup(group, gdb.ConfigGroup{masterNode, slaveNode})
+		db, err := gdb.New(group)
+		t.AssertNil(err)
+		master.ExpectExec("UPDATE `user`").WillReturnAffected(1)
+		master.ExpectQuery("SELECT").Times(1)
+		slave.ExpectQuery("SELECT").Times(2)
+
+		ctx := gdb.WithReadYourWrites(context.Background())
+		_, err = db.Ctx(ctx).Query("SELECT 1")
+		t.AssertNil(err)
+		_, err = db.Ctx(ctx).Exec("UPDATE `user` SET `nickname`='name'")
+		t.AssertNil(err)
+		// The derived context shares the marker.
+		derivedCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		_, err = db.Ctx(derivedCtx).Query("SELECT 2")
+		t.AssertNil(err)
+		// The context without marker.
+		_, err = db.Ctx(context.Background()).Query("SELECT 3")
+		t.AssertNil(err)
+
+		t.AssertNil(master.ExpectationsWereMet())
+		t.AssertNil(slave.ExpectationsWereMet())
+		t.Assert(master.Records()[1].Sql, "SELECT 2")
+	})
+	// The writing of prepared statement.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			master = gdb.NewMock()
+			slave  = gdb.NewMock()
+			group  = master.Name() + "_group"
+		)
+		masterNode, slaveNode := master.ConfigNode(), slave.ConfigNode()
+		masterNode.ReadYourWrites = time.Minute
+		slaveNode.Role = "slave"
+		gdb.SetConfigGroup(group, gdb.ConfigGroup{masterNode, slaveNode})
+		db, err := gdb.New(group)
+		t.AssertNil(err)
+		master.ExpectExec("UPDATE `user`").WillReturnAffected(1)
+		master.ExpectQuery("SELECT").Times(1)
+		slave.ExpectQuery("SELECT").Times(1)
+
+		ctx := gdb.WithReadYourWrites(context.Background())
+		stmt, err := db.Prepare("UPDATE `user` SET `nickname`=?", true)
+		t.AssertNil(err)
+		_, err = db.Ctx(ctx).Query("SELECT 1")
+		t.AssertNil(err)
+		_, err = stmt.ExecContext(ctx, "name")
+		t.AssertNil(err)
+		_, err = db.Ctx(ctx).Query("SELECT 2")
+		t.AssertNil(err)
+
+		t.AssertNil(master.ExpectationsWereMet())
+		t.AssertNil(slave.ExpectationsWereMet())
+		t.Assert(master.Records()[1].Sql, "SELECT 2")
+	})
+}
+
+func Test_Mock_Group(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			group1 = gdb.NewMock().Name() + "_group1"
+			group2 = gdb.NewMock().Name() + "_group2"
+			group3 = gdb.NewMock().Name() + "_group3"
+		)
+		// Groups of the same database name on different hosts.
+		gdb.AddConfigNode(group1, gdb.ConfigNode{Type: "mock", Host: "127.0.0.1", Name: "test"})
+		gdb.AddConfigNode(group2, gdb.ConfigNode{Type: "mock", Host: "127.0.0.2", Name: "test"})
+		// Group of empty database name.
+		gdb.AddConfigNode(group3, gdb.ConfigNode{Type: "mock"})
+		for _, group := range []string{group1, group2, group3} {
+			db, err := gdb.New(group)
+			t.AssertNil(err)
+			mock := db.(*gdb.DriverMock).Mock()
+			t.Assert(mock.Name(), group)
+			t.Assert(gdb.GetMock(group), mock)
+			mock.ExpectQuery("SELECT").WillReturnRows(g.Map{"name": group})
+
+			value, err := db.GetValue("SELECT `name` FROM `user`")
+			t.AssertNil(err)
+			t.Assert(value, group)
+			t.Assert(len(mock.Records()), 1)
+		}
+	})
+}
+
+func Test_Mock_DoInsert_Option(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		mock := gdb.NewMock()
+		node := mock.ConfigNode()
+		node.Type = "insert-option-mock"
+		gdb.AddConfigNode(mock.Name(), node)
+		db, err := gdb.New(mock.Name())
+		t.AssertNil(err)
+		mock.SetTableFields(
+			"user",
+			&gdb.TableField{Name: "id", Type: "int(10) unsigned", Key: "PRI"},
+			&gdb.TableField{Name: "nickname", Type: "varchar(45)"},
+		)
+		mock.ExpectExec("INSERT").WillReturnAffected(1).Times(5)
+
+		// The overwritten DoInsert is called by Model, also for Save without upsert options.
+		_, err = db.Model("user").Data(g.Map{"id": 1, "nickname": "name_1"}).Insert()
+		t.AssertNil(err)
+		_, err = db.Model("user").Data(g.Map{"id": 1, "nickname": "name_1"}).InsertIgnore()
+		t.AssertNil(err)
+		_, err = db.Model("user").Data(g.Map{"nickname": "name_1", "id": 1}).Save()
+		t.AssertNil(err)
+		t.Assert(db.(*insertOptionMock).options, []int{0, 3, 2})
+		t.Assert(
+			mock.Records()[2].Sql,
+			"INSERT INTO `user`(`id`,`nickname`) VALUES(?,?) ON DUPLICATE KEY UPDATE `id`=VALUES(`id`),`nickname`=VALUES(`nickname`)",
+		)
+
+		// The upsert options are passed by DoInsertWithOption.
+		_, err = db.Model("user").Data(g.Map{"id": 1, "nickname": "name_1"}).OnDuplicate("nickname").Save()
+		t.AssertNil(err)
+		t.Assert(len(db.(*insertOptionMock).options), 3)
+		t.Assert(
+			mock.Records()[3].Sql,
+			"INSERT INTO `user`(`id`,`nickname`) VALUES(?,?) ON DUPLICATE KEY UPDATE `nickname`=VALUES(`nickname`)",
+		)
+		_, err = db.Model("user").Data(g.Map{"id": 1, "nickname": "name_1"}).OnDuplicate(g.Map{
+			"nickname": "nickname",
+			"id":       "id",
+		}).Save()
+		t.AssertNil(err)
+		t.Assert(
+			mock.Records()[4].Sql,
+			"INSERT INTO `user`(`id`,`nickname`) VALUES(?,?) ON DUPLICATE KEY UPDATE `id`=VALUES(`id`),`nickname`=VALUES(`nickname`)",
+		)
+	})
+}
+
+func Test_Mock_Invalid_Column_SubQuery(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		subModel := db.Model("user").Fields("id").WhereGT("id;drop", 1)
+
+		_, err := db.Model("user").Where("id IN(?)", subModel).All()
+		t.AssertNE(err, nil)
+		_, err = db.Model("user").Where(g.Map{"id": subModel}).All()
+		t.AssertNE(err, nil)
+		_, err = db.Model("user").Fields("id, (?) AS total", subModel).All()
+		t.AssertNE(err, nil)
+		_, err = db.Model("user").Table(subModel, "u").All()
+		t.AssertNE(err, nil)
+		_, err = db.Model("user").Union(subModel).All()
+		t.AssertNE(err, nil)
+		_, err = db.GetAll("SELECT * FROM `user` WHERE id IN(?)", subModel)
+		t.AssertNE(err, nil)
+		_, err = db.Model("user").Data("nickname", "name").Where("id IN(?)", subModel).Update()
+		t.AssertNE(err, nil)
+		t.Assert(len(mock.Records()), 0)
+	})
+}
+
+func Test_Mock_Invalid_Column_BatchUpdate(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		_, err := db.Model("user").WhereGT("id;drop", 1).BatchUpdate(g.List{
+			{"id": 1, "nickname": "name_1"},
+		})
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), `invalid column name "id;drop"`), true)
+		t.Assert(len(mock.Records()), 0)
+	})
+}
+
+func Test_Mock_Migrator_LockLost(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.SetTableFields(
+			"gf_migrations",
+			&gdb.TableField{Name: "version", Type: "bigint", Key: "PRI"},
+			&gdb.TableField{Name: "name", Type: "varchar(255)"},
+			&gdb.TableField{Name: "applied_at", Type: "varchar(64)"},
+		)
+		mock.SetTableFields(
+			"gf_migrations_lock",
+			&gdb.TableField{Name: "id", Type: "int", Key: "PRI"},
+			&gdb.TableField{Name: "owner", Type: "varchar(64)"},
+			&gdb.TableField{Name: "locked_at", Type: "bigint"},
+		)
+		mock.ExpectExec("INSERT INTO `gf_migrations_lock`").WillReturnAffected(1)
+		mock.ExpectQuery("FROM `gf_migrations`").WillReturnRows()
+		// The lock is removed as a stale lock by others.
+		mock.ExpectExec("UPDATE `gf_migrations_lock`").WillReturnAffected(0)
+		mock.ExpectQuery("SELECT COUNT(1) FROM `gf_migrations_lock`").WillReturnRows(g.Map{"total": 0})
+		mock.ExpectExec("DELETE FROM `gf_migrations_lock`").WillReturnAffected(0)
+
+		migrator := gdb.NewMigrator(db)
+		migrator.SetLockTimeout(150 * time.Millisecond)
+		err := migrator.Register(&gdb.Migration{
+			Version: 1,
+			Name:    "lock_lost",
+			Up: func(tx *gdb.TX) error {
+				time.Sleep(500 * time.Millisecond)
+				return nil
+			},
+		})
+		t.AssertNil(err)
+
+		migrations, err := migrator.Up()
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), "migration lock is lost"), true)
+		t.Assert(len(migrations), 0)
+		// The migration is not recorded as the transaction is rolled back.
+		for _, record := range mock.Records() {
+			t.Assert(gstr.Contains(record.Sql, "INSERT INTO `gf_migrations`("), false)
+		}
+	})
+}
+
+func Test_Mock_Sharding(t *testing.T) {
+	rule := &gdb.ShardingModulo{Key: "id", Count: 4, Prefix: "user_"}
+	// The negative and positive values of the same shard.
+	gtest.C(t, func(t *gtest.T) {
+		shards, err := rule.Shards(-1, 1)
+		t.AssertNil(err)
+		t.Assert(len(shards), 2)
+		t.Assert(shards[0].Table, "user_01")
+		t.Assert(shards[1].Table, "user_00")
+	})
+	// The aggregate fields across multiple shards.
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		_, err := db.Model("user").Sharding(rule).Value("SUM(id)")
+		t.AssertNE(err, nil)
+		_, err = db.Model("user").Sharding(rule).Array("MAX(id)")
+		t.AssertNE(err, nil)
+		t.Assert(len(mock.Records()), 0)
+
+		mock.ExpectQuery("SELECT SUM(id) FROM `user_03`").WillReturnRows(g.Map{"id": 3})
+		value, err := db.Model("user").Sharding(rule).Where("id", 3).Value("SUM(id)")
+		t.AssertNil(err)
+		t.Assert(value, 3)
+	})
+	// The custom cache name of each shard.
+	gtest.C(t, func(t *gtest.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery("FROM `user_01`").WillReturnRows(g.Map{"id": 1})
+		mock.ExpectQuery("FROM `user_02`").WillReturnRows(g.Map{"id": 2})
+		cacheName := mock.Name() + "_sharding"
+		one, err := db.Model("user").Sharding(rule).Cache(time.Minute, cacheName).Where("id", 1).One()
+		t.AssertNil(err)
+		t.Assert(one["id"], 1)
+		one, err = db.Model("user").Sharding(rule).Cache(time.Minute, cacheName).Where("id", 2).One()
+		t.AssertNil(err)
+		t.Assert(one["id"], 2)
+		t.Assert(len(mock.Records()), 2)
+	})
+}
@@ -8,6 +8,7 @@ package gdb_test
 
 import (
 	"fmt"
+	"github.com/gogf/gf/database/gdb"
 	"github.com/gogf/gf/frame/g"
 	"github.com/gogf/gf/test/gtest"
 	"github.com/gogf/gf/util/gmeta"
@@ -639,3 +640,163 @@ PRIMARY KEY (id)
 		t.Assert(users[1].UserScores[4].Score, 5)
 	})
 }
+
+func Test_Table_Relation_With_Nested_Option(t *testing.T) {
+	var (
+		tableUser        = "user"
+		tableUserDetail  = "user_detail"
+		tableUserAddress = "user_address"
+		tableUserScores  = "user_scores"
+	)
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %s (
+id int(10) unsigned NOT NULL AUTO_INCREMENT,
+name varchar(45) NOT NULL,
+PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+ `, tableUser)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(tableUser)
+
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %s (
+uid int(10) unsigned NOT NULL AUTO_INCREMENT,
+address varchar(45) NOT NULL,
+PRIMARY KEY (uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+ `, tableUserDetail)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(tableUserDetail)
+
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %s (
+uid int(10) unsigned NOT NULL AUTO_INCREMENT,
+city varchar(45) NOT NULL,
+PRIMARY KEY (uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+ `, tableUserAddress)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(tableUserAddress)
+
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %s (
+id int(10) unsigned NOT NULL AUTO_INCREMENT,
+uid int(10) unsigned NOT NULL,
+score int(10) unsigned NOT NULL,
+PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+ `, tableUserScores)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(tableUserScores)
+
+	type UserAddress struct {
+		gmeta.Meta `orm:"table:user_address"`
+		Uid        int    `json:"uid"`
+		City       string `json:"city"`
+	}
+
+	type UserDetail struct {
+		gmeta.Meta  `orm:"table:user_detail"`
+		Uid         int          `json:"uid"`
+		Address     string       `json:"address"`
+		UserAddress *UserAddress `orm:"with:uid=uid"`
+	}
+
+	type UserScores struct {
+		gmeta.Meta `orm:"table:user_scores"`
+		Id         int `json:"id"`
+		Uid        int `json:"uid"`
+		Score      int `json:"score"`
+	}
+
+	type User struct {
+		gmeta.Meta `orm:"table:user"`
+		Id         int           `json:"id"`
+		Name       string        `json:"name"`
+		UserDetail *UserDetail   `orm:"with:uid=id"`
+		UserScores []*UserScores `orm:"with:uid=id"`
+	}
+
+	// Initialize the data.
+	var err error
+	for i := 1; i <= 5; i++ {
+		// User.
+		_, err = db.Insert(tableUser, g.Map{
+			"id":   i,
+			"name": fmt.Sprintf(`name_%d`, i),
+		})
+		gtest.Assert(err, nil)
+		// Detail.
+		_, err = db.Insert(tableUserDetail, g.Map{
+			"uid":     i,
+			"address": fmt.Sprintf(`address_%d`, i),
+		})
+		gtest.Assert(err, nil)
+		// Address.
+		_, err = db.Insert(tableUserAddress, g.Map{
+			"uid":  i,
+			"city": fmt.Sprintf(`city_%d`, i),
+		})
+		gtest.Assert(err, nil)
+		// Scores.
+		for j := 1; j <= 5; j++ {
+			_, err = db.Insert(tableUserScores, g.Map{
+				"uid":   i,
+				"score": j,
+			})
+			gtest.Assert(err, nil)
+		}
+	}
+	// Nested relation.
+	gtest.C(t, func(t *gtest.T) {
+		var user *User
+		err := db.With(User{}).
+			With(User{}.UserDetail).
+			With(UserDetail{}.UserAddress).
+			Where("id", 3).
+			Scan(&user)
+		t.AssertNil(err)
+		t.Assert(user.Id, 3)
+		t.AssertNE(user.UserDetail, nil)
+		t.Assert(user.UserDetail.Address, `address_3`)
+		t.AssertNE(user.UserDetail.UserAddress, nil)
+		t.Assert(user.UserDetail.UserAddress.City, `city_3`)
+		t.Assert(len(user.UserScores), 0)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var users []*User
+		err := db.Model(tableUser).WithAll().Where("id", []int{3, 4}).Order("id asc").Scan(&users)
+		t.AssertNil(err)
+		t.Assert(len(users), 2)
+		t.Assert(users[0].UserDetail.UserAddress.City, `city_3`)
+		t.Assert(users[1].UserDetail.UserAddress.City, `city_4`)
+		t.Assert(len(users[0].UserScores), 5)
+		t.Assert(len(users[1].UserScores), 5)
+	})
+	// Relation option.
+	gtest.C(t, func(t *gtest.T) {
+		var users []User
+		err := db.With(User{}).
+			With(User{}.UserScores, func(m *gdb.Model) *gdb.Model {
+				return m.Fields("score").Where("score>?", 1).Order("score desc").Limit(2)
+			}).
+			Where("id", []int{3, 4}).
+			Order("id asc").
+			Scan(&users)
+		t.AssertNil(err)
+		t.Assert(len(users), 2)
+		t.Assert(users[0].UserDetail, nil)
+		t.Assert(len(users[0].UserScores), 2)
+		t.Assert(users[0].UserScores[0].Uid, 3)
+		t.Assert(users[0].UserScores[0].Score, 5)
+		t.Assert(users[0].UserScores[0].Id, 0)
+		t.Assert(users[0].UserScores[1].Score, 4)
+		t.Assert(len(users[1].UserScores), 2)
+		t.Assert(users[1].UserScores[0].Uid, 4)
+		t.Assert(users[1].UserScores[1].Score, 4)
+	})
+}
@@ -0,0 +1,156 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/test/gtest"
+)
+
+func Test_DDL_ParseTableColumns(t *testing.T) {
+	type Base struct {
+		CreatedAt *gtime.Time `orm:"created_at"`
+	}
+	type User struct {
+		Id       uint    `orm:"id,primary,autoincrement,comment:User ID"`
+		Passport string  `orm:"passport,size:45,unique"`
+		Amount   float64 `orm:"amount,type:decimal(10,2),default:0"`
+		Score    int     `orm:"score,index:idx_score_age"`
+		Age      int     `orm:"age,index:idx_score_age"`
+		NickName string
+		Base
+		Ignored string `orm:"-"`
+		// Relation attributes.
+		Detail *User   `orm:"with:uid=id"`
+		Scores []*User `orm:"scores, with:uid=id"`
+	}
+	gtest.C(t, func(t *gtest.T) {
+		columns, err := gdb.ParseTableColumns(&User{}, "user")
+		t.AssertNil(err)
+		t.Assert(len(columns), 7)
+		t.Assert(columns[0].Name, "id")
+		t.Assert(columns[0].Primary, true)
+		t.Assert(columns[0].AutoIncrement, true)
+		t.Assert(columns[0].Null, false)
+		t.Assert(columns[0].Comment, "User ID")
+		t.Assert(columns[1].Size, 45)
+		t.Assert(columns[1].Unique, "uk_user_passport")
+		t.Assert(columns[2].Type, "decimal(10,2)")
+		t.Assert(columns[2].Default, "0")
+		t.Assert(columns[3].Index, "idx_score_age")
+		t.Assert(columns[4].Index, "idx_score_age")
+		t.Assert(columns[5].Name, "nick_name")
+		t.Assert(columns[6].Name, "created_at")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		type Invalid struct {
+			Id int `orm:"id,unknown"`
+		}
+		_, err := gdb.ParseTableColumns(Invalid{}, "user")
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_DDL_CreateTableSql(t *testing.T) {
+	type User struct {
+		Id       uint    `orm:"id,primary,autoincrement,comment:User ID"`
+		Passport string  `orm:"passport,size:45,unique"`
+		Nickname string  `orm:"nickname,default:guest"`
+		Amount   float64 `orm:"amount,type:decimal(10,2),default:0"`
+		Score    int     `orm:"score,index:idx_score_age"`
+		Age      int8    `orm:"age,index:idx_score_age"`
+		Detail   *struct {
+			Uid int
+		} `orm:"with:uid=id"`
+	}
+	table := fmt.Sprintf(`%s_%d`, TableName, gtime.TimestampNano())
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		statements, err := db.CreateTableSql(User{}, table)
+		t.AssertNil(err)
+		t.Assert(len(statements), 3)
+		t.Assert(statements[0], fmt.Sprintf(
+			"CREATE TABLE `%s` (\n\t%s\n)", table,
+			"`id` bigint unsigned AUTO_INCREMENT NOT NULL COMMENT 'User ID',\n\t"+
+				"`passport` varchar(45),\n\t"+
+				"`nickname` varchar(255) DEFAULT 'guest',\n\t"+
+				"`amount` decimal(10,2) DEFAULT 0,\n\t"+
+				"`score` bigint,\n\t"+
+				"`age` tinyint,\n\t"+
+				"PRIMARY KEY (`id`)",
+		))
+		t.Assert(statements[1], fmt.Sprintf(
+			"CREATE UNIQUE INDEX `uk_%s_passport` ON `%s` (`passport`)", table, table,
+		))
+		t.Assert(statements[2], fmt.Sprintf(
+			"CREATE INDEX `idx_score_age` ON `%s` (`score`,`age`)", table,
+		))
+		for _, statement := range statements {
+			_, err = db.Exec(statement)
+			t.AssertNil(err)
+		}
+		_, err = db.Model(table).Data(g.Map{"passport": "john"}).Insert()
+		t.AssertNil(err)
+		one, err := db.Model(table).One()
+		t.AssertNil(err)
+		t.Assert(one["id"], 1)
+		t.Assert(one["nickname"], "guest")
+		t.Assert(one["amount"], 0)
+	})
+}
+
+func Test_DDL_DiffTableSql(t *testing.T) {
+	type User struct {
+		Id       int    `orm:"id,primary"`
+		Passport string `orm:"passport"`
+		Password string `orm:"password"`
+		NickName string `orm:"nickname"`
+		Score    int    `orm:"score,default:100"`
+		Level    int    `orm:"level,index"`
+	}
+	table := fmt.Sprintf(`%s_%d`, TableName, gtime.TimestampNano())
+	gtest.C(t, func(t *gtest.T) {
+		statements, err := db.DiffTableSql(User{}, table)
+		t.AssertNil(err)
+		t.Assert(len(statements), 2)
+		t.Assert(statements[0][0:12], "CREATE TABLE")
+	})
+
+	createTable(table)
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		statements, err := db.DiffTableSql(User{}, table)
+		t.AssertNil(err)
+		t.Assert(statements, g.Slice{
+			fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `score` bigint DEFAULT 100", table),
+			fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `level` bigint", table),
+			fmt.Sprintf("CREATE INDEX `idx_%s_level` ON `%s` (`level`)", table, table),
+		})
+		for _, statement := range statements {
+			_, err = db.Exec(statement)
+			t.AssertNil(err)
+		}
+		_, err = db.Model(table).Data(g.Map{
+			"id":          1,
+			"passport":    "john",
+			"password":    "pass",
+			"nickname":    "name",
+			"create_time": CreateTime,
+		}).Insert()
+		t.AssertNil(err)
+		value, err := db.Model(table).Fields("score").Where("id", 1).Value()
+		t.AssertNil(err)
+		t.Assert(value.Int(), 100)
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/encoding/gjson"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/test/gtest"
+	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gconv"
+)
+
+func Test_Model_FieldType_Json(t *testing.T) {
+	table := "field_type_json_" + gtime.TimestampNanoStr()
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE %s (
+  id     int(11) NOT NULL,
+  info   json DEFAULT NULL,
+  tags   json DEFAULT NULL,
+  extra  json DEFAULT NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(table)
+
+	type Info struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	type Tag struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type Entity struct {
+		Id    int
+		Info  *Info
+		Tags  []Tag
+		Extra *gjson.Json
+	}
+
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data(g.Map{
+			"id":    1,
+			"info":  Info{Name: "john", Age: 18},
+			"tags":  []Tag{{1, "a"}, {2, "b"}},
+			"extra": g.Map{"score": 9007199254740993},
+		}).Insert()
+		t.AssertNil(err)
+
+		var entity *Entity
+		t.AssertNil(db.Model(table).WherePri(1).Scan(&entity))
+		t.Assert(entity.Info, &Info{Name: "john", Age: 18})
+		t.Assert(entity.Tags, []Tag{{1, "a"}, {2, "b"}})
+		t.Assert(entity.Extra.GetInt64("score"), int64(9007199254740993))
+
+		one, err := db.Model(table).WherePri(1).One()
+		t.AssertNil(err)
+		t.Assert(one["info"].Map()["name"], "john")
+		t.Assert(one["tags"].Maps()[1]["name"], "b")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data(g.Map{
+			"info": gjson.New(g.Map{"name": "smith"}),
+		}).WherePri(1).Update()
+		t.AssertNil(err)
+
+		var entity *Entity
+		t.AssertNil(db.Model(table).WherePri(1).Scan(&entity))
+		t.Assert(entity.Info, &Info{Name: "smith"})
+	})
+}
+
+func Test_Model_FieldType_Register(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gdb.RegisterFieldType(
+		"varchar",
+		func(fieldValue interface{}, fieldType string) (interface{}, error) {
+			return gstr.Split(gconv.String(fieldValue), ","), nil
+		},
+		func(value interface{}, fieldType string) (interface{}, error) {
+			if array, ok := value.([]string); ok {
+				return gstr.Join(array, ","), nil
+			}
+			return value, nil
+		},
+	)
+	defer gdb.RegisterFieldType("varchar", nil, nil)
+
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data(g.Map{
+			"id":       1,
+			"passport": []string{"a", "b", "c"},
+			"password": "pass_1",
+			"nickname": "name_1",
+		}).Insert()
+		t.AssertNil(err)
+
+		one, err := db.Model(table).Fields("passport").WherePri(1).One()
+		t.AssertNil(err)
+		t.Assert(one["passport"].Strings(), []string{"a", "b", "c"})
+	})
+}
@@ -7,6 +7,7 @@
 package gdb
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-sql-driver/mysql"
 	"github.com/gogf/gf/container/gvar"
@@ -14,6 +15,7 @@ import (
 	"github.com/gogf/gf/os/gtime"
 	"github.com/gogf/gf/test/gtest"
 	"testing"
+	"time"
 )
 
 const (
@@ -314,6 +316,176 @@ func Test_isSubQuery(t *testing.T) {
 	})
 }
 
+func Test_splitSqlStatements(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(splitSqlStatements(""), []string{})
+		t.Assert(splitSqlStatements("SELECT 1"), []string{"SELECT 1"})
+		t.Assert(splitSqlStatements("SELECT 1;\n SELECT 2; ;"), []string{"SELECT 1", "SELECT 2"})
+		t.Assert(
+			splitSqlStatements("INSERT INTO `a;b` VALUES('x;y', \"z;\");DELETE FROM t"),
+			[]string{"INSERT INTO `a;b` VALUES('x;y', \"z;\")", "DELETE FROM t"},
+		)
+	})
+}
+
+func Test_getTableNamesFromSql(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(getTableNamesFromSql("SELECT * FROM `user` WHERE id=?", db), []string{"user"})
+		t.Assert(getTableNamesFromSql("FROM `user` AS u", db), []string{"user"})
+		t.Assert(
+			getTableNamesFromSql("SELECT * FROM `user` u LEFT JOIN `user_detail` ud ON (ud.uid=u.uid)", db),
+			[]string{"user", "user_detail"},
+		)
+		t.Assert(
+			getTableNamesFromSql("SELECT * FROM `user` u,test.`user_detail` ud WHERE ud.uid=u.uid", db),
+			[]string{"user", "user_detail"},
+		)
+		t.Assert(
+			getTableNamesFromSql("SELECT * FROM ((SELECT * FROM `a`) UNION (SELECT * FROM `b` WHERE id IN (SELECT id FROM c)))", db),
+			[]string{"a", "b", "c"},
+		)
+	})
+}
+
+func Test_Balancer(t *testing.T) {
+	nodes := []*BalancerNode{
+		{ConfigNode: &ConfigNode{Host: "127.0.0.1", Weight: 3}},
+		{ConfigNode: &ConfigNode{Host: "127.0.0.2", Weight: 1}},
+	}
+	gtest.C(t, func(t *gtest.T) {
+		balancer := &balancerRoundRobin{weights: make(map[string]int)}
+		hosts := make([]string, 0)
+		for i := 0; i < 8; i++ {
+			hosts = append(hosts, balancer.Pick("test", true, nodes).Host)
+		}
+		t.Assert(hosts, []string{
+			"127.0.0.1", "127.0.0.1", "127.0.0.2", "127.0.0.1",
+			"127.0.0.1", "127.0.0.1", "127.0.0.2", "127.0.0.1",
+		})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		balancer := &balancerLeastConn{}
+		nodes[0].Stats.InUse = 3
+		nodes[1].Stats.InUse = 2
+		t.Assert(balancer.Pick("test", true, nodes).Host, "127.0.0.1")
+		nodes[0].Stats.InUse = 7
+		t.Assert(balancer.Pick("test", true, nodes).Host, "127.0.0.2")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		balancer, err := getBalancer("")
+		t.AssertNil(err)
+		t.AssertNE(balancer.Pick("test", true, nodes), nil)
+		_, err = getBalancer("none")
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Core_pickConfigNode(t *testing.T) {
+	group := "test_pick_config_node"
+	node := configNode
+	node.Balancer = BalancerRoundRobin
+	node.ReadYourWrites = time.Minute
+	slave1, slave2 := node, node
+	slave1.Role, slave1.Host = "slave", "127.0.0.2"
+	slave2.Role, slave2.Host = "slave", "127.0.0.3"
+	SetConfigGroup(group, ConfigGroup{node, slave1, slave2})
+	defer func() {
+		configs.Lock()
+		delete(configs.config, group)
+		configs.Unlock()
+	}()
+	groupDb, err := New(group)
+	gtest.AssertNil(err)
+	core := groupDb.(*DriverMysql).Core
+
+	gtest.C(t, func(t *gtest.T) {
+		picked, err := core.pickConfigNode(true, "")
+		t.AssertNil(err)
+		t.Assert(picked.Host, node.Host)
+		picked, err = core.pickConfigNode(false, "test")
+		t.AssertNil(err)
+		t.Assert(picked.Name, "test")
+		t.AssertIN(picked.Host, []string{slave1.Host, slave2.Host})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		unhealthyNodes.Add(slave1.String())
+		defer unhealthyNodes.Remove(slave1.String())
+		for i := 0; i < 3; i++ {
+			picked, err := core.pickConfigNode(false, "")
+			t.AssertNil(err)
+			t.Assert(picked.Host, slave2.Host)
+		}
+		// All slave nodes are unhealthy.
+		unhealthyNodes.Add(slave2.String())
+		defer unhealthyNodes.Remove(slave2.String())
+		picked, err := core.pickConfigNode(false, "")
+		t.AssertNil(err)
+		t.Assert(picked.Host, node.Host)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		ctx1 := WithReadYourWrites(context.Background())
+		ctx2 := WithReadYourWrites(context.Background())
+		t.Assert(WithReadYourWrites(ctx1), ctx1)
+		core1 := groupDb.Ctx(ctx1).(*DriverMysql).Core
+		core2 := groupDb.Ctx(ctx2).(*DriverMysql).Core
+		t.Assert(core1.isWritten(), false)
+		core1.markWritten(ctx1)
+		t.Assert(core1.isWritten(), true)
+		t.Assert(core2.isWritten(), false)
+		// Derived context shares the marker.
+		derivedCtx := context.WithValue(ctx1, "id", 1)
+		t.Assert(groupDb.Ctx(derivedCtx).(*DriverMysql).Core.isWritten(), true)
+		// Context without marker.
+		core3 := groupDb.Ctx(context.Background()).(*DriverMysql).Core
+		core3.markWritten(context.Background())
+		t.Assert(core3.isWritten(), false)
+	})
+}
+
+func Test_Core_convertFieldValueToLocalValue(t *testing.T) {
+	core := db.(*DriverMysql).Core
+	gtest.C(t, func(t *gtest.T) {
+		value, err := core.convertFieldValueToLocalValue([]byte(`{"id":1,"tags":["a","b"]}`), "JSON")
+		t.AssertNil(err)
+		t.Assert(gvar.New(value).Map()["id"], 1)
+		t.Assert(gvar.New(value).Map()["tags"], []string{"a", "b"})
+
+		value, err = core.convertFieldValueToLocalValue([]byte(`9007199254740993`), "JSONB")
+		t.AssertNil(err)
+		t.Assert(gvar.New(value).Int64(), int64(9007199254740993))
+
+		value, err = core.convertFieldValueToLocalValue([]byte(`12.50`), "DECIMAL(10,2)")
+		t.AssertNil(err)
+		t.Assert(value, 12.5)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		RegisterFieldType("DECIMAL", func(fieldValue interface{}, fieldType string) (interface{}, error) {
+			return "decimal:" + gvar.New(fieldValue).String(), nil
+		}, nil)
+		defer RegisterFieldType("decimal", nil, nil)
+		value, err := core.convertFieldValueToLocalValue([]byte(`12.50`), "DECIMAL(10,2)")
+		t.AssertNil(err)
+		t.Assert(value, "decimal:12.50")
+	})
+}
+
+func Test_encodeFieldValue(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		value, err := encodeFieldValue(map[string]interface{}{"id": 1}, "json")
+		t.AssertNil(err)
+		t.Assert(value, `{"id":1}`)
+		value, err = encodeFieldValue(`{"id":1}`, "json")
+		t.AssertNil(err)
+		t.Assert(value, `{"id":1}`)
+		value, err = encodeFieldValue(map[string]interface{}{"id": 1}, "text")
+		t.AssertNil(err)
+		t.Assert(value, map[string]interface{}{"id": 1})
+		value, err = encodeFieldValue(Raw("NULL"), "json")
+		t.AssertNil(err)
+		t.Assert(value, Raw("NULL"))
+	})
+}
+
 func TestResult_Structs1(t *testing.T) {
 	type A struct {
 		Id int `orm:"id"`
@@ -656,3 +828,29 @@ func Test_ScanList_NoRecreate_SliceAttribute_Struct(t *testing.T) {
 		t.Assert(len(s[1].Many), 0)
 	})
 }
+
+func Test_normalizeSqlForStats(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(
+			normalizeSqlForStats("SELECT * FROM `user_01` WHERE `id` IN(?,?, ?) AND name='john''s'  LIMIT 10"),
+			"SELECT * FROM `user_01` WHERE `id` IN(?) AND name=? LIMIT ?",
+		)
+		t.Assert(
+			normalizeSqlForStats("INSERT INTO `user`(`id`,`name`) VALUES(?,?),(?,?),(?,?)"),
+			"INSERT INTO `user`(`id`,`name`) VALUES(?)",
+		)
+	})
+}
+
+func Test_getPercentileLatency(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(getPercentileLatency(nil, 0.99), 0)
+		latencies := make([]time.Duration, 0)
+		for i := 100; i > 0; i-- {
+			latencies = append(latencies, time.Duration(i)*time.Millisecond)
+		}
+		t.Assert(getPercentileLatency(latencies, 0.99), 99*time.Millisecond)
+		t.Assert(getPercentileLatency(latencies, 0.5), 50*time.Millisecond)
+		t.Assert(getPercentileLatency(latencies[:1], 0.99), 100*time.Millisecond)
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/test/gtest"
+)
+
+func Test_Model_Iterator(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		iterator, err := db.Model(table).Where("id>?", 3).Order("id asc").Iterator()
+		t.AssertNil(err)
+		defer iterator.Close()
+		ids := make([]int, 0)
+		for iterator.Next() {
+			ids = append(ids, iterator.Record()["id"].Int())
+		}
+		t.AssertNil(iterator.Err())
+		t.Assert(ids, []int{4, 5, 6, 7, 8, 9, 10})
+		t.Assert(iterator.Next(), false)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		iterator, err := db.Model(table).Where("id>?", 100).Iterator(3)
+		t.AssertNil(err)
+		t.Assert(iterator.Next(), false)
+		t.AssertNil(iterator.Err())
+	})
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Order("id desc").Iterator(3)
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Model_Each(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		ids := make([]int, 0)
+		err := db.Model(table).Order("id asc").Each(func(record gdb.Record) error {
+			ids = append(ids, record["id"].Int())
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(len(ids), TableSize)
+		t.Assert(ids[0], 1)
+		t.Assert(ids[TableSize-1], TableSize)
+	})
+	// Keyset pagination.
+	gtest.C(t, func(t *gtest.T) {
+		ids := make([]int, 0)
+		err := db.Model(table).Where("id>?", 1).Each(func(record gdb.Record) error {
+			ids = append(ids, record["id"].Int())
+			return nil
+		}, 3)
+		t.AssertNil(err)
+		t.Assert(ids, []int{2, 3, 4, 5, 6, 7, 8, 9, 10})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		ids := make([]int, 0)
+		err := db.Model(table).Where("id<=?", 6).Each(func(record gdb.Record) error {
+			ids = append(ids, record["id"].Int())
+			return nil
+		}, 3)
+		t.AssertNil(err)
+		t.Assert(ids, []int{1, 2, 3, 4, 5, 6})
+	})
+	// Stops by callback error.
+	gtest.C(t, func(t *gtest.T) {
+		count := 0
+		err := db.Model(table).Each(func(record gdb.Record) error {
+			count++
+			if count == 5 {
+				return gerror.New("stop")
+			}
+			return nil
+		}, 2)
+		t.Assert(err, "stop")
+		t.Assert(count, 5)
+	})
+	// Context cancellation.
+	gtest.C(t, func(t *gtest.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		count := 0
+		err := db.Model(table).Ctx(ctx).Order("id asc").Each(func(record gdb.Record) error {
+			count++
+			if count == 3 {
+				cancel()
+			}
+			return nil
+		})
+		t.Assert(err, context.Canceled)
+		t.Assert(count, 3)
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/test/gtest"
+)
+
+func Test_Migrator(t *testing.T) {
+	var (
+		migrationTable = "migration_" + gtime.TimestampNanoStr()
+		userTable      = "migration_user_" + gtime.TimestampNanoStr()
+		migrator       = gdb.NewMigrator(db)
+	)
+	migrator.SetTable(migrationTable)
+	defer dropTable(migrationTable)
+	defer dropTable(migrationTable + "_lock")
+	defer dropTable(userTable)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := migrator.Register(
+			&gdb.Migration{
+				Version: 1,
+				Name:    "create_user",
+				UpSql: fmt.Sprintf(`
+CREATE TABLE %s (
+	id   int(10) unsigned NOT NULL AUTO_INCREMENT,
+	name varchar(45) NOT NULL,
+	PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+INSERT INTO %s(name) VALUES('john;smith');
+`, userTable, userTable),
+				DownSql: fmt.Sprintf(`DROP TABLE %s`, userTable),
+			},
+			&gdb.Migration{
+				Version: 2,
+				Name:    "add_user_age",
+				Up: func(tx *gdb.TX) error {
+					_, err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN age int(10) NOT NULL DEFAULT 18`, userTable))
+					return err
+				},
+				Down: func(tx *gdb.TX) error {
+					_, err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s DROP COLUMN age`, userTable))
+					return err
+				},
+			},
+		)
+		t.AssertNil(err)
+
+		err = migrator.Register(&gdb.Migration{Version: 1})
+		t.AssertNE(err, nil)
+
+		// Dry run.
+		migrations, err := migrator.DryRun().Up()
+		t.AssertNil(err)
+		t.Assert(len(migrations), 2)
+		status, err := migrator.Status()
+		t.AssertNil(err)
+		t.Assert(len(status), 2)
+		t.Assert(status[0].Applied, false)
+		t.Assert(status[1].Applied, false)
+
+		// Up.
+		migrations, err = migrator.DryRun(false).Up()
+		t.AssertNil(err)
+		t.Assert(len(migrations), 2)
+		t.Assert(migrations[0].Version, 1)
+		t.Assert(migrations[1].Version, 2)
+
+		one, err := db.Model(userTable).One()
+		t.AssertNil(err)
+		t.Assert(one["name"], "john;smith")
+		t.Assert(one["age"], 18)
+
+		status, err = migrator.Status()
+		t.AssertNil(err)
+		t.Assert(len(status), 2)
+		t.Assert(status[0].Applied, true)
+		t.Assert(status[1].Applied, true)
+		t.AssertNE(status[1].AppliedAt, nil)
+
+		// Nothing to apply.
+		migrations, err = migrator.Up()
+		t.AssertNil(err)
+		t.Assert(len(migrations), 0)
+
+		// Down.
+		migrations, err = migrator.Down(1)
+		t.AssertNil(err)
+		t.Assert(len(migrations), 1)
+		t.Assert(migrations[0].Version, 2)
+
+		_, err = db.GetAll(fmt.Sprintf(`SELECT age FROM %s`, userTable))
+		t.AssertNE(err, nil)
+
+		status, err = migrator.Status()
+		t.AssertNil(err)
+		t.Assert(status[0].Applied, true)
+		t.Assert(status[1].Applied, false)
+
+		migrations, err = migrator.Down(10)
+		t.AssertNil(err)
+		t.Assert(len(migrations), 1)
+		t.Assert(migrations[0].Version, 1)
+
+		_, err = migrator.Down(0)
+		t.AssertNE(err, nil)
+	})
+}
@@ -2659,12 +2659,7 @@ func Test_Model_Cache(t *testing.T) {
 		t.AssertNil(err)
 		t.Assert(n, 1)
 
-		one, err = db.Model(table).Cache(time.Second, "test1").FindOne(1)
-		t.AssertNil(err)
-		t.Assert(one["passport"], "user_1")
-
-		time.Sleep(time.Second * 2)
-
+		// The cache is removed automatically by updating on the table.
 		one, err = db.Model(table).Cache(time.Second, "test1").FindOne(1)
 		t.AssertNil(err)
 		t.Assert(one["passport"], "user_100")
@@ -2707,7 +2702,7 @@ func Test_Model_Cache(t *testing.T) {
 
 		one, err = db.Model(table).Cache(time.Second, "test3").FindOne(3)
 		t.AssertNil(err)
-		t.Assert(one["passport"], "user_3")
+		t.Assert(one["passport"], "user_300")
 	})
 	gtest.C(t, func(t *gtest.T) {
 		// make cache for id 4
@@ -2743,6 +2738,70 @@ func Test_Model_Cache(t *testing.T) {
 	})
 }
 
+func Test_Model_Cache_Invalidation(t *testing.T) {
+	var (
+		table1 = createInitTable()
+		table2 = createInitTable(fmt.Sprintf(`%s_%d`, TableName+"_detail", gtime.TimestampNano()))
+	)
+	defer dropTable(table1)
+	defer dropTable(table2)
+
+	// Auto cache key.
+	gtest.C(t, func(t *gtest.T) {
+		count, err := db.Model(table1).Cache(time.Hour).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize)
+
+		_, err = db.Model(table1).Where("id", 1).Delete()
+		t.AssertNil(err)
+
+		count, err = db.Model(table1).Cache(time.Hour).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize-1)
+	})
+	// Joined table.
+	gtest.C(t, func(t *gtest.T) {
+		getNickname := func() gdb.Value {
+			value, err := db.Model(table1+" u").
+				LeftJoin(table2+" ud", "ud.id=u.id").
+				Fields("ud.nickname").
+				Where("u.id", 2).
+				Cache(time.Hour).
+				Value()
+			t.AssertNil(err)
+			return value
+		}
+		t.Assert(getNickname(), "name_2")
+
+		_, err := db.Model(table2).Data("nickname", "name_200").Where("id", 2).Update()
+		t.AssertNil(err)
+		t.Assert(getNickname(), "name_200")
+
+		// Other table does not affect the cache.
+		_, err = db.Exec(fmt.Sprintf("UPDATE %s SET nickname='name_2000' WHERE id=2", table2))
+		t.AssertNil(err)
+		_, err = db.Model(table1).Data("nickname", "name_3").Where("id", 3).Update()
+		t.AssertNil(err)
+		t.Assert(getNickname(), "name_200")
+	})
+	// Sub query.
+	gtest.C(t, func(t *gtest.T) {
+		getCount := func() int {
+			count, err := db.Model(table1).
+				Where("id", db.Model(table2).Fields("id").Where("id<?", 6)).
+				Cache(time.Hour).
+				Count()
+			t.AssertNil(err)
+			return count
+		}
+		t.Assert(getCount(), 4)
+
+		_, err := db.Model(table2).Where("id", 3).Delete()
+		t.AssertNil(err)
+		t.Assert(getCount(), 3)
+	})
+}
+
 func Test_Model_Having(t *testing.T) {
 	table := createInitTable()
 	defer dropTable(table)
@@ -3321,3 +3380,381 @@ func Test_Model_Fields_AutoFilterInJoinStatement(t *testing.T) {
 		t.Assert(one["number"].String(), "n")
 	})
 }
+
+func Test_Model_Aggregate(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		sum, err := db.Model(table).Sum("id")
+		t.AssertNil(err)
+		t.Assert(sum, 55)
+
+		avg, err := db.Model(table).Where("id<?", 5).Avg("id")
+		t.AssertNil(err)
+		t.Assert(avg, 2.5)
+
+		min, err := db.Model(table).Min("id", "id>?", 3)
+		t.AssertNil(err)
+		t.Assert(min, 4)
+
+		max, err := db.Model(table).Max("id")
+		t.AssertNil(err)
+		t.Assert(max, TableSize)
+
+		sum, err = db.Model(table).Where("id>?", 100).Sum("id")
+		t.AssertNil(err)
+		t.Assert(sum, 0)
+	})
+}
+
+func Test_Model_Increment_Decrement(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		result, err := db.Model(table).Where("id", 1).Increment("id", 100)
+		t.AssertNil(err)
+		n, _ := result.RowsAffected()
+		t.Assert(n, 1)
+
+		count, err := db.Model(table).Count("id", 101)
+		t.AssertNil(err)
+		t.Assert(count, 1)
+
+		_, err = db.Model(table).Where("id", 101).Decrement("id", 10)
+		t.AssertNil(err)
+		count, err = db.Model(table).Count("id", 91)
+		t.AssertNil(err)
+		t.Assert(count, 1)
+	})
+}
+
+func Test_Model_Aggregate_SoftDeleting(t *testing.T) {
+	table := "aggregate_test_table_" + gtime.TimestampNanoStr()
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE %s (
+  id        int(11) NOT NULL,
+  score     int(11) NOT NULL DEFAULT 0,
+  update_at datetime DEFAULT NULL,
+  delete_at datetime DEFAULT NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data(g.List{
+			{"id": 1, "score": 10},
+			{"id": 2, "score": 20},
+			{"id": 3, "score": 30},
+		}).Insert()
+		t.AssertNil(err)
+
+		_, err = db.Model(table).Delete("id", 3)
+		t.AssertNil(err)
+
+		sum, err := db.Model(table).Sum("score")
+		t.AssertNil(err)
+		t.Assert(sum, 30)
+
+		sum, err = db.Model(table).Unscoped().Sum("score")
+		t.AssertNil(err)
+		t.Assert(sum, 60)
+
+		_, err = db.Model(table).Where("id>?", 0).Increment("score", 5)
+		t.AssertNil(err)
+		max, err := db.Model(table).Unscoped().Max("score")
+		t.AssertNil(err)
+		t.Assert(max, 30)
+		max, err = db.Model(table).Max("score")
+		t.AssertNil(err)
+		t.Assert(max, 25)
+
+		one, err := db.Model(table).FindOne(1)
+		t.AssertNil(err)
+		t.AssertNE(one["update_at"].String(), "")
+	})
+}
+
+func Test_Model_OnDuplicate(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	// string fields.
+	gtest.C(t, func(t *gtest.T) {
+		data := g.Map{
+			"id":          1,
+			"passport":    "pp1",
+			"password":    "pw1",
+			"nickname":    "n1",
+			"create_time": "2016-06-06",
+		}
+		_, err := db.Model(table).OnDuplicate("passport,password").Data(data).Save()
+		t.AssertNil(err)
+		one, err := db.Model(table).FindOne(1)
+		t.AssertNil(err)
+		t.Assert(one["passport"], data["passport"])
+		t.Assert(one["password"], data["password"])
+		t.Assert(one["nickname"], "name_1")
+	})
+	// map with column name and Raw expression.
+	gtest.C(t, func(t *gtest.T) {
+		data := g.Map{
+			"id":          2,
+			"passport":    "pp2",
+			"password":    "pw2",
+			"nickname":    "n2",
+			"create_time": "2016-06-06",
+		}
+		_, err := db.Model(table).OnDuplicate(g.Map{
+			"passport": "nickname",
+			"password": gdb.Raw("CONCAT(VALUES(password), '_new')"),
+		}).Data(data).Save()
+		t.AssertNil(err)
+		one, err := db.Model(table).FindOne(2)
+		t.AssertNil(err)
+		t.Assert(one["passport"], data["nickname"])
+		t.Assert(one["password"], "pw2_new")
+		t.Assert(one["nickname"], "name_2")
+	})
+	// raw string statement.
+	gtest.C(t, func(t *gtest.T) {
+		data := g.Map{
+			"id":       3,
+			"passport": "pp3",
+			"nickname": "n3",
+		}
+		_, err := db.Model(table).OnDuplicate("nickname=CONCAT(nickname, '_dup')").Data(data).Save()
+		t.AssertNil(err)
+		one, err := db.Model(table).FindOne(3)
+		t.AssertNil(err)
+		t.Assert(one["passport"], "user_3")
+		t.Assert(one["nickname"], "name_3_dup")
+	})
+}
+
+func Test_Model_OnDuplicateEx(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		data := g.List{
+			{
+				"id":          1,
+				"passport":    "pp1",
+				"password":    "pw1",
+				"nickname":    "n1",
+				"create_time": "2016-06-06",
+			},
+			{
+				"id":          2,
+				"passport":    "pp2",
+				"password":    "pw2",
+				"nickname":    "n2",
+				"create_time": "2016-06-06",
+			},
+		}
+		_, err := db.Model(table).OnDuplicateEx("nickname", "create_time").Data(data).Save()
+		t.AssertNil(err)
+		all, err := db.Model(table).Where("id", g.Slice{1, 2}).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(len(all), 2)
+		t.Assert(all[0]["passport"], "pp1")
+		t.Assert(all[0]["password"], "pw1")
+		t.Assert(all[0]["nickname"], "name_1")
+		t.Assert(all[1]["passport"], "pp2")
+		t.Assert(all[1]["nickname"], "name_2")
+	})
+}
+
+func Test_Model_BatchUpdate(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model(table).Batch(2).BatchUpdate(g.List{
+			{"id": 1, "nickname": "batch_1"},
+			{"id": 2, "nickname": "batch_2", "passport": "batch_passport_2"},
+			{"id": 3, "passport": gdb.Raw("UPPER(passport)")},
+		})
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 3)
+
+		all, err := db.Model(table).Where("id", g.Slice{1, 2, 3, 4}).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(all[0]["nickname"], "batch_1")
+		t.Assert(all[0]["passport"], "user_1")
+		t.Assert(all[1]["nickname"], "batch_2")
+		t.Assert(all[1]["passport"], "batch_passport_2")
+		t.Assert(all[2]["nickname"], "name_3")
+		t.Assert(all[2]["passport"], "USER_3")
+		t.Assert(all[3]["nickname"], "name_4")
+	})
+	// Struct list with key field and conditions.
+	gtest.C(t, func(t *gtest.T) {
+		type User struct {
+			Passport string
+			Nickname string
+		}
+		r, err := db.Model(table).Where("id>?", 5).BatchUpdate([]User{
+			{Passport: "user_5", Nickname: "struct_5"},
+			{Passport: "user_6", Nickname: "struct_6"},
+		}, "passport")
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 1)
+
+		value, err := db.Model(table).Fields("nickname").Where("id", 5).Value()
+		t.AssertNil(err)
+		t.Assert(value, "name_5")
+		value, err = db.Model(table).Fields("nickname").Where("id", 6).Value()
+		t.AssertNil(err)
+		t.Assert(value, "struct_6")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).BatchUpdate(g.List{{"nickname": "no_key"}})
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Model_WhereIn_Between_Like(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		all, err := db.Model(table).WhereIn("id", g.Slice{1, 2, 3}).WhereNotIn("id", g.Slice{2}).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{1, 3})
+
+		all, err = db.Model(table).WhereIn("id", g.Slice{}).All()
+		t.AssertNil(err)
+		t.Assert(len(all), 0)
+
+		count, err := db.Model(table).WhereNotIn("id", g.Slice{}).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize)
+
+		all, err = db.Model(table).WhereBetween("id", 3, 5).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{3, 4, 5})
+
+		count, err = db.Model(table).WhereNotBetween("id", 3, 5).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize-3)
+
+		all, err = db.Model(table+" u").WhereLike("u.nickname", "name_1%").Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{1, 10})
+
+		count, err = db.Model(table).WhereNotLike("nickname", "name_1%").Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize-2)
+	})
+}
+
+func Test_Model_WhereNull_Compare(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data("nickname", nil).Where("id", g.Slice{1, 2}).Update()
+		t.AssertNil(err)
+
+		all, err := db.Model(table).WhereNull("nickname").Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{1, 2})
+
+		count, err := db.Model(table).WhereNotNull("nickname", "passport").Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize-2)
+
+		count, err = db.Model(table).WhereGT("id", 8).Count()
+		t.AssertNil(err)
+		t.Assert(count, 2)
+		count, err = db.Model(table).WhereGTE("id", 8).Count()
+		t.AssertNil(err)
+		t.Assert(count, 3)
+		count, err = db.Model(table).WhereLT("id", 3).Count()
+		t.AssertNil(err)
+		t.Assert(count, 2)
+		count, err = db.Model(table).WhereLTE("id", 3).Count()
+		t.AssertNil(err)
+		t.Assert(count, 3)
+	})
+}
+
+func Test_Model_WhereBuilder(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		// (id=1 OR id=2) AND nickname='name_2'
+		all, err := db.Model(table).WhereBuilder(func(b *gdb.Model) *gdb.Model {
+			return b.Where("id", 1).Or("id", 2)
+		}).Where("nickname", "name_2").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{2})
+
+		// id>8 OR (id<3 AND passport LIKE 'user_1%')
+		all, err = db.Model(table).WhereGT("id", 8).OrBuilder(func(b *gdb.Model) *gdb.Model {
+			return b.WhereLT("id", 3).WhereLike("passport", "user_1%")
+		}).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{1, 9, 10})
+
+		// Nested builders.
+		all, err = db.Model(table).WhereBuilder(func(b *gdb.Model) *gdb.Model {
+			return b.WhereIn("id", g.Slice{1, 2, 3}).WhereBuilder(func(b *gdb.Model) *gdb.Model {
+				return b.Where("id", 1).Or("id", 3)
+			})
+		}).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{1, 3})
+	})
+}
+
+func Test_Model_WhereIn_Empty_Or(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		all, err := db.Model(table).Where("id", 1).OrBuilder(func(b *gdb.Model) *gdb.Model {
+			return b.WhereIn("id", g.Slice{})
+		}).All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{1})
+
+		all, err = db.Model(table).Where("id", 2).Or("id", 3).WhereIn("id", g.Slice{}).All()
+		t.AssertNil(err)
+		t.Assert(len(all), 0)
+	})
+}
+
+func Test_Model_Where_Invalid_Column(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).WhereIn("id) OR 1=1 -- ", g.Slice{1}).All()
+		t.AssertNE(err, nil)
+
+		_, err = db.Model(table).WhereGT("u.id OR 1", 1).Count()
+		t.AssertNE(err, nil)
+
+		_, err = db.Model(table).WhereBuilder(func(b *gdb.Model) *gdb.Model {
+			return b.WhereNull("nickname; DROP TABLE user")
+		}).Data("nickname", "name").Update()
+		t.AssertNE(err, nil)
+
+		_, err = db.Model(table).WhereLike("nickname`", "name%").Delete()
+		t.AssertNE(err, nil)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize)
+	})
+}
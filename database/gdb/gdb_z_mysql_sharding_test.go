@@ -0,0 +1,131 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/test/gtest"
+)
+
+func Test_Model_Sharding_Modulo(t *testing.T) {
+	var (
+		prefix = fmt.Sprintf(`user_log_%d_`, gtime.TimestampNano())
+		rule   = &gdb.ShardingModulo{Key: "id", Count: 4, Prefix: prefix}
+	)
+	for i := 0; i < rule.Count; i++ {
+		table := createTable(fmt.Sprintf(`%s%02d`, prefix, i))
+		defer dropTable(table)
+	}
+
+	gtest.C(t, func(t *gtest.T) {
+		list := g.List{}
+		for i := 1; i <= 10; i++ {
+			list = append(list, g.Map{
+				"id":       i,
+				"passport": fmt.Sprintf(`user_%d`, i),
+				"password": fmt.Sprintf(`pass_%d`, i),
+				"nickname": fmt.Sprintf(`name_%d`, i),
+			})
+		}
+		r, err := db.Model("user_log").Sharding(rule).Data(list).Insert()
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 10)
+
+		// Records are split into the shards.
+		count, err := db.Model(prefix + "01").Count()
+		t.AssertNil(err)
+		t.Assert(count, 3)
+		count, err = db.Model(prefix + "03").Count()
+		t.AssertNil(err)
+		t.Assert(count, 2)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		one, err := db.Model("user_log").Sharding(rule).Where("id", 6).One()
+		t.AssertNil(err)
+		t.Assert(one["passport"], "user_6")
+
+		// Fan out with ordering and limit.
+		all, err := db.Model("user_log").Sharding(rule).Order("id desc").Limit(2, 3).All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{8, 7, 6})
+
+		all, err = db.Model("user_log").Sharding(rule).Where("id BETWEEN ? AND ?", 3, 5).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{3, 4, 5})
+
+		count, err := db.Model("user_log").Sharding(rule).Where("id>?", 5).Count()
+		t.AssertNil(err)
+		t.Assert(count, 5)
+
+		max, err := db.Model("user_log").Sharding(rule).Max("id")
+		t.AssertNil(err)
+		t.Assert(max, 10)
+		sum, err := db.Model("user_log").Sharding(rule).Sum("id")
+		t.AssertNil(err)
+		t.Assert(sum, 55)
+		avg, err := db.Model("user_log").Sharding(rule).Avg("id")
+		t.AssertNil(err)
+		t.Assert(avg, 5.5)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model("user_log").Sharding(rule).Data(g.Map{"nickname": "updated"}).Where("id IN(?)", g.Slice{1, 2, 3}).Update()
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 3)
+
+		r, err = db.Model("user_log").Sharding(rule).Where("id<=?", 2).Delete()
+		t.AssertNil(err)
+		n, _ = r.RowsAffected()
+		t.Assert(n, 2)
+
+		all, err := db.Model("user_log").Sharding(rule).Where("nickname", "updated").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{3})
+	})
+}
+
+func Test_Model_Sharding_Month(t *testing.T) {
+	var (
+		prefix = fmt.Sprintf(`order_%d_`, gtime.TimestampNano())
+		start  = gtime.Now().StartOfMonth().AddDate(0, -2, 0)
+		rule   = &gdb.ShardingMonth{Key: "create_time", Prefix: prefix, Start: start.Time}
+	)
+	for i := 0; i < 3; i++ {
+		table := createTable(prefix + start.AddDate(0, i, 0).Format("Ym"))
+		defer dropTable(table)
+	}
+
+	gtest.C(t, func(t *gtest.T) {
+		for i := 0; i < 3; i++ {
+			_, err := db.Model("order").Sharding(rule).Data(g.Map{
+				"id":          i + 1,
+				"passport":    fmt.Sprintf(`user_%d`, i+1),
+				"password":    fmt.Sprintf(`pass_%d`, i+1),
+				"nickname":    fmt.Sprintf(`name_%d`, i+1),
+				"create_time": start.AddDate(0, i, 0).String(),
+			}).Insert()
+			t.AssertNil(err)
+		}
+		count, err := db.Model(prefix + start.Format("Ym")).Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+
+		all, err := db.Model("order").Sharding(rule).Order("id desc").All()
+		t.AssertNil(err)
+		t.Assert(all.Array("id"), g.Slice{3, 2, 1})
+
+		all, err = db.Model("order").Sharding(rule).Where("create_time>=?", start.AddDate(0, 1, 0).String()).All()
+		t.AssertNil(err)
+		t.Assert(len(all), 2)
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/os/glog"
+	"github.com/gogf/gf/test/gtest"
+	"github.com/gogf/gf/text/gstr"
+)
+
+func Test_DB_SlowThreshold(t *testing.T) {
+	node := configNode
+	node.SlowThreshold = 50 * time.Millisecond
+	gdb.AddConfigNode("slow-test", node)
+
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			buffer = bytes.NewBuffer(nil)
+			logger = glog.New()
+		)
+		logger.SetWriter(buffer)
+		logger.SetStdoutPrint(false)
+		slowDB, err := gdb.New("slow-test")
+		t.AssertNil(err)
+		slowDB.SetLogger(logger)
+
+		_, err = slowDB.Query("SELECT 1")
+		t.AssertNil(err)
+		t.Assert(buffer.String(), "")
+
+		_, err = slowDB.Query("SELECT SLEEP(?)", 0.1)
+		t.AssertNil(err)
+		t.Assert(gstr.Contains(buffer.String(), "[slow] SELECT SLEEP(0.1)"), true)
+		t.Assert(gstr.Contains(buffer.String(), "gdb_z_mysql_stats_test.go"), true)
+	})
+}
+
+func Test_DB_Stats(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		db.GetConfig().StatsEnabled = true
+		defer func() {
+			db.GetConfig().StatsEnabled = false
+		}()
+		db.ResetStats()
+		for i := 1; i <= 3; i++ {
+			_, err := db.Model(table).Where("id", i).One()
+			t.AssertNil(err)
+		}
+		_, err := db.Model(table).Data("nickname", "updated").Where("id<?", 5).Update()
+		t.AssertNil(err)
+		_, err = db.Model(table+"_nonexistent").Where("id", 1).One()
+		t.AssertNE(err, nil)
+
+		stats := db.Stats()
+		t.Assert(stats.Group, gdb.DefaultGroupName)
+		t.AssertGT(len(stats.Nodes), 0)
+		t.AssertGT(stats.Nodes[0].Stats.OpenConnections, 0)
+
+		statements := make(map[string]*gdb.StatementStats)
+		for _, s := range stats.Statements {
+			statements[s.Sql] = s
+		}
+		selectStats := statements["SELECT * FROM `"+table+"` WHERE `id`=? LIMIT ?"]
+		t.AssertNE(selectStats, nil)
+		t.Assert(selectStats.Count, 3)
+		t.Assert(selectStats.ErrorCount, 0)
+		t.Assert(selectStats.AvgLatency, selectStats.TotalLatency/3)
+
+		updateStats := statements["UPDATE `"+table+"` SET `nickname`=? WHERE id<?"]
+		t.AssertNE(updateStats, nil)
+		t.Assert(updateStats.RowsAffected, 4)
+
+		errorStats := statements["SELECT * FROM `"+table+"_nonexistent` WHERE `id`=? LIMIT ?"]
+		t.AssertNE(errorStats, nil)
+		t.Assert(errorStats.ErrorCount, 1)
+	})
+}
@@ -796,3 +796,98 @@ CREATE TABLE %s (
 		t.Assert(i, 0)
 	})
 }
+
+func Test_SoftDelete_Flag(t *testing.T) {
+	table := "time_test_table_" + gtime.TimestampNanoStr()
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE %s (
+  id         int(11) NOT NULL,
+  name       varchar(45) DEFAULT NULL,
+  is_deleted tinyint(1) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(table)
+
+	// The flag field should be configured explicitly.
+	db.GetConfig().DeletedAt = "is_deleted"
+	defer func() {
+		db.GetConfig().DeletedAt = ""
+	}()
+
+	gtest.C(t, func(t *gtest.T) {
+		for i := 1; i <= 3; i++ {
+			_, err := db.Model(table).Data(g.Map{"id": i, "name": fmt.Sprintf("name_%d", i)}).Insert()
+			t.AssertNil(err)
+		}
+		r, err := db.Model(table).Delete("id", 1)
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 1)
+
+		one, err := db.Model(table).FindOne(1)
+		t.AssertNil(err)
+		t.Assert(len(one), 0)
+		one, err = db.Model(table).Unscoped().FindOne(1)
+		t.AssertNil(err)
+		t.Assert(one["is_deleted"].Int(), 1)
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 2)
+
+		// Force delete.
+		r, err = db.Model(table).ForceDelete("id", 2)
+		t.AssertNil(err)
+		n, _ = r.RowsAffected()
+		t.Assert(n, 1)
+		count, err = db.Model(table).Unscoped().Count()
+		t.AssertNil(err)
+		t.Assert(count, 2)
+	})
+}
+
+func Test_SoftCreateUpdateDeleteTime_Timestamp(t *testing.T) {
+	table := "time_test_table_" + gtime.TimestampNanoStr()
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE %s (
+  id         int(11) NOT NULL,
+  name       varchar(45) DEFAULT NULL,
+  created_at int(11) unsigned NOT NULL DEFAULT 0,
+  updated_at int(11) unsigned NOT NULL DEFAULT 0,
+  deleted_at int(11) unsigned NOT NULL DEFAULT 0,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data(g.Map{"id": 1, "name": "name_1"}).Insert()
+		t.AssertNil(err)
+
+		one, err := db.Model(table).FindOne(1)
+		t.AssertNil(err)
+		t.AssertGE(one["created_at"].Int64(), gtime.Timestamp()-2)
+		t.AssertGE(one["updated_at"].Int64(), gtime.Timestamp()-2)
+		t.Assert(one["deleted_at"].Int64(), 0)
+
+		_, err = db.Model(table).Data("name='name_10'").WherePri(1).Update()
+		t.AssertNil(err)
+		one, err = db.Model(table).FindOne(1)
+		t.AssertNil(err)
+		t.Assert(one["name"], "name_10")
+		t.AssertGE(one["updated_at"].Int64(), gtime.Timestamp()-2)
+
+		_, err = db.Model(table).Delete("id", 1)
+		t.AssertNil(err)
+		one, err = db.Model(table).FindOne(1)
+		t.AssertNil(err)
+		t.Assert(len(one), 0)
+		one, err = db.Model(table).Unscoped().FindOne(1)
+		t.AssertNil(err)
+		t.AssertGE(one["deleted_at"].Int64(), gtime.Timestamp()-2)
+	})
+}
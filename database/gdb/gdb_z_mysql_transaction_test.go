@@ -789,3 +789,101 @@ func Test_Transaction_Panic(t *testing.T) {
 		}
 	})
 }
+
+func Test_Transaction_Nested(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(func(tx *gdb.TX) error {
+			if _, err := tx.Model(table).Data("nickname", "NAME_1").Where("id", 1).Update(); err != nil {
+				return err
+			}
+			// The nested transaction rolls back to its savepoint only.
+			err := tx.Transaction(func(tx *gdb.TX) error {
+				if _, err := tx.Model(table).Data("nickname", "NAME_2").Where("id", 2).Update(); err != nil {
+					return err
+				}
+				return gerror.New("error")
+			})
+			t.AssertNE(err, nil)
+			// The nested transaction joined by context.
+			return db.Ctx(tx.GetCtx()).Transaction(func(tx *gdb.TX) error {
+				_, err := tx.Model(table).Data("nickname", "NAME_3").Where("id", 3).Update()
+				return err
+			})
+		})
+		t.AssertNil(err)
+
+		value, err := db.Model(table).Fields("nickname").Where("id", 1).Value()
+		t.AssertNil(err)
+		t.Assert(value.String(), "NAME_1")
+
+		value, err = db.Model(table).Fields("nickname").Where("id", 2).Value()
+		t.AssertNil(err)
+		t.Assert(value.String(), "name_2")
+
+		value, err = db.Model(table).Fields("nickname").Where("id", 3).Value()
+		t.AssertNil(err)
+		t.Assert(value.String(), "NAME_3")
+	})
+}
+
+func Test_Transaction_Nested_Model(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Model(table).Transaction(func(tx *gdb.TX) error {
+			ctx := tx.GetCtx()
+			// The model operation joins the transaction from context.
+			if _, err := db.Model(table).Ctx(ctx).Data("nickname", "NAME_1").Where("id", 1).Update(); err != nil {
+				return err
+			}
+			return db.Model(table).Ctx(ctx).Transaction(func(tx *gdb.TX) error {
+				if _, err := tx.Model(table).Data("nickname", "NAME_2").Where("id", 2).Update(); err != nil {
+					return err
+				}
+				panic("error")
+			})
+		})
+		t.AssertNE(err, nil)
+
+		value, err := db.Model(table).Fields("nickname").Where("id", 1).Value()
+		t.AssertNil(err)
+		t.Assert(value.String(), "name_1")
+
+		value, err = db.Model(table).Fields("nickname").Where("id", 2).Value()
+		t.AssertNil(err)
+		t.Assert(value.String(), "name_2")
+	})
+}
+
+func Test_TX_SavePoint(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin()
+		t.AssertNil(err)
+
+		_, err = tx.Model(table).Data("nickname", "NAME_1").Where("id", 1).Update()
+		t.AssertNil(err)
+		t.AssertNil(tx.SavePoint("point1"))
+
+		_, err = tx.Model(table).Data("nickname", "NAME_2").Where("id", 2).Update()
+		t.AssertNil(err)
+		t.AssertNil(tx.RollbackTo("point1"))
+
+		t.AssertNil(tx.Commit())
+		t.Assert(tx.IsClosed(), true)
+
+		value, err := db.Model(table).Fields("nickname").Where("id", 1).Value()
+		t.AssertNil(err)
+		t.Assert(value.String(), "NAME_1")
+
+		value, err = db.Model(table).Fields("nickname").Where("id", 2).Value()
+		t.AssertNil(err)
+		t.Assert(value.String(), "name_2")
+	})
+}
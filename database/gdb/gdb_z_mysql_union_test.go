@@ -0,0 +1,127 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/test/gtest"
+)
+
+func Test_Model_Union(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model(table).Where("id", 1).Union(
+			db.Model(table).Where("id", 2),
+			db.Model(table).Where("id", g.Slice{1, 2, 3}),
+		).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(len(r), 3)
+		t.Assert(r[0]["id"], 1)
+		t.Assert(r[1]["id"], 2)
+		t.Assert(r[2]["id"], 3)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model(table).Where("id<?", 3).UnionAll(
+			db.Model(table).Where("id", g.Slice{1, 2, 3}),
+		).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(len(r), 5)
+		t.Assert(r[0]["id"], 1)
+		t.Assert(r[1]["id"], 1)
+		t.Assert(r[4]["id"], 3)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		count, err := db.Model(table).Where("id<?", 3).UnionAll(
+			db.Model(table).Where("id>?", 8),
+		).Where("id>?", 1).Count()
+		t.AssertNil(err)
+		t.Assert(count, 3)
+	})
+}
+
+func Test_Model_SubQuery_Where(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model(table).Where(
+			"id IN(?)", db.Model(table).Fields("id").Where("id>?", 7),
+		).Where("id<?", 10).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(len(r), 2)
+		t.Assert(r[0]["id"], 8)
+		t.Assert(r[1]["id"], 9)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model(table).Where("id>?", 1).Where(
+			"id", db.Model(table).Fields("id").Where("id", g.Slice{1, 2, 3}),
+		).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(len(r), 2)
+		t.Assert(r[0]["id"], 2)
+		t.Assert(r[1]["id"], 3)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model(table).Where(g.Map{
+			"id": db.Model(table).Fields("id").Where("id<=?", 5),
+		}).Where("id>?", 3).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(len(r), 2)
+		t.Assert(r[0]["id"], 4)
+		t.Assert(r[1]["id"], 5)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.GetAll(
+			"SELECT * FROM "+table+" WHERE id>? AND id IN(?) ORDER BY id ASC",
+			2, db.Model(table).Fields("id").Where("id<?", 5),
+		)
+		t.AssertNil(err)
+		t.Assert(len(r), 2)
+		t.Assert(r[0]["id"], 3)
+		t.Assert(r[1]["id"], 4)
+	})
+}
+
+func Test_Model_SubQuery_Table(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model().Table(
+			db.Model(table).Where("id>?", 5), "t",
+		).Where("t.id<?", 8).Order("t.id asc").All()
+		t.AssertNil(err)
+		t.Assert(len(r), 2)
+		t.Assert(r[0]["id"], 6)
+		t.Assert(r[1]["id"], 7)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		count, err := db.Model().Table(db.Model(table).Where("id>?", 5)).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize-5)
+	})
+}
+
+func Test_Model_SubQuery_Fields(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		r, err := db.Model(table).Fields(
+			"id, (?) AS total", db.Model(table).Fields("COUNT(1)").Where("id>?", 5),
+		).Where("id<?", 3).Order("id asc").All()
+		t.AssertNil(err)
+		t.Assert(len(r), 2)
+		t.Assert(r[0]["id"], 1)
+		t.Assert(r[0]["total"], TableSize-5)
+		t.Assert(r[1]["total"], TableSize-5)
+	})
+}
@@ -0,0 +1,90 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/test/gtest"
+)
+
+func Test_Model_Update_Version(t *testing.T) {
+	table := "version_test_table_" + gtime.TimestampNanoStr()
+	if _, err := db.Exec(fmt.Sprintf(`
+CREATE TABLE %s (
+  id      int(11) NOT NULL,
+  name    varchar(45) DEFAULT NULL,
+  version int(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(table)
+
+	type Entity struct {
+		Id      int    `orm:"id"`
+		Name    string `orm:"name"`
+		Version int    `orm:"version"`
+	}
+
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data(g.Map{"id": 1, "name": "name_1"}).Insert()
+		t.AssertNil(err)
+
+		// Two readers of the same version.
+		var entity1, entity2 *Entity
+		t.AssertNil(db.Model(table).WherePri(1).Scan(&entity1))
+		t.AssertNil(db.Model(table).WherePri(1).Scan(&entity2))
+		t.Assert(entity1.Version, 0)
+
+		entity1.Name = "name_100"
+		r, err := db.Model(table).Data(entity1).WherePri(1).Update()
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 1)
+
+		// Lost update is prevented.
+		entity2.Name = "name_200"
+		_, err = db.Model(table).Data(entity2).WherePri(1).Update()
+		t.AssertNE(err, nil)
+		t.Assert(errors.Is(err, gdb.ErrVersionConflict), true)
+		conflictErr, ok := err.(*gdb.VersionConflictError)
+		t.Assert(ok, true)
+		t.Assert(conflictErr.Field, "version")
+		t.Assert(conflictErr.Version, 0)
+
+		one, err := db.Model(table).FindOne(1)
+		t.AssertNil(err)
+		t.Assert(one["name"], "name_100")
+		t.Assert(one["version"], 1)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		// Version value in map.
+		_, err := db.Model(table).Data(g.Map{"name": "name_300", "version": 1}).WherePri(1).Update()
+		t.AssertNil(err)
+		// No version value given, the version is increased without checking.
+		_, err = db.Model(table).Data(g.Map{"name": "name_400"}).WherePri(1).Update()
+		t.AssertNil(err)
+		_, err = db.Model(table).Data("name='name_500'").WherePri(1).Update()
+		t.AssertNil(err)
+
+		one, err := db.Model(table).FindOne(1)
+		t.AssertNil(err)
+		t.Assert(one["name"], "name_500")
+		t.Assert(one["version"], 4)
+
+		// Record that does not exist.
+		_, err = db.Model(table).Data(g.Map{"name": "name_600", "version": 4}).WherePri(2).Update()
+		t.Assert(errors.Is(err, gdb.ErrVersionConflict), true)
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/gogf/gf/database/gdb"
 	"github.com/gogf/gf/text/gregex"
 	"github.com/gogf/gf/util/gconv"
+	"time"
 )
 
 const (
@@ -159,6 +160,10 @@ func parseDBConfigNode(value interface{}) *gdb.ConfigNode {
 	if _, v := gutil.MapPossibleItemByKey(nodeMap, "link"); v != nil {
 		node.LinkInfo = gconv.String(v)
 	}
+	// The slow threshold configured using number is in milliseconds, like: 200.
+	if _, v := gutil.MapPossibleItemByKey(nodeMap, "slowThreshold"); v != nil && gstr.IsNumeric(gconv.String(v)) {
+		node.SlowThreshold = time.Duration(gconv.Int64(v)) * time.Millisecond
+	}
 	// Parse link syntax.
 	if node.LinkInfo != "" && node.Type == "" {
 		match, _ := gregex.MatchString(`([a-z]+):(.+)`, node.LinkInfo)
@@ -0,0 +1,27 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gins
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/test/gtest"
+)
+
+func Test_parseDBConfigNode_SlowThreshold(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		node := parseDBConfigNode(map[string]interface{}{"slowThreshold": 200})
+		t.Assert(node.SlowThreshold, 200*time.Millisecond)
+		node = parseDBConfigNode(map[string]interface{}{"slowThreshold": "200"})
+		t.Assert(node.SlowThreshold, 200*time.Millisecond)
+		node = parseDBConfigNode(map[string]interface{}{"slowThreshold": "1s"})
+		t.Assert(node.SlowThreshold, time.Second)
+		node = parseDBConfigNode(map[string]interface{}{"slowThreshold": "500us"})
+		t.Assert(node.SlowThreshold, 500*time.Microsecond)
+	})
+}
@@ -69,6 +69,7 @@ type (
 		shutFunc   HandlerFunc        // Shutdown function when request leaves out the object(only available for object register type).
 		middleware []HandlerFunc      // Bound middleware array.
 		ctrlInfo   *handlerController // Controller information for reflect usage.
+		typedInfo  *handlerTypedInfo  // Typed handler information for request parsing and response writing.
 		hookName   string             // Hook type name.
 		router     *Router            // Router object.
 		source     string             // Source file path:line when registering.
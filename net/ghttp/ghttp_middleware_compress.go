@@ -0,0 +1,382 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/container/gmap"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/os/gres"
+	"github.com/gogf/gf/util/gconv"
+)
+
+// CompressEncoderFunc creates and returns a writer compressing the content written to `w`
+// using compression `level`.
+type CompressEncoderFunc = func(w io.Writer, level int) (io.WriteCloser, error)
+
+// compressWriter is the http.ResponseWriter compressing the content for static file serving.
+type compressWriter struct {
+	http.ResponseWriter
+	server   *Server
+	encoding string              // Content encoding, like: gzip, deflate.
+	encoder  io.WriteCloser      // Compressing writer, which is nil if the content is not compressed.
+	newFunc  CompressEncoderFunc // Function creating the compressing writer.
+}
+
+const (
+	compressEncodingGzip     = "gzip"
+	compressEncodingDeflate  = "deflate"
+	compressEncodingIdentity = "identity"
+	compressEncodingAny      = "*"
+	compressVaryHeader       = "Accept-Encoding"
+)
+
+var (
+	// compressEncoderMap stores the registered encoders by content encoding name.
+	compressEncoderMap = gmap.NewStrAnyMap(true)
+
+	// compressFileExtensions maps content encoding to the file extension of precompressed static file.
+	compressFileExtensions = map[string]string{
+		compressEncodingGzip: ".gz",
+		"br":                 ".br",
+	}
+)
+
+func init() {
+	RegisterCompressEncoder(compressEncodingGzip, func(w io.Writer, level int) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, level)
+	})
+	RegisterCompressEncoder(compressEncodingDeflate, func(w io.Writer, level int) (io.WriteCloser, error) {
+		return zlib.NewWriterLevel(w, level)
+	})
+}
+
+// RegisterCompressEncoder registers encoder `f` for content encoding `encoding`, which can be
+// used to add brotli compression using third-party package, like:
+// RegisterCompressEncoder("br", func(w io.Writer, level int) (io.WriteCloser, error) {...}).
+// Note that the encoding should also be added to CompressEncodings of server configuration
+// if it's not "br", "gzip" or "deflate".
+func RegisterCompressEncoder(encoding string, f CompressEncoderFunc) {
+	compressEncoderMap.Set(strings.ToLower(encoding), f)
+}
+
+// MiddlewareCompress is a middleware that compresses the response content according to the
+// "Accept-Encoding" header of the client, which makes sense only if CompressEnabled of
+// server configuration is false, as it compresses all responses if CompressEnabled is true.
+func MiddlewareCompress(r *Request) {
+	r.Response.compress = true
+	r.Middleware.Next()
+}
+
+// compressResponse compresses the buffer content of `r` if it is compressible, and sets the
+// headers "Content-Encoding", "Content-Length" and "Vary" accordingly.
+// It does nothing if the response is hijacked or any content is already output to client.
+func (s *Server) compressResponse(r *Request) {
+	response := r.Response
+	if response.hijacked || response.wroteHeader {
+		return
+	}
+	if !isCompressibleStatus(response.Status) || int64(response.buffer.Len()) < s.config.CompressMinSize {
+		return
+	}
+	header := response.Header()
+	if header.Get("Content-Encoding") != "" || strings.Contains(header.Get("Cache-Control"), "no-transform") {
+		return
+	}
+	// The content type should be set before compressing, or else it is detected using compressed content.
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(response.buffer.Bytes())
+		header.Set("Content-Type", contentType)
+	}
+	if !s.isCompressibleContentType(contentType) {
+		return
+	}
+	addCompressVaryHeader(header)
+	encoding, f := s.getCompressEncoder(r)
+	if f == nil {
+		return
+	}
+	buffer := bytes.NewBuffer(nil)
+	encoder, err := f(buffer, s.config.CompressLevel)
+	if err != nil {
+		s.Logger().Ctx(r.Context()).Error(err)
+		return
+	}
+	if _, err = encoder.Write(response.buffer.Bytes()); err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		s.Logger().Ctx(r.Context()).Error(err)
+		return
+	}
+	// It makes no sense if the compressed content is larger.
+	if buffer.Len() >= response.buffer.Len() {
+		return
+	}
+	header.Set("Content-Encoding", encoding)
+	header.Set("Content-Length", gconv.String(buffer.Len()))
+	response.buffer = buffer
+}
+
+// serveFileContent serves the file `content` with `info` of static file `f` to client.
+// If compression is enabled, it serves the precompressed file like "xxx.gz" if it exists and
+// is accepted by client, or else compresses the file content if it is compressible.
+func (s *Server) serveFileContent(r *Request, f *staticFile, info os.FileInfo, content io.ReadSeeker) {
+	var (
+		writer = r.Response.Writer.RawWriter()
+		header = writer.Header()
+	)
+	r.Response.wroteHeader = true
+	if !s.config.CompressEnabled || r.Header.Get("Range") != "" {
+		http.ServeContent(writer, r.Request, info.Name(), info.ModTime(), content)
+		return
+	}
+	// Precompressed static file.
+	for _, encoding := range getAcceptedEncodings(r.Header.Get("Accept-Encoding")) {
+		ext, ok := compressFileExtensions[encoding]
+		if !ok {
+			continue
+		}
+		file, fileInfo, closer := searchPrecompressedFile(f, ext)
+		if file == nil {
+			continue
+		}
+		defer closer.Close()
+		if header.Get("Content-Type") == "" {
+			contentType := mime.TypeByExtension(filepath.Ext(info.Name()))
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+			header.Set("Content-Type", contentType)
+		}
+		header.Set("Content-Encoding", encoding)
+		addCompressVaryHeader(header)
+		http.ServeContent(writer, r.Request, info.Name(), fileInfo.ModTime(), file)
+		return
+	}
+	// Compressing the file content.
+	if info.Size() >= s.config.CompressMinSize {
+		if encoding, newFunc := s.getCompressEncoder(r); newFunc != nil {
+			w := &compressWriter{
+				ResponseWriter: writer,
+				server:         s,
+				encoding:       encoding,
+				newFunc:        newFunc,
+			}
+			defer w.Close()
+			http.ServeContent(w, r.Request, info.Name(), info.ModTime(), content)
+			return
+		}
+	}
+	http.ServeContent(writer, r.Request, info.Name(), info.ModTime(), content)
+}
+
+// searchPrecompressedFile searches and opens the precompressed file of `f` with extension `ext`.
+// It returns nil if the precompressed file does not exist.
+func searchPrecompressedFile(f *staticFile, ext string) (io.ReadSeeker, os.FileInfo, io.Closer) {
+	if f.File != nil {
+		if file := gres.Get(f.File.Name() + ext); file != nil && !file.FileInfo().IsDir() {
+			return file, file.FileInfo(), file
+		}
+		return nil, nil, nil
+	}
+	if f.Path == "" || !gfile.IsFile(f.Path+ext) {
+		return nil, nil, nil
+	}
+	file, err := os.Open(f.Path + ext)
+	if err != nil {
+		return nil, nil, nil
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, nil
+	}
+	return file, info, file
+}
+
+// getCompressEncoder returns the registered encoder accepted by the client of `r`,
+// which is chosen by the quality values of "Accept-Encoding" header and the order of
+// CompressEncodings configuration.
+func (s *Server) getCompressEncoder(r *Request) (string, CompressEncoderFunc) {
+	for _, encoding := range getAcceptedEncodings(r.Header.Get("Accept-Encoding"), s.config.CompressEncodings...) {
+		if v := compressEncoderMap.Get(encoding); v != nil {
+			return encoding, v.(CompressEncoderFunc)
+		}
+	}
+	return "", nil
+}
+
+// isCompressibleContentType checks whether `contentType` matches CompressContentTypes of configuration,
+// the item of which can be a wildcard type like: "text/*".
+func (s *Server) isCompressibleContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	if mediaType == "" {
+		return false
+	}
+	for _, item := range s.config.CompressContentTypes {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if strings.HasSuffix(item, "/*") {
+			if strings.HasPrefix(mediaType, item[:len(item)-1]) {
+				return true
+			}
+		} else if mediaType == item {
+			return true
+		}
+	}
+	return false
+}
+
+// getAcceptedEncodings parses and returns the content encodings accepted by the client from
+// "Accept-Encoding" header `accept`, which are ordered by their quality values descending.
+// The optional parameter `preferred` specifies the order of encodings having the same quality value,
+// and the encodings matched by wildcard "*".
+func getAcceptedEncodings(accept string, preferred ...string) []string {
+	type acceptItem struct {
+		encoding string
+		quality  float64
+		index    int
+	}
+	var (
+		items     = make([]acceptItem, 0)
+		excluded  = make(map[string]struct{})
+		anyItem   *acceptItem
+		getWeight = func(encoding string) int {
+			for i, v := range preferred {
+				if strings.EqualFold(v, encoding) {
+					return i
+				}
+			}
+			return len(preferred)
+		}
+	)
+	for _, part := range strings.Split(accept, ",") {
+		var (
+			array    = strings.Split(part, ";")
+			encoding = strings.ToLower(strings.TrimSpace(array[0]))
+			quality  = 1.0
+		)
+		if encoding == "" || encoding == compressEncodingIdentity {
+			continue
+		}
+		for _, param := range array[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				quality = gconv.Float64(param[2:])
+			}
+		}
+		if quality <= 0 {
+			excluded[encoding] = struct{}{}
+			continue
+		}
+		item := acceptItem{encoding: encoding, quality: quality, index: getWeight(encoding)}
+		if encoding == compressEncodingAny {
+			anyItem = &item
+			continue
+		}
+		items = append(items, item)
+	}
+	// Wildcard "*" matches the preferred encodings that are not specified.
+	if anyItem != nil {
+		for i, encoding := range preferred {
+			encoding = strings.ToLower(encoding)
+			if _, ok := excluded[encoding]; ok {
+				continue
+			}
+			found := false
+			for _, item := range items {
+				if item.encoding == encoding {
+					found = true
+					break
+				}
+			}
+			if !found {
+				items = append(items, acceptItem{encoding: encoding, quality: anyItem.quality, index: i})
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].quality != items[j].quality {
+			return items[i].quality > items[j].quality
+		}
+		return items[i].index < items[j].index
+	})
+	encodings := make([]string, len(items))
+	for i, item := range items {
+		encodings[i] = item.encoding
+	}
+	return encodings
+}
+
+// isCompressibleStatus checks whether the response of HTTP status `status` can be compressed.
+func isCompressibleStatus(status int) bool {
+	switch {
+	case status == 0:
+		return true
+	case status < http.StatusOK, status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
+
+// addCompressVaryHeader adds "Accept-Encoding" to header "Vary" if it is not added.
+func addCompressVaryHeader(header http.Header) {
+	for _, value := range header.Values("Vary") {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), compressVaryHeader) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", compressVaryHeader)
+}
+
+// WriteHeader implements the interface function of http.ResponseWriter.WriteHeader.
+// It creates the compressing writer if the content of status `status` is compressible.
+func (w *compressWriter) WriteHeader(status int) {
+	header := w.Header()
+	if status == http.StatusOK &&
+		header.Get("Content-Encoding") == "" &&
+		w.server.isCompressibleContentType(header.Get("Content-Type")) {
+		addCompressVaryHeader(header)
+		if encoder, err := w.newFunc(w.ResponseWriter, w.server.config.CompressLevel); err == nil {
+			w.encoder = encoder
+			header.Set("Content-Encoding", w.encoding)
+			header.Del("Content-Length")
+		} else {
+			w.server.Logger().Error(err)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements the interface function of http.ResponseWriter.Write.
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Close closes the compressing writer, which flushes the remaining compressed content to client.
+func (w *compressWriter) Close() error {
+	if w.encoder != nil {
+		return w.encoder.Close()
+	}
+	return nil
+}
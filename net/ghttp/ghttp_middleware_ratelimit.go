@@ -0,0 +1,245 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gogf/gf/container/gmap"
+	"github.com/gogf/gf/container/gtype"
+	"github.com/gogf/gf/util/gconv"
+)
+
+// RateLimitStore is the interface for storing the counters of rate limiting,
+// which can be implemented using memory, redis, etc.
+type RateLimitStore interface {
+	// Take takes one request from counter `key` which allows `limit` requests in `period`
+	// using rate limiting algorithm `algorithm`, and returns the limiting result.
+	Take(ctx context.Context, key string, limit int, period time.Duration, algorithm string) (*RateLimitResult, error)
+}
+
+// RateLimitResult is the result of taking one request from rate limiting counter.
+type RateLimitResult struct {
+	Allowed    bool          // Whether the request is allowed.
+	Limit      int           // Max requests allowed in the period.
+	Remaining  int           // Remaining requests allowed currently.
+	Reset      time.Duration // Duration until the counter is fully reset.
+	RetryAfter time.Duration // Duration after which the request can be retried if it's not allowed.
+}
+
+// RateLimitConfig is the configuration for MiddlewareRateLimit.
+type RateLimitConfig struct {
+	Name      string                  // Name distinguishes the counters of limiters with the same configuration, optional.
+	Limit     int                     // Max requests allowed in Period.
+	Period    time.Duration           // Period of the limit, which is 1 second in default.
+	Algorithm string                  // Rate limiting algorithm, which is RateLimitTokenBucket in default.
+	KeyFunc   func(r *Request) string // Key of the client, which is RateLimitKeyByIp in default. Empty key skips limiting.
+	Store     RateLimitStore          // Store of the counters, which is a memory store in default.
+	Handler   HandlerFunc             // Handler for rejected request, which writes status 429 in default.
+}
+
+const (
+	// RateLimitTokenBucket is the token bucket algorithm, which allows bursting of Limit requests,
+	// and refills the tokens at rate Limit/Period.
+	RateLimitTokenBucket = "token-bucket"
+
+	// RateLimitSlidingWindow is the sliding window algorithm, which allows Limit requests in any
+	// window of Period, approximated using the counters of current and previous window.
+	RateLimitSlidingWindow = "sliding-window"
+)
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+	headerRetryAfter         = "Retry-After"
+)
+
+// MiddlewareRateLimit creates and returns a middleware that limits the request rate of each client
+// using `config`. Each route has its own counters if the middleware is bound to router group,
+// or else all routes share the same counters if it's bound globally using Server.Use.
+// It sets headers "X-RateLimit-Limit", "X-RateLimit-Remaining" and "X-RateLimit-Reset" for
+// each request, and rejects the request with header "Retry-After" if it exceeds the limit.
+func MiddlewareRateLimit(config RateLimitConfig) HandlerFunc {
+	if config.Limit <= 0 {
+		panic(fmt.Sprintf(`invalid rate limit "%d", which should be greater than 0`, config.Limit))
+	}
+	if config.Period <= 0 {
+		config.Period = time.Second
+	}
+	if config.Algorithm == "" {
+		config.Algorithm = RateLimitTokenBucket
+	}
+	if config.Algorithm != RateLimitTokenBucket && config.Algorithm != RateLimitSlidingWindow {
+		panic(fmt.Sprintf(`invalid rate limit algorithm "%s"`, config.Algorithm))
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = RateLimitKeyByIp
+	}
+	if config.Store == nil {
+		config.Store = NewRateLimitStoreMemory()
+	}
+	counterPrefix := fmt.Sprintf(`%s:%s:%d/%d`, config.Name, config.Algorithm, config.Limit, config.Period.Milliseconds())
+	return func(r *Request) {
+		key := config.KeyFunc(r)
+		if key == "" {
+			r.Middleware.Next()
+			return
+		}
+		result, err := config.Store.Take(
+			r.Context(),
+			fmt.Sprintf(`%s:%s:%s`, counterPrefix, getRouteKey(r), key),
+			config.Limit,
+			config.Period,
+			config.Algorithm,
+		)
+		// It does not block the request if the store fails.
+		if err != nil {
+			r.Server.Logger().Ctx(r.Context()).Error(err)
+			r.Middleware.Next()
+			return
+		}
+		header := r.Response.Header()
+		header.Set(headerRateLimitLimit, gconv.String(result.Limit))
+		header.Set(headerRateLimitRemaining, gconv.String(result.Remaining))
+		header.Set(headerRateLimitReset, gconv.String(durationToSeconds(result.Reset)))
+		if !result.Allowed {
+			header.Set(headerRetryAfter, gconv.String(durationToSeconds(result.RetryAfter)))
+			if config.Handler != nil {
+				config.Handler(r)
+			} else {
+				r.Response.WriteStatus(http.StatusTooManyRequests)
+			}
+			return
+		}
+		r.Middleware.Next()
+	}
+}
+
+// MiddlewareConcurrencyLimit creates and returns a middleware that limits the concurrent in-flight
+// requests to `max`. Each route has its own limit if the middleware is bound to router group,
+// or else all routes share the same limit if it's bound globally using Server.Use.
+// The request exceeding the limit is rejected with status 503 in default, or handled by
+// the optional `handler`.
+func MiddlewareConcurrencyLimit(max int, handler ...HandlerFunc) HandlerFunc {
+	if max <= 0 {
+		panic(fmt.Sprintf(`invalid concurrency limit "%d", which should be greater than 0`, max))
+	}
+	counters := gmap.NewStrAnyMap(true)
+	return func(r *Request) {
+		counter := counters.GetOrSetFuncLock(getRouteKey(r), func() interface{} {
+			return gtype.NewInt()
+		}).(*gtype.Int)
+		defer counter.Add(-1)
+		if counter.Add(1) > max {
+			r.Response.Header().Set(headerRetryAfter, "1")
+			if len(handler) > 0 && handler[0] != nil {
+				handler[0](r)
+			} else {
+				r.Response.WriteStatus(http.StatusServiceUnavailable)
+			}
+			return
+		}
+		r.Middleware.Next()
+	}
+}
+
+// RateLimitKeyByIp uses the client ip as the key of rate limiting.
+func RateLimitKeyByIp(r *Request) string {
+	return r.GetClientIp()
+}
+
+// RateLimitKeyBySession uses the session id as the key of rate limiting.
+// It uses the client ip if the client has no session id.
+func RateLimitKeyBySession(r *Request) string {
+	if id := r.GetSessionId(); id != "" {
+		return id
+	}
+	return r.GetClientIp()
+}
+
+// RateLimitKeyByHeader returns a key function using the value of header `name` as the key
+// of rate limiting, like the api key of the client. It uses the client ip if the header is empty.
+func RateLimitKeyByHeader(name string) func(r *Request) string {
+	return func(r *Request) string {
+		if v := r.GetHeader(name); v != "" {
+			return v
+		}
+		return r.GetClientIp()
+	}
+}
+
+// getRouteKey returns the key of the router serving current request, which is used for
+// per-route counters.
+func getRouteKey(r *Request) string {
+	if r.Router == nil {
+		return ""
+	}
+	return r.Router.Method + ":" + r.Router.Uri + "@" + r.Router.Domain
+}
+
+// newTokenBucketResult creates and returns the result of token bucket algorithm,
+// in which `tokens` is the remaining tokens after taking.
+func newTokenBucketResult(allowed bool, limit int, period time.Duration, tokens float64) *RateLimitResult {
+	var (
+		interval = float64(period) / float64(limit) // Interval filling one token.
+		result   = &RateLimitResult{
+			Allowed:   allowed,
+			Limit:     limit,
+			Remaining: int(math.Floor(tokens)),
+			Reset:     time.Duration((float64(limit) - tokens) * interval),
+		}
+	)
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * interval)
+	}
+	return result
+}
+
+// newSlidingWindowResult creates and returns the result of sliding window algorithm,
+// in which `previous` and `current` are the counts of previous and current window after taking,
+// and `elapsed` is the elapsed duration of current window.
+func newSlidingWindowResult(allowed bool, limit int, period time.Duration, previous, current int64, elapsed time.Duration) *RateLimitResult {
+	var (
+		weight = float64(period-elapsed) / float64(period)
+		count  = float64(previous)*weight + float64(current)
+		result = &RateLimitResult{
+			Allowed:   allowed,
+			Limit:     limit,
+			Remaining: int(math.Max(0, math.Floor(float64(limit)-count))),
+			Reset:     period - elapsed,
+		}
+	)
+	if current > 0 {
+		result.Reset += period
+	}
+	if !allowed {
+		// Waiting for the weighted count of previous window decreasing enough,
+		// or else the next window.
+		result.RetryAfter = period - elapsed
+		if previous > 0 && current < int64(limit) {
+			// previous*(period-elapsed-wait)/period + current + 1 <= limit.
+			wait := float64(period-elapsed) - float64(int64(limit)-current-1)*float64(period)/float64(previous)
+			if wait < float64(result.RetryAfter) {
+				result.RetryAfter = time.Duration(math.Max(0, wait))
+			}
+		}
+	}
+	return result
+}
+
+// durationToSeconds converts `d` to seconds rounding up, which is used for HTTP headers.
+func durationToSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(d.Seconds()))
+}
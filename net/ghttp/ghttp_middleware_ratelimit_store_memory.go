@@ -0,0 +1,96 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/os/gcache"
+)
+
+// RateLimitStoreMemory implements the RateLimitStore interface with memory,
+// which is used for single instance deployment.
+type RateLimitStoreMemory struct {
+	cache *gcache.Cache // Cache for counters, which automatically removes the expired ones.
+}
+
+// rateLimitMemoryCounter is the counter for RateLimitStoreMemory.
+type rateLimitMemoryCounter struct {
+	mu       sync.Mutex
+	tokens   float64   // Remaining tokens for token bucket algorithm.
+	previous int64     // Count of previous window for sliding window algorithm.
+	current  int64     // Count of current window for sliding window algorithm.
+	window   int64     // Index of current window for sliding window algorithm.
+	time     time.Time // Last updating time for token bucket algorithm.
+}
+
+// NewRateLimitStoreMemory creates and returns a memory store for rate limiting.
+func NewRateLimitStoreMemory() *RateLimitStoreMemory {
+	return &RateLimitStoreMemory{
+		cache: gcache.New(),
+	}
+}
+
+// Take implements the interface function RateLimitStore.Take.
+func (s *RateLimitStoreMemory) Take(ctx context.Context, key string, limit int, period time.Duration, algorithm string) (*RateLimitResult, error) {
+	// The counter is fully reset after two periods in any algorithm.
+	ttl := 2 * period
+	v, err := s.cache.Ctx(ctx).GetOrSetFuncLock(key, func() (interface{}, error) {
+		return &rateLimitMemoryCounter{
+			tokens: float64(limit),
+			time:   time.Now(),
+		}, nil
+	}, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.cache.Ctx(ctx).UpdateExpire(key, ttl); err != nil {
+		return nil, err
+	}
+	counter := v.(*rateLimitMemoryCounter)
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+	now := time.Now()
+	switch algorithm {
+	case RateLimitTokenBucket:
+		counter.tokens = math.Min(
+			float64(limit),
+			counter.tokens+float64(now.Sub(counter.time))*float64(limit)/float64(period),
+		)
+		counter.time = now
+		allowed := counter.tokens >= 1
+		if allowed {
+			counter.tokens--
+		}
+		return newTokenBucketResult(allowed, limit, period, counter.tokens), nil
+
+	case RateLimitSlidingWindow:
+		window := now.UnixNano() / int64(period)
+		switch window {
+		case counter.window:
+		case counter.window + 1:
+			counter.previous, counter.current = counter.current, 0
+		default:
+			counter.previous, counter.current = 0, 0
+		}
+		counter.window = window
+		var (
+			elapsed = time.Duration(now.UnixNano() - window*int64(period))
+			weight  = float64(period-elapsed) / float64(period)
+			allowed = float64(counter.previous)*weight+float64(counter.current)+1 <= float64(limit)
+		)
+		if allowed {
+			counter.current++
+		}
+		return newSlidingWindowResult(allowed, limit, period, counter.previous, counter.current, elapsed), nil
+	}
+	return nil, gerror.Newf(`invalid rate limit algorithm "%s"`, algorithm)
+}
@@ -0,0 +1,134 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/database/gredis"
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/util/gconv"
+)
+
+// RateLimitStoreRedis implements the RateLimitStore interface with redis,
+// which shares the counters among multiple instances of the server.
+type RateLimitStoreRedis struct {
+	redis  *gredis.Redis // Redis client for counters.
+	prefix string        // Redis key prefix for counters.
+}
+
+const (
+	// rateLimitRedisTokenBucketScript takes one token from the bucket stored as hash.
+	// KEYS[1]: bucket key. ARGV: limit, period in milliseconds, current time in milliseconds.
+	// It returns whether the request is allowed and the remaining tokens.
+	rateLimitRedisTokenBucketScript = `
+local limit  = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local now    = tonumber(ARGV[3])
+local data   = redis.call('HMGET', KEYS[1], 'tokens', 'time')
+local tokens = tonumber(data[1])
+local last   = tonumber(data[2])
+if tokens == nil or last == nil then
+	tokens = limit
+	last   = now
+end
+if now > last then
+	tokens = math.min(limit, tokens + (now - last) * limit / period)
+	last   = now
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens  = tokens - 1
+	allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'time', tostring(last))
+redis.call('PEXPIRE', KEYS[1], period * 2)
+return {allowed, tostring(tokens)}
+`
+
+	// rateLimitRedisSlidingWindowScript increases the count of current window if the weighted count
+	// of current and previous window does not exceed the limit.
+	// KEYS[1]: counter key prefix. ARGV: limit, period in milliseconds, current time in milliseconds.
+	// It returns whether the request is allowed, the counts of previous and current window, and the
+	// elapsed milliseconds of current window.
+	rateLimitRedisSlidingWindowScript = `
+local limit    = tonumber(ARGV[1])
+local period   = tonumber(ARGV[2])
+local now      = tonumber(ARGV[3])
+local window   = math.floor(now / period)
+local key      = KEYS[1] .. ':' .. window
+local previous = tonumber(redis.call('GET', KEYS[1] .. ':' .. (window - 1)) or '0')
+local current  = tonumber(redis.call('GET', key) or '0')
+local elapsed  = now - window * period
+local allowed  = 0
+if previous * (period - elapsed) / period + current + 1 <= limit then
+	current = redis.call('INCR', key)
+	redis.call('PEXPIRE', key, period * 2)
+	allowed = 1
+end
+return {allowed, previous, current, elapsed}
+`
+)
+
+// NewRateLimitStoreRedis creates and returns a redis store for rate limiting.
+// The optional parameter `prefix` specifies the key prefix of counters, which is "ratelimit:" in default.
+func NewRateLimitStoreRedis(redis *gredis.Redis, prefix ...string) *RateLimitStoreRedis {
+	if redis == nil {
+		panic("redis instance for rate limit store cannot be empty")
+	}
+	s := &RateLimitStoreRedis{
+		redis:  redis,
+		prefix: "ratelimit:",
+	}
+	if len(prefix) > 0 && prefix[0] != "" {
+		s.prefix = prefix[0]
+	}
+	return s
+}
+
+// Take implements the interface function RateLimitStore.Take.
+func (s *RateLimitStoreRedis) Take(ctx context.Context, key string, limit int, period time.Duration, algorithm string) (*RateLimitResult, error) {
+	var (
+		script   string
+		periodMs = period.Milliseconds()
+		nowMs    = time.Now().UnixNano() / int64(time.Millisecond)
+	)
+	if periodMs <= 0 {
+		periodMs = 1
+	}
+	switch algorithm {
+	case RateLimitTokenBucket:
+		script = rateLimitRedisTokenBucketScript
+	case RateLimitSlidingWindow:
+		script = rateLimitRedisSlidingWindowScript
+	default:
+		return nil, gerror.Newf(`invalid rate limit algorithm "%s"`, algorithm)
+	}
+	v, err := s.redis.Ctx(ctx).DoVar("EVAL", script, 1, s.prefix+key, limit, periodMs, nowMs)
+	if err != nil {
+		return nil, err
+	}
+	values := v.Strings()
+	if algorithm == RateLimitTokenBucket {
+		if len(values) != 2 {
+			return nil, gerror.Newf(`invalid rate limit result from redis: %v`, values)
+		}
+		return newTokenBucketResult(values[0] == "1", limit, period, gconv.Float64(values[1])), nil
+	}
+	if len(values) != 4 {
+		return nil, gerror.Newf(`invalid rate limit result from redis: %v`, values)
+	}
+	return newSlidingWindowResult(
+		values[0] == "1",
+		limit,
+		period,
+		gconv.Int64(values[1]),
+		gconv.Int64(values[2]),
+		time.Duration(gconv.Int64(values[3]))*time.Millisecond,
+	), nil
+}
@@ -213,6 +213,18 @@ func (r *Request) GetSessionId() string {
 	return id
 }
 
+// GetLastEventId retrieves and returns the last event id of Server-Sent Events from header "Last-Event-ID",
+// which is sent by the client when it reconnects, and can be used for resuming the events.
+// It also retrieves it from parameter "lastEventId" if the header is empty,
+// which is commonly used by EventSource polyfills.
+func (r *Request) GetLastEventId() string {
+	id := r.Header.Get("Last-Event-ID")
+	if id == "" {
+		id = r.GetQueryString("lastEventId")
+	}
+	return id
+}
+
 // GetReferer returns referer of this request.
 func (r *Request) GetReferer() string {
 	return r.Header.Get("Referer")
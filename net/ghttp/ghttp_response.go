@@ -24,6 +24,8 @@ type Response struct {
 	Server          *Server         // Parent server.
 	Writer          *ResponseWriter // Alias of ResponseWriter.
 	Request         *Request        // According request.
+	compress        bool            // Whether compressing the content, which is enabled by MiddlewareCompress.
+	sse             *SSE            // Server-Sent Events writer, which is created by SSE.
 }
 
 // newResponse creates and returns a new Response object.
@@ -0,0 +1,170 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/internal/json"
+	"github.com/gogf/gf/util/gconv"
+)
+
+// SSE is the writer for Server-Sent Events, which writes and flushes the events to client immediately.
+type SSE struct {
+	mu       sync.Mutex
+	response *Response
+	closed   bool          // Whether the writer is closed.
+	done     chan struct{} // Closed when the writer is closed, which stops the keep-alive goroutine.
+}
+
+// SSE sets the headers for Server-Sent Events and returns a writer sending events to client.
+// The writer is automatically closed after the handler returns, and any sending after that fails.
+// It's commonly used like:
+// sse := r.Response.SSE(); for ... { if err := sse.Send("message", id, data); err != nil { return } }
+func (r *Response) SSE() *SSE {
+	if r.sse != nil {
+		return r.sse
+	}
+	header := r.Header()
+	header.Set("Content-Type", "text/event-stream; charset=utf-8")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Disable the buffering of proxy like nginx.
+	header.Set("X-Accel-Buffering", "no")
+	header.Del("Content-Length")
+	r.ClearBuffer()
+	r.WriteHeader(http.StatusOK)
+	r.sse = &SSE{
+		response: r,
+		done:     make(chan struct{}),
+	}
+	r.sse.flush()
+	return r.sse
+}
+
+// Send sends an event with name `event`, id `id` and data `data` to client.
+// The parameters `event` and `id` are ignored if they are empty, in which case the event name is
+// "message" at client side. The parameter `data` is sent as it is if it's type of string or []byte,
+// or else it's encoded as JSON.
+// It returns error if the client is disconnected or the writer is closed.
+func (s *SSE) Send(event, id string, data interface{}) error {
+	var content string
+	switch v := data.(type) {
+	case string:
+		content = v
+	case []byte:
+		content = string(v)
+	default:
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		content = string(b)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if id != "" {
+		buffer.WriteString("id: " + sseSanitize(id) + "\n")
+	}
+	if event != "" {
+		buffer.WriteString("event: " + sseSanitize(event) + "\n")
+	}
+	content = strings.Replace(content, "\r\n", "\n", -1)
+	for _, line := range strings.Split(content, "\n") {
+		buffer.WriteString("data: " + line + "\n")
+	}
+	buffer.WriteString("\n")
+	return s.write(buffer.Bytes())
+}
+
+// Retry sends the reconnection time `retry` to client, which is used by client reconnecting
+// after the connection is lost.
+func (s *SSE) Retry(retry time.Duration) error {
+	return s.write([]byte("retry: " + gconv.String(retry.Milliseconds()) + "\n\n"))
+}
+
+// Comment sends comment `comment` to client, which is ignored by client
+// and commonly used for keeping the connection alive.
+func (s *SSE) Comment(comment string) error {
+	buffer := bytes.NewBuffer(nil)
+	for _, line := range strings.Split(strings.Replace(comment, "\r\n", "\n", -1), "\n") {
+		buffer.WriteString(": " + line + "\n")
+	}
+	buffer.WriteString("\n")
+	return s.write(buffer.Bytes())
+}
+
+// KeepAlive sends keep-alive comment to client in every `interval` in background,
+// which prevents the connection being closed by proxies for idle timeout.
+// It stops when the client is disconnected or the writer is closed.
+func (s *SSE) KeepAlive(interval time.Duration) *SSE {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Comment("keep-alive"); err != nil {
+					return
+				}
+			case <-s.done:
+				return
+			case <-s.response.Request.Context().Done():
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// Done returns a channel that's closed when the client is disconnected.
+func (s *SSE) Done() <-chan struct{} {
+	return s.response.Request.Context().Done()
+}
+
+// Close closes the writer, which stops the keep-alive goroutine.
+// It's automatically called after the handler returns.
+func (s *SSE) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+}
+
+// write writes `content` to the response and flushes it to client.
+func (s *SSE) write(content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return gerror.New("sse writer is closed")
+	}
+	if err := s.response.Request.Context().Err(); err != nil {
+		return err
+	}
+	s.response.buffer.Write(content)
+	s.flush()
+	return nil
+}
+
+// flush outputs the buffer content to client through the underlying http.Flusher.
+func (s *SSE) flush() {
+	s.response.Flush()
+	if flusher, ok := s.response.RawWriter().(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// sseSanitize removes the line breaks from field value `s`, which are not allowed in event name and id.
+func sseSanitize(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
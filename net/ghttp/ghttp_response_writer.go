@@ -64,6 +64,8 @@ func (w *ResponseWriter) Flush() {
 		w.buffer.WriteString(http.StatusText(w.Status))
 	}
 	if w.buffer.Len() > 0 {
+		// The header is implicitly written along with the content.
+		w.wroteHeader = true
 		w.writer.Write(w.buffer.Bytes())
 		w.buffer.Reset()
 	}
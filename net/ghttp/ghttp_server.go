@@ -157,6 +157,9 @@ func (s *Server) Start() error {
 		s.EnablePProf(s.config.PProfPattern)
 	}
 
+	// OpenAPI feature.
+	s.bindOpenApiHandlers()
+
 	// Default HTTP handler.
 	if s.config.Handler == nil {
 		s.config.Handler = s
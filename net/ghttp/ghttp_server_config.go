@@ -218,6 +218,69 @@ type ServerConfig struct {
 
 	// Graceful enables graceful reload feature for all servers of the process.
 	Graceful bool `json:"graceful"`
+
+	// HandlerResponse specifies the function writing the result or error of typed handlers
+	// to response. It writes DefaultHandlerResponse as JSON in default.
+	HandlerResponse HandlerResponseFunc `json:"-"`
+
+	// HandlerErrorStatus specifies the function mapping the error of typed handlers to HTTP
+	// status code for the default HandlerResponse. It uses DefaultHandlerErrorStatus in default.
+	HandlerErrorStatus HandlerErrorStatusFunc `json:"-"`
+
+	// ==================================
+	// OpenAPI.
+	// ==================================
+
+	// OpenApiPath specifies the URI path serving the OpenAPI document of the server, like: /api.json.
+	// The document is served in YAML format if the path has extension ".yaml" or ".yml".
+	OpenApiPath string `json:"openapiPath"`
+
+	// SwaggerPath specifies the URI path serving the Swagger UI page for the OpenAPI document.
+	// The document is served at "/api.json" if OpenApiPath is not specified.
+	SwaggerPath string `json:"swaggerPath"`
+
+	// SwaggerAssetsUrl specifies the base URL of the Swagger UI assets "swagger-ui.css" and "swagger-ui-bundle.js",
+	// which is the public CDN "https://unpkg.com/swagger-ui-dist@3" in default, so the page requires the
+	// browser's access to the CDN. It can be set to a local path serving the files of package swagger-ui-dist,
+	// like "/swagger-ui" served by static file serving or resource manager, for the deployment without the access.
+	// Note that the assets are not bundled in the framework, which should be provided by the application.
+	SwaggerAssetsUrl string `json:"swaggerAssetsUrl"`
+
+	// OpenApiTitle specifies the title of the OpenAPI document, which is the server name in default.
+	OpenApiTitle string `json:"openapiTitle"`
+
+	// OpenApiDescription specifies the description of the OpenAPI document.
+	OpenApiDescription string `json:"openapiDescription"`
+
+	// OpenApiVersion specifies the API version of the OpenAPI document, which is "1.0.0" in default.
+	OpenApiVersion string `json:"openapiVersion"`
+
+	// ==================================
+	// Compression.
+	// ==================================
+
+	// CompressEnabled enables compressing the response content of all routes and static files
+	// according to the "Accept-Encoding" header of client. Use MiddlewareCompress instead
+	// if only some of the routes need compression.
+	// It also serves the precompressed static file like "xxx.gz" if it exists.
+	CompressEnabled bool `json:"compressEnabled"`
+
+	// CompressLevel specifies the compression level, which is -1 (default level) in default.
+	CompressLevel int `json:"compressLevel"`
+
+	// CompressMinSize specifies the min size in bytes of the content to be compressed.
+	// It can be configured in configuration file using string like: 1k, 10kb etc.
+	// It's 1KB in default.
+	CompressMinSize int64 `json:"compressMinSize"`
+
+	// CompressContentTypes specifies the content types that can be compressed,
+	// the item of which can be a wildcard type like: "text/*".
+	CompressContentTypes []string `json:"compressContentTypes"`
+
+	// CompressEncodings specifies the content encodings in preference order for compression,
+	// which is used if the client accepts multiple encodings with the same quality value.
+	// The encoding "br" is used only if it's registered using RegisterCompressEncoder.
+	CompressEncodings []string `json:"compressEncodings"`
 }
 
 // Deprecated. Use NewConfig instead.
@@ -265,6 +328,21 @@ func NewConfig() ServerConfig {
 		FormParsingMemory:   1024 * 1024,     // 1MB
 		Rewrites:            make(map[string]string),
 		Graceful:            false,
+		CompressLevel:       -1,
+		CompressMinSize:     1024, // 1KB
+		CompressContentTypes: []string{
+			"text/*",
+			"application/json",
+			"application/javascript",
+			"application/x-javascript",
+			"application/xml",
+			"application/xhtml+xml",
+			"application/rss+xml",
+			"application/atom+xml",
+			"application/wasm",
+			"image/svg+xml",
+		},
+		CompressEncodings: []string{"br", "gzip", "deflate"},
 	}
 }
 
@@ -295,6 +373,9 @@ func (s *Server) SetConfigWithMap(m map[string]interface{}) error {
 	if k, v := gutil.MapPossibleItemByKey(m, "FormParsingMemory"); k != "" {
 		m[k] = gfile.StrToSize(gconv.String(v))
 	}
+	if k, v := gutil.MapPossibleItemByKey(m, "CompressMinSize"); k != "" {
+		m[k] = gfile.StrToSize(gconv.String(v))
+	}
 	// Update the current configuration object.
 	// It only updates the configured keys not all the object.
 	if err := gconv.Struct(m, &s.config); err != nil {
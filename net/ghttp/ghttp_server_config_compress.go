@@ -0,0 +1,32 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+// SetCompressEnabled enables/disables the response compression for all routes and static files.
+func (s *Server) SetCompressEnabled(enabled bool) {
+	s.config.CompressEnabled = enabled
+}
+
+// SetCompressLevel sets the compression level for server.
+func (s *Server) SetCompressLevel(level int) {
+	s.config.CompressLevel = level
+}
+
+// SetCompressMinSize sets the min size in bytes of the content to be compressed.
+func (s *Server) SetCompressMinSize(size int64) {
+	s.config.CompressMinSize = size
+}
+
+// SetCompressContentTypes sets the content types that can be compressed, like: "text/*", "application/json".
+func (s *Server) SetCompressContentTypes(types ...string) {
+	s.config.CompressContentTypes = types
+}
+
+// SetCompressEncodings sets the content encodings in preference order for compression, like: "br", "gzip".
+func (s *Server) SetCompressEncodings(encodings ...string) {
+	s.config.CompressEncodings = encodings
+}
@@ -26,3 +26,15 @@ func (s *Server) SetClientMaxBodySize(maxSize int64) {
 func (s *Server) SetFormParsingMemory(maxMemory int64) {
 	s.config.FormParsingMemory = maxMemory
 }
+
+// SetHandlerResponse sets the HandlerResponse for server, which writes the result or error
+// of typed handlers to response.
+func (s *Server) SetHandlerResponse(f HandlerResponseFunc) {
+	s.config.HandlerResponse = f
+}
+
+// SetHandlerErrorStatus sets the HandlerErrorStatus for server, which maps the error
+// of typed handlers to HTTP status code.
+func (s *Server) SetHandlerErrorStatus(f HandlerErrorStatusFunc) {
+	s.config.HandlerErrorStatus = f
+}
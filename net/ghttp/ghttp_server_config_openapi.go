@@ -0,0 +1,31 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+// SetOpenApiPath sets the OpenApiPath for server, which serves the OpenAPI document of the server.
+func (s *Server) SetOpenApiPath(path string) {
+	s.config.OpenApiPath = path
+}
+
+// SetSwaggerPath sets the SwaggerPath for server, which serves the Swagger UI page for the OpenAPI document.
+func (s *Server) SetSwaggerPath(path string) {
+	s.config.SwaggerPath = path
+}
+
+// SetSwaggerAssetsUrl sets the SwaggerAssetsUrl for server, which is the base URL of the Swagger UI assets.
+func (s *Server) SetSwaggerAssetsUrl(url string) {
+	s.config.SwaggerAssetsUrl = url
+}
+
+// SetOpenApiInfo sets the title, version and optional description of the OpenAPI document.
+func (s *Server) SetOpenApiInfo(title, version string, description ...string) {
+	s.config.OpenApiTitle = title
+	s.config.OpenApiVersion = version
+	if len(description) > 0 {
+		s.config.OpenApiDescription = description[0]
+	}
+}
@@ -28,14 +28,14 @@ func (s *Server) Domain(domains string) *Domain {
 	return d
 }
 
-func (d *Domain) BindHandler(pattern string, handler HandlerFunc) {
+func (d *Domain) BindHandler(pattern string, handler interface{}) {
 	for domain, _ := range d.domains {
 		d.server.BindHandler(pattern+"@"+domain, handler)
 	}
 }
 
 func (d *Domain) doBindHandler(
-	pattern string, handler HandlerFunc,
+	pattern string, handler interface{},
 	middleware []HandlerFunc, source string,
 ) {
 	for domain, _ := range d.domains {
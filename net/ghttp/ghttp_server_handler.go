@@ -135,6 +135,11 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Close the Server-Sent Events writer, which should not write anything after serving.
+	if request.Response.sse != nil {
+		request.Response.sse.Close()
+	}
+
 	// HOOK - AfterServe
 	if !request.IsExited() {
 		s.callHookHandler(HookAfterServe, request)
@@ -176,6 +181,10 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	// Output the cookie content to client.
 	request.Cookie.Flush()
+	// Compress the buffer content if compression is enabled.
+	if s.config.CompressEnabled || request.Response.compress {
+		s.compressResponse(request)
+	}
 	// Output the buffer content to client.
 	request.Response.Flush()
 	// HOOK - AfterOutput
@@ -258,9 +267,7 @@ func (s *Server) serveFile(r *Request, f *staticFile, allowIndex ...bool) {
 				r.Response.WriteStatus(http.StatusForbidden)
 			}
 		} else {
-			info := f.File.FileInfo()
-			r.Response.wroteHeader = true
-			http.ServeContent(r.Response.Writer.RawWriter(), r.Request, info.Name(), info.ModTime(), f.File)
+			s.serveFileContent(r, f, f.File.FileInfo(), f.File)
 		}
 		return
 	}
@@ -284,8 +291,7 @@ func (s *Server) serveFile(r *Request, f *staticFile, allowIndex ...bool) {
 			r.Response.WriteStatus(http.StatusForbidden)
 		}
 	} else {
-		r.Response.wroteHeader = true
-		http.ServeContent(r.Response.Writer.RawWriter(), r.Request, info.Name(), info.ModTime(), file)
+		s.serveFileContent(r, f, info, file)
 	}
 }
 
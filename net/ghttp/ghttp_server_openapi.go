@@ -0,0 +1,683 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/encoding/gjson"
+	"github.com/gogf/gf/internal/json"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/os/gtime"
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/util/gconv"
+	"github.com/gogf/gf/util/gmeta"
+)
+
+type (
+	// OpenApi is the OpenAPI 3 document of the server.
+	OpenApi struct {
+		OpenApi    string                                  `json:"openapi"`
+		Info       OpenApiInfo                             `json:"info"`
+		Paths      map[string]map[string]*OpenApiOperation `json:"paths"`
+		Components OpenApiComponents                       `json:"components"`
+	}
+
+	// OpenApiInfo is the metadata of the API.
+	OpenApiInfo struct {
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+		Version     string `json:"version"`
+	}
+
+	// OpenApiOperation describes a single API operation on a path.
+	OpenApiOperation struct {
+		Tags        []string                    `json:"tags,omitempty"`
+		Summary     string                      `json:"summary,omitempty"`
+		Description string                      `json:"description,omitempty"`
+		Parameters  []*OpenApiParameter         `json:"parameters,omitempty"`
+		RequestBody *OpenApiRequestBody         `json:"requestBody,omitempty"`
+		Responses   map[string]*OpenApiResponse `json:"responses"`
+	}
+
+	// OpenApiParameter describes a single operation parameter.
+	OpenApiParameter struct {
+		Name        string         `json:"name"`
+		In          string         `json:"in"`
+		Description string         `json:"description,omitempty"`
+		Required    bool           `json:"required,omitempty"`
+		Schema      *OpenApiSchema `json:"schema,omitempty"`
+	}
+
+	// OpenApiRequestBody describes a single request body.
+	OpenApiRequestBody struct {
+		Required bool                         `json:"required,omitempty"`
+		Content  map[string]*OpenApiMediaType `json:"content"`
+	}
+
+	// OpenApiResponse describes a single response from an API operation.
+	OpenApiResponse struct {
+		Description string                       `json:"description"`
+		Content     map[string]*OpenApiMediaType `json:"content,omitempty"`
+	}
+
+	// OpenApiMediaType provides schema for the media type.
+	OpenApiMediaType struct {
+		Schema *OpenApiSchema `json:"schema"`
+	}
+
+	// OpenApiComponents holds the reusable schemas of the document.
+	OpenApiComponents struct {
+		Schemas map[string]*OpenApiSchema `json:"schemas,omitempty"`
+	}
+
+	// OpenApiSchema is the definition of input and output data types.
+	OpenApiSchema struct {
+		Ref                  string                    `json:"$ref,omitempty"`
+		Type                 string                    `json:"type,omitempty"`
+		Format               string                    `json:"format,omitempty"`
+		Description          string                    `json:"description,omitempty"`
+		Default              interface{}               `json:"default,omitempty"`
+		Enum                 []interface{}             `json:"enum,omitempty"`
+		Minimum              *float64                  `json:"minimum,omitempty"`
+		Maximum              *float64                  `json:"maximum,omitempty"`
+		MinLength            *int                      `json:"minLength,omitempty"`
+		MaxLength            *int                      `json:"maxLength,omitempty"`
+		Items                *OpenApiSchema            `json:"items,omitempty"`
+		Properties           map[string]*OpenApiSchema `json:"properties,omitempty"`
+		AdditionalProperties *OpenApiSchema            `json:"additionalProperties,omitempty"`
+		Required             []string                  `json:"required,omitempty"`
+	}
+)
+
+const (
+	openApiVersion           = "3.0.3"
+	defaultOpenApiVersion    = "1.0.0"
+	defaultOpenApiPath       = "/api.json"
+	openApiSchemaRefPrefix   = "#/components/schemas/"
+	openApiContentTypeJson   = "application/json"
+	openApiContentTypeYaml   = "application/yaml"
+	defaultSwaggerAssetsUrl  = "https://unpkg.com/swagger-ui-dist@3"
+	openApiAllMethods        = "GET,PUT,POST,DELETE,PATCH"
+	openApiBodyMethods       = "PUT,POST,PATCH"
+	openApiParamInPath       = "path"
+	openApiParamInQuery      = "query"
+	openApiParamInHeader     = "header"
+	openApiParamInCookie     = "cookie"
+	openApiTagDescription    = "dc"
+	openApiTagDescriptionAlt = "description"
+	openApiTagValidation     = "v"
+)
+
+var (
+	// Reflect types that are described as formatted string in document.
+	reflectTypeTime      = reflect.TypeOf(time.Time{})
+	reflectTypeGTime     = reflect.TypeOf(gtime.Time{})
+	reflectTypeGmetaMeta = reflect.TypeOf(gmeta.Meta{})
+)
+
+// GetOpenApi generates and returns the OpenAPI 3 document from the registered routes of the server.
+//
+// The operations of typed handlers are described using their request and response structs:
+// the attributes of request struct are described as parameters of path, header, cookie and query,
+// or JSON request body for methods PUT/POST/PATCH. The `v` tags of gvalid describe their required
+// and restricting rules, and the `dc` or `description` tags describe their descriptions.
+// The tags "summary", "description" and "tags" of the gmeta.Meta attribute of request struct describe
+// the operation.
+func (s *Server) GetOpenApi() *OpenApi {
+	if s.Status() != ServerStatusRunning {
+		s.handlePreBindItems()
+	}
+	oai := &OpenApi{
+		OpenApi: openApiVersion,
+		Info: OpenApiInfo{
+			Title:       s.config.OpenApiTitle,
+			Description: s.config.OpenApiDescription,
+			Version:     s.config.OpenApiVersion,
+		},
+		Paths: make(map[string]map[string]*OpenApiOperation),
+		Components: OpenApiComponents{
+			Schemas: make(map[string]*OpenApiSchema),
+		},
+	}
+	if oai.Info.Title == "" {
+		oai.Info.Title = s.name
+	}
+	if oai.Info.Version == "" {
+		oai.Info.Version = defaultOpenApiVersion
+	}
+	for _, item := range s.GetRouterArray() {
+		if !item.IsServiceHandler || item.handler.hookName != "" {
+			continue
+		}
+		if item.Route == s.getOpenApiPath() || item.Route == s.config.SwaggerPath {
+			continue
+		}
+		var (
+			path       = convertRouteToOpenApiPath(item.Route)
+			pathParams = getOpenApiPathParams(path)
+			methods    = []string{item.Method}
+		)
+		if item.Method == defaultMethod {
+			methods = strings.Split(openApiAllMethods, ",")
+		}
+		if _, ok := oai.Paths[path]; !ok {
+			oai.Paths[path] = make(map[string]*OpenApiOperation)
+		}
+		for _, method := range methods {
+			method = strings.ToLower(method)
+			if _, ok := oai.Paths[path][method]; ok {
+				continue
+			}
+			oai.Paths[path][method] = s.newOpenApiOperation(oai, item.handler, method, pathParams)
+		}
+	}
+	return oai
+}
+
+// ExportOpenApi generates and writes the OpenAPI 3 document of the server to file `path`.
+// The document is written in YAML format if `path` has extension ".yaml" or ".yml",
+// or else in JSON format.
+func (s *Server) ExportOpenApi(path string) error {
+	var (
+		oai     = s.GetOpenApi()
+		content []byte
+		err     error
+	)
+	if isOpenApiYamlPath(path) {
+		content, err = oai.ToYaml()
+	} else {
+		content, err = oai.ToJson()
+	}
+	if err != nil {
+		return err
+	}
+	return gfile.PutBytes(path, content)
+}
+
+// ToJson returns the document as indented JSON content.
+func (oai *OpenApi) ToJson() ([]byte, error) {
+	return json.MarshalIndent(oai, "", "    ")
+}
+
+// ToYaml returns the document as YAML content.
+func (oai *OpenApi) ToYaml() ([]byte, error) {
+	content, err := json.Marshal(oai)
+	if err != nil {
+		return nil, err
+	}
+	j, err := gjson.LoadContent(content)
+	if err != nil {
+		return nil, err
+	}
+	return j.ToYaml()
+}
+
+// bindOpenApiHandlers binds the handlers serving the OpenAPI document and Swagger UI if they are configured.
+func (s *Server) bindOpenApiHandlers() {
+	if s.config.OpenApiPath == "" && s.config.SwaggerPath == "" {
+		return
+	}
+	s.BindHandler(s.getOpenApiPath(), s.serveOpenApi)
+	if s.config.SwaggerPath != "" {
+		s.BindHandler(s.config.SwaggerPath, s.serveSwaggerUI)
+	}
+}
+
+// getOpenApiPath returns the path serving the OpenAPI document, which is OpenApiPath of configuration,
+// or the default path if only SwaggerPath is configured.
+func (s *Server) getOpenApiPath() string {
+	if s.config.OpenApiPath != "" {
+		return s.config.OpenApiPath
+	}
+	if s.config.SwaggerPath != "" {
+		return defaultOpenApiPath
+	}
+	return ""
+}
+
+// serveOpenApi is the handler serving the OpenAPI document.
+func (s *Server) serveOpenApi(r *Request) {
+	var (
+		oai         = s.GetOpenApi()
+		content     []byte
+		contentType = openApiContentTypeJson
+		err         error
+	)
+	if isOpenApiYamlPath(s.getOpenApiPath()) {
+		content, err = oai.ToYaml()
+		contentType = openApiContentTypeYaml
+	} else {
+		content, err = oai.ToJson()
+	}
+	if err != nil {
+		r.Response.WriteStatusExit(http.StatusInternalServerError, err.Error())
+	}
+	r.Response.Header().Set("Content-Type", contentType)
+	r.Response.Write(content)
+}
+
+// serveSwaggerUI is the handler serving the Swagger UI page for the OpenAPI document.
+// The assets of Swagger UI are loaded from configured SwaggerAssetsUrl, which is public CDN in default,
+// as the assets are not bundled in the framework.
+func (s *Server) serveSwaggerUI(r *Request) {
+	assetsUrl := strings.TrimRight(s.config.SwaggerAssetsUrl, "/")
+	if assetsUrl == "" {
+		assetsUrl = defaultSwaggerAssetsUrl
+	}
+	r.Response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	r.Response.Write(fmt.Sprintf(swaggerUITemplate, assetsUrl, assetsUrl, s.getOpenApiPath()))
+}
+
+// newOpenApiOperation creates and returns the operation of handler `handler` for `method`.
+func (s *Server) newOpenApiOperation(oai *OpenApi, handler *handlerItem, method string, pathParams []string) *OpenApiOperation {
+	operation := &OpenApiOperation{
+		Responses: map[string]*OpenApiResponse{
+			"200": {Description: http.StatusText(http.StatusOK)},
+		},
+	}
+	if handler.typedInfo == nil {
+		return operation
+	}
+	var (
+		reqType = handler.typedInfo.reqType.Elem()
+		meta    = gmeta.Data(reflect.New(reqType).Interface())
+	)
+	operation.Summary = gconv.String(meta["summary"])
+	operation.Description = gconv.String(meta[openApiTagDescription])
+	if operation.Description == "" {
+		operation.Description = gconv.String(meta[openApiTagDescriptionAlt])
+	}
+	if tags := gconv.String(meta["tags"]); tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			operation.Tags = append(operation.Tags, strings.TrimSpace(tag))
+		}
+	}
+	// Request parameters and body.
+	var (
+		isBody     = strings.Contains(openApiBodyMethods, strings.ToUpper(method))
+		bodySchema = &OpenApiSchema{
+			Type:       "object",
+			Properties: make(map[string]*OpenApiSchema),
+		}
+	)
+	for _, field := range getOpenApiStructFields(reqType) {
+		if isOpenApiIgnoredField(field) {
+			continue
+		}
+		var (
+			name             = getParamNameOfField(field)
+			schema, required = oai.getFieldSchema(field)
+			in               = strings.ToLower(field.Tag.Get(tagNameIn))
+		)
+		switch {
+		case in == openApiParamInHeader || in == openApiParamInCookie:
+			operation.Parameters = append(operation.Parameters, &OpenApiParameter{
+				Name:        name,
+				In:          in,
+				Description: schema.Description,
+				Required:    required,
+				Schema:      schema,
+			})
+		case isOpenApiPathParam(pathParams, name):
+			operation.Parameters = append(operation.Parameters, &OpenApiParameter{
+				Name:        name,
+				In:          openApiParamInPath,
+				Description: schema.Description,
+				Required:    true,
+				Schema:      schema,
+			})
+		case isBody:
+			bodySchema.Properties[name] = schema
+			if required {
+				bodySchema.Required = append(bodySchema.Required, name)
+			}
+		default:
+			operation.Parameters = append(operation.Parameters, &OpenApiParameter{
+				Name:        name,
+				In:          openApiParamInQuery,
+				Description: schema.Description,
+				Required:    required,
+				Schema:      schema,
+			})
+		}
+	}
+	if isBody && len(bodySchema.Properties) > 0 {
+		operation.RequestBody = &OpenApiRequestBody{
+			Required: len(bodySchema.Required) > 0,
+			Content: map[string]*OpenApiMediaType{
+				openApiContentTypeJson: {Schema: bodySchema},
+			},
+		}
+	}
+	// Response.
+	resSchema := oai.getSchema(handler.typedInfo.resType)
+	if s.config.HandlerResponse == nil {
+		resSchema = &OpenApiSchema{
+			Type: "object",
+			Properties: map[string]*OpenApiSchema{
+				"code":    {Type: "integer", Description: "Error code, which is 0 if no error."},
+				"message": {Type: "string", Description: "Error message, which is empty if no error."},
+				"data":    resSchema,
+			},
+		}
+	}
+	operation.Responses["200"].Content = map[string]*OpenApiMediaType{
+		openApiContentTypeJson: {Schema: resSchema},
+	}
+	return operation
+}
+
+// getSchema returns the schema of `reflectType`, the named struct of which is added to the components
+// of document and referred using "$ref".
+func (oai *OpenApi) getSchema(reflectType reflect.Type) *OpenApiSchema {
+	for reflectType.Kind() == reflect.Ptr {
+		reflectType = reflectType.Elem()
+	}
+	switch reflectType {
+	case reflectTypeTime, reflectTypeGTime:
+		return &OpenApiSchema{Type: "string", Format: "date-time"}
+	}
+	switch reflectType.Kind() {
+	case reflect.Bool:
+		return &OpenApiSchema{Type: "boolean"}
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenApiSchema{Type: "integer", Format: "int32"}
+
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &OpenApiSchema{Type: "integer", Format: "int64"}
+
+	case reflect.Float32:
+		return &OpenApiSchema{Type: "number", Format: "float"}
+
+	case reflect.Float64:
+		return &OpenApiSchema{Type: "number", Format: "double"}
+
+	case reflect.String:
+		return &OpenApiSchema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		if reflectType.Elem().Kind() == reflect.Uint8 {
+			return &OpenApiSchema{Type: "string", Format: "byte"}
+		}
+		return &OpenApiSchema{Type: "array", Items: oai.getSchema(reflectType.Elem())}
+
+	case reflect.Map:
+		return &OpenApiSchema{Type: "object", AdditionalProperties: oai.getSchema(reflectType.Elem())}
+
+	case reflect.Struct:
+		if reflectType.Name() == "" {
+			return oai.getStructSchema(reflectType)
+		}
+		name := getOpenApiSchemaName(reflectType)
+		if _, ok := oai.Components.Schemas[name]; !ok {
+			// Placeholder for recursive struct definition.
+			oai.Components.Schemas[name] = &OpenApiSchema{}
+			*oai.Components.Schemas[name] = *oai.getStructSchema(reflectType)
+		}
+		return &OpenApiSchema{Ref: openApiSchemaRefPrefix + name}
+	}
+	return &OpenApiSchema{}
+}
+
+// getStructSchema returns the object schema of struct type `reflectType`.
+func (oai *OpenApi) getStructSchema(reflectType reflect.Type) *OpenApiSchema {
+	schema := &OpenApiSchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenApiSchema),
+	}
+	for _, field := range getOpenApiStructFields(reflectType) {
+		name := getJsonNameOfField(field)
+		if name == "-" {
+			continue
+		}
+		fieldSchema, required := oai.getFieldSchema(field)
+		schema.Properties[name] = fieldSchema
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// getFieldSchema returns the schema of struct attribute `field` along with its description,
+// default value and validation rules, and whether it is required.
+func (oai *OpenApi) getFieldSchema(field reflect.StructField) (schema *OpenApiSchema, required bool) {
+	schema = oai.getSchema(field.Type)
+	rules := parseOpenApiValidationRules(field.Tag.Get(openApiTagValidation))
+	for _, rule := range rules {
+		if rule == "required" {
+			required = true
+		}
+	}
+	// Sibling attributes of "$ref" are ignored in OpenAPI 3.0.
+	if schema.Ref != "" {
+		return
+	}
+	schema.Description = field.Tag.Get(openApiTagDescription)
+	if schema.Description == "" {
+		schema.Description = field.Tag.Get(openApiTagDescriptionAlt)
+	}
+	for _, tag := range defaultValueTags {
+		if v, ok := field.Tag.Lookup(tag); ok {
+			schema.Default = convertOpenApiValue(schema, v)
+			break
+		}
+	}
+	for _, rule := range rules {
+		var (
+			array = strings.SplitN(rule, ":", 2)
+			name  = strings.TrimSpace(array[0])
+			args  []string
+		)
+		if len(array) > 1 {
+			args = strings.Split(array[1], ",")
+		}
+		switch name {
+		case "length", "between":
+			if len(args) == 2 {
+				if name == "length" {
+					schema.MinLength, schema.MaxLength = intPtr(args[0]), intPtr(args[1])
+				} else {
+					schema.Minimum, schema.Maximum = floatPtr(args[0]), floatPtr(args[1])
+				}
+			}
+		case "min-length":
+			if len(args) > 0 {
+				schema.MinLength = intPtr(args[0])
+			}
+		case "max-length":
+			if len(args) > 0 {
+				schema.MaxLength = intPtr(args[0])
+			}
+		case "min":
+			if len(args) > 0 {
+				schema.Minimum = floatPtr(args[0])
+			}
+		case "max":
+			if len(args) > 0 {
+				schema.Maximum = floatPtr(args[0])
+			}
+		case "in":
+			for _, v := range args {
+				schema.Enum = append(schema.Enum, convertOpenApiValue(schema, v))
+			}
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "date":
+			schema.Format = "date"
+		case "datetime":
+			schema.Format = "date-time"
+		case "ipv4", "ipv6":
+			schema.Format = name
+		}
+	}
+	return
+}
+
+// getOpenApiStructFields returns the exported attributes of struct type `reflectType`, in which the
+// attributes of embedded structs are merged and the gmeta.Meta attribute is ignored.
+func getOpenApiStructFields(reflectType reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, reflectType.NumField())
+	for i := 0; i < reflectType.NumField(); i++ {
+		field := reflectType.Field(i)
+		if field.Type == reflectTypeGmetaMeta {
+			continue
+		}
+		if field.Anonymous {
+			embeddedType := field.Type
+			for embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+				fields = append(fields, getOpenApiStructFields(embeddedType)...)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// isOpenApiIgnoredField checks and returns whether the request attribute `field` is ignored by its
+// parameter name tag of the highest priority, like `json:"-"`, which is not a parameter of the request.
+func isOpenApiIgnoredField(field reflect.StructField) bool {
+	for _, tag := range paramNameTags {
+		if v := field.Tag.Get(tag); v != "" {
+			return strings.TrimSpace(strings.Split(v, ",")[0]) == "-"
+		}
+	}
+	return false
+}
+
+// getJsonNameOfField returns the JSON name of struct attribute `field`,
+// which is specified by tag `json`, or else its attribute name.
+func getJsonNameOfField(field reflect.StructField) string {
+	if name := strings.TrimSpace(strings.Split(field.Tag.Get("json"), ",")[0]); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// getOpenApiSchemaName returns the component schema name of named type `reflectType`, like: "model.User".
+func getOpenApiSchemaName(reflectType reflect.Type) string {
+	name := reflectType.String()
+	if v, err := gregex.ReplaceString(`[^\w\.\-]`, "_", name); err == nil {
+		name = v
+	}
+	return name
+}
+
+// parseOpenApiValidationRules parses and returns the rule names with arguments from gvalid tag `tag`,
+// like: "name@required|length:6,16#Name is required|Name length should be between 6 and 16".
+func parseOpenApiValidationRules(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	if index := strings.Index(tag, "#"); index != -1 {
+		tag = tag[:index]
+	}
+	if index := strings.Index(tag, "@"); index != -1 {
+		tag = tag[index+1:]
+	}
+	rules := strings.Split(tag, "|")
+	for i, rule := range rules {
+		rules[i] = strings.TrimSpace(rule)
+	}
+	return rules
+}
+
+// convertRouteToOpenApiPath converts the route of server to path of OpenAPI, like:
+// "/user/:id" and "/user/*any" are converted to "/user/{id}" and "/user/{any}".
+func convertRouteToOpenApiPath(route string) string {
+	path, _ := gregex.ReplaceString(`[:\*](\w+)`, `{$1}`, route)
+	return path
+}
+
+// getOpenApiPathParams returns the parameter names of OpenAPI `path`.
+func getOpenApiPathParams(path string) []string {
+	names := make([]string, 0)
+	matches, _ := gregex.MatchAllString(`\{(\w+)\}`, path)
+	for _, match := range matches {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// isOpenApiPathParam checks whether `name` is one of the path parameter names `pathParams`.
+func isOpenApiPathParam(pathParams []string, name string) bool {
+	for _, v := range pathParams {
+		if strings.EqualFold(v, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOpenApiYamlPath checks whether the document `path` is in YAML format.
+func isOpenApiYamlPath(path string) bool {
+	switch gfile.ExtName(path) {
+	case "yaml", "yml":
+		return true
+	}
+	return false
+}
+
+// convertOpenApiValue converts string `value` to the type of `schema`.
+func convertOpenApiValue(schema *OpenApiSchema, value string) interface{} {
+	switch schema.Type {
+	case "integer":
+		return gconv.Int64(value)
+	case "number":
+		return gconv.Float64(value)
+	case "boolean":
+		return gconv.Bool(value)
+	}
+	return value
+}
+
+// intPtr converts string `s` to int and returns its pointer.
+func intPtr(s string) *int {
+	v := gconv.Int(strings.TrimSpace(s))
+	return &v
+}
+
+// floatPtr converts string `s` to float64 and returns its pointer.
+func floatPtr(s string) *float64 {
+	v := gconv.Float64(strings.TrimSpace(s))
+	return &v
+}
+
+// swaggerUITemplate is the Swagger UI page, which loads the assets from given base URL
+// and the OpenAPI document from given path.
+const swaggerUITemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API Document</title>
+<link rel="stylesheet" href="%s/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="%s/swagger-ui-bundle.js"></script>
+<script>
+window.onload = function() {
+	window.ui = SwaggerUIBundle({url: "%s", dom_id: "#swagger-ui"});
+};
+</script>
+</body>
+</html>`
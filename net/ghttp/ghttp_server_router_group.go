@@ -246,6 +246,18 @@ func (g *RouterGroup) Middleware(handlers ...HandlerFunc) *RouterGroup {
 	return g
 }
 
+// RateLimit binds the rate limiting middleware with `config` to the router group,
+// each route of which has its own counters.
+func (g *RouterGroup) RateLimit(config RateLimitConfig) *RouterGroup {
+	return g.Middleware(MiddlewareRateLimit(config))
+}
+
+// ConcurrencyLimit binds the middleware limiting the concurrent in-flight requests to `max`
+// to the router group, each route of which has its own limit.
+func (g *RouterGroup) ConcurrencyLimit(max int, handler ...HandlerFunc) *RouterGroup {
+	return g.Middleware(MiddlewareConcurrencyLimit(max, handler...))
+}
+
 // preBindToLocalArray adds the route registering parameters to internal variable array for lazily registering feature.
 func (g *RouterGroup) preBindToLocalArray(bindType string, pattern string, object interface{}, params ...interface{}) *RouterGroup {
 	_, file, line := gdebug.CallerWithFilter(stackFilterKey)
@@ -315,6 +327,12 @@ func (g *RouterGroup) doBindRoutersToServer(item *preBindItem) *RouterGroup {
 			} else {
 				g.domain.doBindHandler(pattern, h, g.middleware, source)
 			}
+		} else if isTypedHandler(object) {
+			if g.server != nil {
+				g.server.doBindHandler(pattern, object, g.middleware, source)
+			} else {
+				g.domain.doBindHandler(pattern, object, g.middleware, source)
+			}
 		} else if g.isController(object) {
 			if len(extras) > 0 {
 				if g.server != nil {
@@ -15,7 +15,14 @@ import (
 )
 
 // BindHandler registers a handler function to server with given pattern.
-func (s *Server) BindHandler(pattern string, handler HandlerFunc) {
+//
+// The parameter `handler` can be type of HandlerFunc: func(r *ghttp.Request), or typed handler like:
+// func(ctx context.Context, req *XxxReq) (res *XxxRes, err error).
+// The request struct of typed handler is parsed from path, query, form and body parameters, and the
+// attributes having tag `in:"header"` or `in:"cookie"` are parsed from headers or cookies. It is then
+// validated using the `v` tags of gvalid. The result or error of typed handler is written to response
+// using HandlerResponse of server configuration.
+func (s *Server) BindHandler(pattern string, handler interface{}) {
 	s.doBindHandler(pattern, handler, nil, "")
 }
 
@@ -23,13 +30,19 @@ func (s *Server) BindHandler(pattern string, handler HandlerFunc) {
 // The parameter <pattern> is like:
 // /user/list, put:/user, delete:/user, post:/user@goframe.org
 func (s *Server) doBindHandler(
-	pattern string, handler HandlerFunc,
+	pattern string, handler interface{},
 	middleware []HandlerFunc, source string,
 ) {
+	itemFunc, typedInfo, err := checkAndCreateHandlerFunc(handler)
+	if err != nil {
+		s.Logger().Fatal(err)
+		return
+	}
 	s.setHandler(pattern, &handlerItem{
 		itemName:   gdebug.FuncPath(handler),
 		itemType:   handlerTypeHandler,
-		itemFunc:   handler,
+		itemFunc:   itemFunc,
+		typedInfo:  typedInfo,
 		middleware: middleware,
 		source:     source,
 	})
@@ -0,0 +1,260 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/internal/structs"
+	"github.com/gogf/gf/util/gconv"
+	"github.com/gogf/gf/util/gvalid"
+)
+
+type (
+	// HandlerResponseFunc writes the result `res` or the error `err` of typed handler to response.
+	// Either `res` or `err` is nil.
+	HandlerResponseFunc = func(r *Request, res interface{}, err error)
+
+	// HandlerErrorStatusFunc maps the error of typed handler to HTTP status code.
+	HandlerErrorStatusFunc = func(err error) int
+
+	// DefaultHandlerResponse is the default response envelope for typed handlers.
+	DefaultHandlerResponse struct {
+		Code    int         `json:"code"`    // Error code, which is 0 if no error.
+		Message string      `json:"message"` // Error message, which is empty if no error.
+		Data    interface{} `json:"data"`    // Result data of the handler.
+	}
+
+	// handlerTypedInfo is the information of typed handler like: func(context.Context, *Req) (*Res, error).
+	handlerTypedInfo struct {
+		value   reflect.Value // Reflect value of the handler function.
+		reqType reflect.Type  // Type of the request struct, like: *Req.
+		resType reflect.Type  // Type of the response, like: *Res.
+	}
+)
+
+const (
+	// tagNameIn is the struct tag name specifying the input source of request attribute,
+	// which can be "header" or "cookie". The attribute is parsed from parameters of path,
+	// query, form and body if it has no such tag.
+	tagNameIn = "in"
+)
+
+var (
+	// reflectTypeContext is the reflect type of interface context.Context.
+	reflectTypeContext = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	// reflectTypeError is the reflect type of interface error.
+	reflectTypeError = reflect.TypeOf((*error)(nil)).Elem()
+
+	// paramNameTags is the struct tag names for parameter name, ordered by priority.
+	paramNameTags = []string{"p", "param", "params", "json"}
+)
+
+// checkAndCreateHandlerFunc checks the given `handler` and returns the HandlerFunc for it.
+// The parameter `handler` can be type of HandlerFunc or typed handler like:
+// func(ctx context.Context, req *XxxReq) (res *XxxRes, err error).
+// The returned `info` is nil if `handler` is type of HandlerFunc.
+func checkAndCreateHandlerFunc(handler interface{}) (f HandlerFunc, info *handlerTypedInfo, err error) {
+	if h, ok := handler.(HandlerFunc); ok {
+		return h, nil, nil
+	}
+	if info, err = getHandlerTypedInfo(handler); err != nil {
+		return nil, nil, err
+	}
+	return info.createHandlerFunc(), info, nil
+}
+
+// isTypedHandler checks and returns whether given `handler` is a typed handler.
+func isTypedHandler(handler interface{}) bool {
+	if handler == nil {
+		return false
+	}
+	if reflect.TypeOf(handler).Kind() != reflect.Func {
+		return false
+	}
+	_, err := getHandlerTypedInfo(handler)
+	return err == nil
+}
+
+// getHandlerTypedInfo checks and returns the typed handler information of `handler`.
+func getHandlerTypedInfo(handler interface{}) (*handlerTypedInfo, error) {
+	reflectValue := reflect.ValueOf(handler)
+	if reflectValue.Kind() != reflect.Func || reflectValue.IsNil() {
+		return nil, gerror.Newf(`invalid handler type "%T", function is required`, handler)
+	}
+	reflectType := reflectValue.Type()
+	if reflectType.NumIn() != 2 || reflectType.NumOut() != 2 {
+		return nil, gerror.Newf(
+			`invalid handler "%s", which should be like: func(context.Context, *XxxReq) (*XxxRes, error)`,
+			reflectType.String(),
+		)
+	}
+	if reflectType.In(0) != reflectTypeContext {
+		return nil, gerror.Newf(
+			`invalid handler "%s", the first input parameter should be type of context.Context`,
+			reflectType.String(),
+		)
+	}
+	if reqType := reflectType.In(1); reqType.Kind() != reflect.Ptr || reqType.Elem().Kind() != reflect.Struct {
+		return nil, gerror.Newf(
+			`invalid handler "%s", the second input parameter should be type of struct pointer`,
+			reflectType.String(),
+		)
+	}
+	if reflectType.Out(1) != reflectTypeError {
+		return nil, gerror.Newf(
+			`invalid handler "%s", the last output parameter should be type of error`,
+			reflectType.String(),
+		)
+	}
+	return &handlerTypedInfo{
+		value:   reflectValue,
+		reqType: reflectType.In(1),
+		resType: reflectType.Out(0),
+	}, nil
+}
+
+// createHandlerFunc creates and returns a HandlerFunc, which parses the request to the request struct,
+// calls the typed handler and writes the result using the handler response of server.
+func (info *handlerTypedInfo) createHandlerFunc() HandlerFunc {
+	return func(r *Request) {
+		var (
+			err     error
+			res     interface{}
+			reqPtr  = reflect.New(info.reqType.Elem())
+			results []reflect.Value
+		)
+		if err = r.parseTypedRequest(reqPtr.Interface()); err != nil {
+			err = gerror.WrapCode(http.StatusBadRequest, err, "")
+		} else {
+			results = info.value.Call([]reflect.Value{reflect.ValueOf(r.Context()), reqPtr})
+			if v := results[1].Interface(); v != nil {
+				err = v.(error)
+			} else if !isNilValue(results[0]) {
+				res = results[0].Interface()
+			}
+		}
+		if r.IsExited() {
+			return
+		}
+		r.Server.getHandlerResponse()(r, res, err)
+	}
+}
+
+// parseTypedRequest parses the request parameters, headers and cookies to `pointer`
+// and validates it using gvalid.
+// The attributes are parsed from headers or cookies if they have tag `in:"header"` or `in:"cookie"`.
+func (r *Request) parseTypedRequest(pointer interface{}) error {
+	data := r.GetRequestMap()
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	tagFields, err := structs.TagFields(pointer, []string{tagNameIn})
+	if err != nil {
+		return err
+	}
+	for _, field := range tagFields {
+		var (
+			name  = getParamNameOfField(field.Field)
+			value string
+		)
+		switch strings.ToLower(field.TagValue) {
+		case "header":
+			value = r.GetHeader(name)
+		case "cookie":
+			value = r.Cookie.Get(name)
+		}
+		if value != "" {
+			data[name] = value
+		}
+	}
+	if err = r.mergeDefaultStructValue(data, pointer); err != nil {
+		return err
+	}
+	if err = gconv.Struct(data, pointer); err != nil {
+		return err
+	}
+	if e := gvalid.CheckStruct(pointer, nil); e != nil {
+		return e
+	}
+	return nil
+}
+
+// getParamNameOfField returns the parameter name of struct attribute `field`, which is specified by
+// tag like: `p:"name"`, `json:"name"`, or else its attribute name.
+func getParamNameOfField(field reflect.StructField) string {
+	for _, tag := range paramNameTags {
+		if v := field.Tag.Get(tag); v != "" {
+			if name := strings.TrimSpace(strings.Split(v, ",")[0]); name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
+
+// getHandlerResponse returns the handler response function of server.
+func (s *Server) getHandlerResponse() HandlerResponseFunc {
+	if s.config.HandlerResponse != nil {
+		return s.config.HandlerResponse
+	}
+	return s.defaultHandlerResponse
+}
+
+// getHandlerErrorStatus returns the handler error status function of server.
+func (s *Server) getHandlerErrorStatus() HandlerErrorStatusFunc {
+	if s.config.HandlerErrorStatus != nil {
+		return s.config.HandlerErrorStatus
+	}
+	return DefaultHandlerErrorStatus
+}
+
+// defaultHandlerResponse writes the result of typed handler using DefaultHandlerResponse as JSON,
+// along with the HTTP status code mapped by the handler error status function of server.
+func (s *Server) defaultHandlerResponse(r *Request, res interface{}, err error) {
+	if err != nil {
+		code := gerror.Code(err)
+		if code == -1 {
+			code = http.StatusInternalServerError
+		}
+		r.Response.WriteHeader(s.getHandlerErrorStatus()(err))
+		r.Response.WriteJson(DefaultHandlerResponse{
+			Code:    code,
+			Message: err.Error(),
+		})
+		return
+	}
+	r.Response.WriteJson(DefaultHandlerResponse{
+		Data: res,
+	})
+}
+
+// DefaultHandlerErrorStatus maps `err` to HTTP status code using its error code retrieved by gerror.Code.
+// It returns the error code if it is a valid HTTP error status code, or else http.StatusInternalServerError.
+func DefaultHandlerErrorStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if code := gerror.Code(err); code >= 400 && code < 600 {
+		return code
+	}
+	return http.StatusInternalServerError
+}
+
+// isNilValue checks whether the reflect value `v` is nil.
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
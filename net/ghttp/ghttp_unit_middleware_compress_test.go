@@ -0,0 +1,223 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/net/ghttp"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/test/gtest"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// compressTestEncoder ignores the content and writes "encoded" when closed.
+type compressTestEncoder struct {
+	writer io.Writer
+}
+
+func (e *compressTestEncoder) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (e *compressTestEncoder) Close() error {
+	_, err := e.writer.Write([]byte("encoded"))
+	return err
+}
+
+func gzipDecode(data []byte) string {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	content, _ := ioutil.ReadAll(reader)
+	return string(content)
+}
+
+func Test_Middleware_Compress(t *testing.T) {
+	var (
+		p, _    = ports.PopRand()
+		s       = g.Server(p)
+		content = gstr.Repeat("hello world ", 200)
+	)
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		group.Middleware(ghttp.MiddlewareCompress)
+		group.ALL("/text", func(r *ghttp.Request) {
+			r.Response.Write(content)
+		})
+		group.ALL("/small", func(r *ghttp.Request) {
+			r.Response.Write("hello")
+		})
+		group.ALL("/image", func(r *ghttp.Request) {
+			r.Response.Header().Set("Content-Type", "image/png")
+			r.Response.Write(content)
+		})
+	})
+	s.BindHandler("/plain", func(r *ghttp.Request) {
+		r.Response.Write(content)
+	})
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+	time.Sleep(100 * time.Millisecond)
+
+	prefix := fmt.Sprintf("http://127.0.0.1:%d", p)
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "deflate;q=0.5, gzip")
+		resp, err := client.Get("/text")
+		t.Assert(err, nil)
+		defer resp.Close()
+		data := resp.ReadAll()
+		t.Assert(resp.Header.Get("Content-Encoding"), "gzip")
+		t.Assert(resp.Header.Get("Vary"), "Accept-Encoding")
+		t.Assert(resp.Header.Get("Content-Length"), len(data))
+		t.Assert(gstr.HasPrefix(resp.Header.Get("Content-Type"), "text/plain"), true)
+		t.Assert(gzipDecode(data), content)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "gzip;q=0.5, deflate")
+		resp, err := client.Get("/text")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Encoding"), "deflate")
+		reader, err := zlib.NewReader(bytes.NewReader(resp.ReadAll()))
+		t.Assert(err, nil)
+		data, _ := ioutil.ReadAll(reader)
+		t.Assert(string(data), content)
+	})
+	// Min size.
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "gzip")
+		resp, err := client.Get("/small")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Encoding"), "")
+		t.Assert(resp.ReadAllString(), "hello")
+	})
+	// Content type not allowed.
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "gzip")
+		resp, err := client.Get("/image")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Encoding"), "")
+		t.Assert(resp.Header.Get("Vary"), "")
+		t.Assert(resp.ReadAllString(), content)
+	})
+	// Encoding not accepted.
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "gzip;q=0, identity")
+		resp, err := client.Get("/text")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Encoding"), "")
+		t.Assert(resp.Header.Get("Vary"), "Accept-Encoding")
+		t.Assert(resp.ReadAllString(), content)
+	})
+	// Route without compression middleware.
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "gzip")
+		resp, err := client.Get("/plain")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Encoding"), "")
+		t.Assert(resp.ReadAllString(), content)
+	})
+}
+
+func Test_Middleware_Compress_Encoder(t *testing.T) {
+	ghttp.RegisterCompressEncoder("test", func(w io.Writer, level int) (io.WriteCloser, error) {
+		return &compressTestEncoder{w}, nil
+	})
+	var (
+		p, _    = ports.PopRand()
+		s       = g.Server(p)
+		content = gstr.Repeat("hello world ", 200)
+	)
+	s.BindHandler("/", func(r *ghttp.Request) {
+		r.Response.Write(content)
+	})
+	s.SetCompressEnabled(true)
+	s.SetCompressEncodings("test", "gzip")
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+	time.Sleep(100 * time.Millisecond)
+
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+		resp, err := client.SetHeader("Accept-Encoding", "gzip, test").Get("/")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Encoding"), "test")
+		t.Assert(resp.ReadAllString(), "encoded")
+	})
+}
+
+func Test_Static_Compress(t *testing.T) {
+	var (
+		p, _    = ports.PopRand()
+		s       = g.Server(p)
+		path    = fmt.Sprintf(`%s/ghttp/static/compress/%d`, gfile.TempDir(), p)
+		content = gstr.Repeat("body { color: red; } ", 200)
+		buffer  = bytes.NewBuffer(nil)
+		writer  = gzip.NewWriter(buffer)
+	)
+	defer gfile.Remove(path)
+	writer.Write([]byte("precompressed"))
+	writer.Close()
+	gfile.PutContents(path+"/index.css", content)
+	gfile.PutContents(path+"/style.css", content)
+	gfile.PutBytes(path+"/style.css.gz", buffer.Bytes())
+	s.SetServerRoot(path)
+	s.SetCompressEnabled(true)
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+	time.Sleep(100 * time.Millisecond)
+
+	prefix := fmt.Sprintf("http://127.0.0.1:%d", p)
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "gzip")
+		resp, err := client.Get("/style.css")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Encoding"), "gzip")
+		t.Assert(resp.Header.Get("Vary"), "Accept-Encoding")
+		t.Assert(gstr.HasPrefix(resp.Header.Get("Content-Type"), "text/css"), true)
+		t.Assert(gzipDecode(resp.ReadAll()), "precompressed")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "gzip")
+		resp, err := client.Get("/index.css")
+		t.Assert(err, nil)
+		defer resp.Close()
+		data := resp.ReadAll()
+		t.Assert(resp.Header.Get("Content-Encoding"), "gzip")
+		t.AssertIN(resp.Header.Get("Content-Length"), g.Slice{"", len(data)})
+		t.Assert(gzipDecode(data), content)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Accept-Encoding", "identity")
+		resp, err := client.Get("/style.css")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Encoding"), "")
+		t.Assert(resp.ReadAllString(), content)
+	})
+}
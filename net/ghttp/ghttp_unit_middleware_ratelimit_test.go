@@ -0,0 +1,173 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/container/garray"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/net/ghttp"
+	"github.com/gogf/gf/test/gtest"
+)
+
+func Test_Middleware_RateLimit(t *testing.T) {
+	p, _ := ports.PopRand()
+	s := g.Server(p)
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		group.RateLimit(ghttp.RateLimitConfig{
+			Limit:  2,
+			Period: time.Minute,
+		})
+		group.ALL("/token", func(r *ghttp.Request) {
+			r.Response.Write("token")
+		})
+		group.ALL("/other", func(r *ghttp.Request) {
+			r.Response.Write("other")
+		})
+	})
+	s.Group("/window", func(group *ghttp.RouterGroup) {
+		group.RateLimit(ghttp.RateLimitConfig{
+			Limit:     1,
+			Period:    time.Minute,
+			Algorithm: ghttp.RateLimitSlidingWindow,
+			KeyFunc:   ghttp.RateLimitKeyByHeader("X-Api-Key"),
+		})
+		group.ALL("/", func(r *ghttp.Request) {
+			r.Response.Write("window")
+		})
+	})
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+	time.Sleep(100 * time.Millisecond)
+
+	prefix := fmt.Sprintf("http://127.0.0.1:%d", p)
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix)
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get("/token")
+			t.Assert(err, nil)
+			t.Assert(resp.StatusCode, http.StatusOK)
+			t.Assert(resp.Header.Get("X-RateLimit-Limit"), 2)
+			t.Assert(resp.Header.Get("X-RateLimit-Remaining"), 1-i)
+			t.Assert(resp.ReadAllString(), "token")
+			resp.Close()
+		}
+		resp, err := client.Get("/token")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.StatusCode, http.StatusTooManyRequests)
+		t.Assert(resp.Header.Get("X-RateLimit-Remaining"), 0)
+		t.Assert(resp.Header.Get("Retry-After"), 30)
+		t.Assert(resp.Header.Get("X-RateLimit-Reset"), 60)
+
+		// Each route has its own counters.
+		t.Assert(client.GetContent("/other"), "other")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix)
+		t.Assert(client.Header(g.MapStrStr{"X-Api-Key": "1"}).GetContent("/window"), "window")
+		t.Assert(client.Header(g.MapStrStr{"X-Api-Key": "2"}).GetContent("/window"), "window")
+		resp, err := client.Header(g.MapStrStr{"X-Api-Key": "1"}).Get("/window")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.StatusCode, http.StatusTooManyRequests)
+		t.AssertNE(resp.Header.Get("Retry-After"), "")
+	})
+}
+
+func Test_Middleware_ConcurrencyLimit(t *testing.T) {
+	p, _ := ports.PopRand()
+	s := g.Server(p)
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		group.ConcurrencyLimit(1)
+		group.ALL("/", func(r *ghttp.Request) {
+			time.Sleep(500 * time.Millisecond)
+			r.Response.Write("ok")
+		})
+	})
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+	time.Sleep(100 * time.Millisecond)
+
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			wg       = sync.WaitGroup{}
+			statuses = garray.NewIntArray(true)
+			client   = g.Client().SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+		)
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp, err := client.Get("/"); err == nil {
+					statuses.Append(resp.StatusCode)
+					resp.Close()
+				}
+			}()
+		}
+		wg.Wait()
+		t.Assert(statuses.Len(), 3)
+		t.Assert(statuses.Contains(http.StatusOK), true)
+		t.Assert(statuses.Contains(http.StatusServiceUnavailable), true)
+		// The counter is released after the requests.
+		t.Assert(client.GetContent("/"), "ok")
+	})
+}
+
+func Test_RateLimitStoreMemory(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx   = context.Background()
+			store = ghttp.NewRateLimitStoreMemory()
+		)
+		for i := 0; i < 3; i++ {
+			result, err := store.Take(ctx, "token", 3, time.Second, ghttp.RateLimitTokenBucket)
+			t.Assert(err, nil)
+			t.Assert(result.Allowed, true)
+			t.Assert(result.Remaining, 2-i)
+		}
+		result, err := store.Take(ctx, "token", 3, time.Second, ghttp.RateLimitTokenBucket)
+		t.Assert(err, nil)
+		t.Assert(result.Allowed, false)
+		t.Assert(result.RetryAfter > 0 && result.RetryAfter <= time.Second/3, true)
+
+		// Refilled tokens.
+		time.Sleep(400 * time.Millisecond)
+		result, err = store.Take(ctx, "token", 3, time.Second, ghttp.RateLimitTokenBucket)
+		t.Assert(err, nil)
+		t.Assert(result.Allowed, true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx   = context.Background()
+			store = ghttp.NewRateLimitStoreMemory()
+		)
+		for i := 0; i < 2; i++ {
+			result, err := store.Take(ctx, "window", 2, time.Hour, ghttp.RateLimitSlidingWindow)
+			t.Assert(err, nil)
+			t.Assert(result.Allowed, true)
+			t.Assert(result.Remaining, 1-i)
+		}
+		result, err := store.Take(ctx, "window", 2, time.Hour, ghttp.RateLimitSlidingWindow)
+		t.Assert(err, nil)
+		t.Assert(result.Allowed, false)
+		t.Assert(result.RetryAfter > 0, true)
+
+		_, err = store.Take(ctx, "window", 2, time.Hour, "unknown")
+		t.AssertNE(err, nil)
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/encoding/gjson"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/net/ghttp"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/test/gtest"
+	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gmeta"
+)
+
+type openApiUserCreateReq struct {
+	gmeta.Meta `summary:"Create user" tags:"user"`
+	Token      string `p:"token" in:"header" v:"required"`
+	Passport   string `json:"passport" v:"required|length:6,16" dc:"User passport"`
+	Age        int    `json:"age" v:"between:1,200" d:"18"`
+	Gender     string `json:"gender" v:"in:male,female"`
+	Internal   string `json:"-"`
+}
+
+type openApiUserGetReq struct {
+	Id     int `p:"id" v:"required"`
+	Detail bool
+	Secret string `json:"-"`
+}
+
+type openApiUser struct {
+	Id       int              `json:"id"`
+	Passport string           `json:"passport"`
+	Friends  []*openApiUser   `json:"friends"`
+	Extra    map[string]int64 `json:"extra"`
+}
+
+func Test_OpenApi(t *testing.T) {
+	p, _ := ports.PopRand()
+	s := g.Server(p)
+	s.Group("/api", func(group *ghttp.RouterGroup) {
+		group.POST("/user", func(ctx context.Context, req *openApiUserCreateReq) (*openApiUser, error) {
+			return &openApiUser{Passport: req.Passport}, nil
+		})
+		group.GET("/user/{id}", func(ctx context.Context, req *openApiUserGetReq) (*openApiUser, error) {
+			return &openApiUser{Id: req.Id}, nil
+		})
+		group.ALL("/ping", func(r *ghttp.Request) {
+			r.Response.Write("pong")
+		})
+	})
+	s.SetOpenApiPath("/api.json")
+	s.SetSwaggerPath("/swagger")
+	s.SetOpenApiInfo("User API", "2.0.0")
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(100 * time.Millisecond)
+	gtest.C(t, func(t *gtest.T) {
+		c := g.Client()
+		c.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		j, err := gjson.LoadContent(c.GetBytes("/api.json"))
+		t.AssertNil(err)
+		t.Assert(j.GetString("openapi"), "3.0.3")
+		t.Assert(j.GetString("info.title"), "User API")
+		t.Assert(j.GetString("info.version"), "2.0.0")
+		t.Assert(j.Contains("paths./api.json"), false)
+		t.Assert(j.Contains("paths./swagger"), false)
+
+		// POST /api/user.
+		t.Assert(j.GetString("paths./api/user.post.summary"), "Create user")
+		t.Assert(j.GetStrings("paths./api/user.post.tags"), g.Slice{"user"})
+		t.Assert(j.GetString("paths./api/user.post.parameters.0.name"), "token")
+		t.Assert(j.GetString("paths./api/user.post.parameters.0.in"), "header")
+		t.Assert(j.GetBool("paths./api/user.post.parameters.0.required"), true)
+		body := j.GetJson("paths./api/user.post.requestBody.content.application/json.schema")
+		t.Assert(body.GetStrings("required"), g.Slice{"passport"})
+		t.Assert(body.GetString("properties.passport.description"), "User passport")
+		t.Assert(body.GetInt("properties.passport.minLength"), 6)
+		t.Assert(body.GetInt("properties.passport.maxLength"), 16)
+		t.Assert(body.GetInt("properties.age.default"), 18)
+		t.Assert(body.GetInt("properties.age.maximum"), 200)
+		t.Assert(body.GetStrings("properties.gender.enum"), g.Slice{"male", "female"})
+		t.Assert(body.Contains("properties.Internal"), false)
+		t.Assert(body.Contains("properties.-"), false)
+		t.Assert(
+			j.GetString("paths./api/user.post.responses.200.content.application/json.schema.properties.data.$ref"),
+			"#/components/schemas/ghttp_test.openApiUser",
+		)
+
+		// GET /api/user/{id}.
+		t.Assert(j.GetString("paths./api/user/{id}.get.parameters.0.name"), "id")
+		t.Assert(j.GetString("paths./api/user/{id}.get.parameters.0.in"), "path")
+		t.Assert(j.GetString("paths./api/user/{id}.get.parameters.0.schema.type"), "integer")
+		t.Assert(j.GetString("paths./api/user/{id}.get.parameters.1.name"), "Detail")
+		t.Assert(j.GetString("paths./api/user/{id}.get.parameters.1.in"), "query")
+		t.Assert(j.GetString("paths./api/user/{id}.get.parameters.1.schema.type"), "boolean")
+		t.Assert(len(j.GetArray("paths./api/user/{id}.get.parameters")), 2)
+
+		// ALL /api/ping.
+		t.Assert(j.GetString("paths./api/ping.get.responses.200.description"), "OK")
+		t.Assert(j.GetString("paths./api/ping.patch.responses.200.description"), "OK")
+
+		// Components.
+		user := s.GetOpenApi().Components.Schemas["ghttp_test.openApiUser"]
+		t.AssertNE(user, nil)
+		t.Assert(user.Type, "object")
+		t.Assert(user.Properties["friends"].Items.Ref, "#/components/schemas/ghttp_test.openApiUser")
+		t.Assert(user.Properties["extra"].AdditionalProperties.Format, "int64")
+
+		// Swagger UI.
+		content := c.GetContent("/swagger")
+		t.Assert(gstr.Contains(content, `url: "/api.json"`), true)
+		t.Assert(gstr.Contains(content, `src="https://unpkg.com/swagger-ui-dist@3/swagger-ui-bundle.js"`), true)
+		s.SetSwaggerAssetsUrl("/swagger-ui/")
+		defer s.SetSwaggerAssetsUrl("")
+		content = c.GetContent("/swagger")
+		t.Assert(gstr.Contains(content, `href="/swagger-ui/swagger-ui.css"`), true)
+		t.Assert(gstr.Contains(content, `src="/swagger-ui/swagger-ui-bundle.js"`), true)
+	})
+	// Export.
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.TempDir(fmt.Sprintf("openapi_%d.yaml", p))
+		defer gfile.Remove(path)
+		t.AssertNil(s.ExportOpenApi(path))
+		j, err := gjson.LoadContent(gfile.GetBytes(path))
+		t.AssertNil(err)
+		t.Assert(j.GetString("info.title"), "User API")
+		t.Assert(j.GetString("paths./api/user/{id}.get.parameters.0.in"), "path")
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/net/ghttp"
+	"github.com/gogf/gf/test/gtest"
+	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gconv"
+)
+
+func Test_Response_SSE(t *testing.T) {
+	var (
+		p, _   = ports.PopRand()
+		s      = g.Server(p)
+		errors = make(chan error, 1)
+	)
+	s.BindHandler("/events", func(r *ghttp.Request) {
+		sse := r.Response.SSE()
+		sse.Retry(3 * time.Second)
+		start := gconv.Int(r.GetLastEventId())
+		sse.Send("", gconv.String(start+1), "hello\nworld")
+		sse.Send("user", gconv.String(start+2), g.Map{"name": "john"})
+	})
+	s.BindHandler("/keepalive", func(r *ghttp.Request) {
+		sse := r.Response.SSE().KeepAlive(50 * time.Millisecond)
+		time.Sleep(120 * time.Millisecond)
+		sse.Send("", "", "done")
+	})
+	s.BindHandler("/stream", func(r *ghttp.Request) {
+		sse := r.Response.SSE()
+		for i := 0; ; i++ {
+			if err := sse.Send("", gconv.String(i), "tick"); err != nil {
+				errors <- err
+				return
+			}
+			select {
+			case <-sse.Done():
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	})
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+	time.Sleep(100 * time.Millisecond)
+
+	prefix := fmt.Sprintf("http://127.0.0.1:%d", p)
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client().SetPrefix(prefix).SetHeader("Last-Event-ID", "10")
+		resp, err := client.Get("/events")
+		t.Assert(err, nil)
+		defer resp.Close()
+		t.Assert(resp.Header.Get("Content-Type"), "text/event-stream; charset=utf-8")
+		t.Assert(resp.Header.Get("Cache-Control"), "no-cache")
+		t.Assert(resp.ReadAllString(), "retry: 3000\n\n"+
+			"id: 11\ndata: hello\ndata: world\n\n"+
+			"id: 12\nevent: user\ndata: {\"name\":\"john\"}\n\n",
+		)
+		t.Assert(g.Client().SetPrefix(prefix).GetContent("/events?lastEventId=1"), "retry: 3000\n\n"+
+			"id: 2\ndata: hello\ndata: world\n\n"+
+			"id: 3\nevent: user\ndata: {\"name\":\"john\"}\n\n",
+		)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		content := g.Client().SetPrefix(prefix).GetContent("/keepalive")
+		t.Assert(gstr.Count(content, ": keep-alive\n\n"), 2)
+		t.Assert(gstr.HasSuffix(content, "data: done\n\n"), true)
+	})
+	// Client disconnection.
+	gtest.C(t, func(t *gtest.T) {
+		resp, err := http.Get(prefix + "/stream")
+		t.Assert(err, nil)
+		reader := bufio.NewReader(resp.Body)
+		line, err := reader.ReadString('\n')
+		t.Assert(err, nil)
+		t.Assert(line, "id: 0\n")
+		resp.Body.Close()
+		select {
+		case err = <-errors:
+			t.AssertNE(err, nil)
+		case <-time.After(3 * time.Second):
+			t.Error("disconnection not detected")
+		}
+	})
+}
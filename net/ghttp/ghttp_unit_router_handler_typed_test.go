@@ -0,0 +1,123 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/errors/gerror"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/net/ghttp"
+	"github.com/gogf/gf/test/gtest"
+)
+
+type typedUserReq struct {
+	Id    int    `v:"required|min:1#id is required|id should be greater than 0"`
+	Name  string `p:"name"`
+	Token string `p:"X-Token" in:"header"`
+}
+
+type typedUserRes struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+func typedUserHandler(ctx context.Context, req *typedUserReq) (*typedUserRes, error) {
+	if req.Id == 404 {
+		return nil, gerror.NewCode(404, "user not found")
+	}
+	if req.Id == 500 {
+		return nil, gerror.New("internal error")
+	}
+	return &typedUserRes{
+		Id:    req.Id,
+		Name:  req.Name,
+		Token: req.Token,
+	}, nil
+}
+
+func Test_Router_Handler_Typed(t *testing.T) {
+	p, _ := ports.PopRand()
+	s := g.Server(p)
+	s.BindHandler("/user/{id}", typedUserHandler)
+	s.Group("/group", func(group *ghttp.RouterGroup) {
+		group.POST("/user", typedUserHandler)
+	})
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(100 * time.Millisecond)
+	gtest.C(t, func(t *gtest.T) {
+		c := g.Client()
+		c.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+		c.SetHeader("X-Token", "token")
+
+		t.Assert(
+			c.GetContent("/user/1?name=john"),
+			`{"code":0,"message":"","data":{"id":1,"name":"john","token":"token"}}`,
+		)
+		t.Assert(
+			c.ContentJson().PostContent("/group/user", g.Map{"id": 2, "name": "smith"}),
+			`{"code":0,"message":"","data":{"id":2,"name":"smith","token":"token"}}`,
+		)
+
+		r, err := c.Get("/user/0")
+		t.AssertNil(err)
+		t.Assert(r.StatusCode, 400)
+		t.Assert(r.ReadAllString(), `{"code":400,"message":"id should be greater than 0","data":null}`)
+		r.Close()
+
+		r, err = c.Get("/user/404")
+		t.AssertNil(err)
+		t.Assert(r.StatusCode, 404)
+		t.Assert(r.ReadAllString(), `{"code":404,"message":"user not found","data":null}`)
+		r.Close()
+
+		r, err = c.Get("/user/500")
+		t.AssertNil(err)
+		t.Assert(r.StatusCode, 500)
+		t.Assert(r.ReadAllString(), `{"code":500,"message":"internal error","data":null}`)
+		r.Close()
+	})
+}
+
+func Test_Router_Handler_Typed_Response(t *testing.T) {
+	p, _ := ports.PopRand()
+	s := g.Server(p)
+	s.SetHandlerResponse(func(r *ghttp.Request, res interface{}, err error) {
+		if err != nil {
+			r.Response.WriteStatus(418, err.Error())
+			return
+		}
+		r.Response.WriteJson(res)
+	})
+	s.BindHandler("/user", typedUserHandler)
+	s.SetPort(p)
+	s.SetDumpRouterMap(false)
+	s.Start()
+	defer s.Shutdown()
+
+	time.Sleep(100 * time.Millisecond)
+	gtest.C(t, func(t *gtest.T) {
+		c := g.Client()
+		c.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+
+		t.Assert(c.GetContent("/user?id=1&name=john"), `{"id":1,"name":"john","token":""}`)
+
+		r, err := c.Get("/user?id=404")
+		t.AssertNil(err)
+		t.Assert(r.StatusCode, 418)
+		t.Assert(r.ReadAllString(), `user not found`)
+		r.Close()
+	})
+}